@@ -23,12 +23,21 @@ import (
 
 //StartControllerDriver main function to start CSI Controller Driver
 func StartControllerDriver(csioptions csioptions.CSIOptions) {
+	startControllerDriver("BV", csioptions.Endpoint, csioptions, driver.BlockVolumeDriverName, driver.BlockVolumeDriverVersion)
+}
+
+//StartFSSControllerDriver main function to start the FSS CSI Controller Driver
+func StartFSSControllerDriver(csioptions csioptions.CSIOptions) {
+	startControllerDriver("FSS", csioptions.FssEndpoint, csioptions, driver.FSSDriverName, driver.FSSDriverVersion)
+}
+
+func startControllerDriver(name, endpoint string, csioptions csioptions.CSIOptions, driverName, driverVersion string) {
 
 	logger := logging.Logger().Sugar()
 	logger.Sync()
 
-	drv, err := driver.NewControllerDriver(logger.Named("BV").With(zap.String("component", "csi-controller")), csioptions.Endpoint, csioptions.Kubeconfig, csioptions.Master,
-		true, driver.BlockVolumeDriverName, driver.BlockVolumeDriverVersion)
+	drv, err := driver.NewControllerDriver(logger.Named(name).With(zap.String("component", "csi-controller")), endpoint, csioptions.Kubeconfig, csioptions.Master,
+		true, driverName, driverVersion, csioptions.OrphanedAttachmentGracePeriod)
 	if err != nil {
 		logger.With(zap.Error(err)).Fatal("Failed to create controller driver.")
 	}
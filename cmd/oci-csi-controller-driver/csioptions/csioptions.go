@@ -25,6 +25,7 @@ type CSIOptions struct {
 	Kubeconfig              string
 	CsiAddress              string
 	Endpoint                string
+	FssEndpoint             string
 	VolumeNamePrefix        string
 	VolumeNameUUIDLength    int
 	ShowVersion             bool
@@ -45,6 +46,9 @@ type CSIOptions struct {
 	ExtraCreateMetadata     bool
 	ReconcileSync           time.Duration
 	EnableResizer           bool
+	EnableFssDriver         bool
+
+	OrphanedAttachmentGracePeriod time.Duration
 }
 
 //NewCSIOptions initializes the flag
@@ -30,8 +30,12 @@ import (
 func main() {
 	csiOptions := csioptions.CSIOptions{}
 	flag.StringVar(&csiOptions.Endpoint, "endpoint", "unix://tmp/csi.sock", "CSI endpoint")
+	flag.StringVar(&csiOptions.FssEndpoint, "fss-endpoint", "unix://tmp/fss/csi.sock", "FSS CSI endpoint")
+	flag.BoolVar(&csiOptions.EnableFssDriver, "fss-csi-driver-enabled", true, "Enables the FSS CSI controller driver.")
 	flag.StringVar(&csiOptions.Master, "master", "", "kube master")
 	flag.StringVar(&csiOptions.Kubeconfig, "kubeconfig", "", "cluster kubeconfig")
+	flag.DurationVar(&csiOptions.OrphanedAttachmentGracePeriod, "orphaned-attachment-grace-period", 0,
+		"How long block volumes stay attached to deleted or terminated nodes before they are detached, e.g. 5m. 0 disables detaching them.")
 	flag.Parse()
 	stopCh := signals.SetupSignalHandler()
 	log := logging.Logger()
@@ -52,5 +56,9 @@ func main() {
 	}
 	logger.With("endpoint", csiOptions.Endpoint).Infof("Starting controller driver go routine.")
 	go csicontrollerdriver.StartControllerDriver(csiOptions)
+	if csiOptions.EnableFssDriver {
+		logger.With("endpoint", csiOptions.FssEndpoint).Infof("Starting FSS controller driver go routine.")
+		go csicontrollerdriver.StartFSSControllerDriver(csiOptions)
+	}
 	<-stopCh
 }
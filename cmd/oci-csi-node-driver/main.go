@@ -37,6 +37,8 @@ func main() {
 	flag.StringVar(&nodecsioptions.Kubeconfig, "kubeconfig", "", "cluster kubeconfig")
 	flag.StringVar(&nodecsioptions.FssEndpoint, "fss-endpoint", "unix://tmp/fss/csi.sock", "FSS CSI endpoint")
 	flag.BoolVar(&nodecsioptions.EnableFssDriver, "fss-csi-driver-enabled", true, "Handle flag to enable FSS CSI driver")
+	flag.Int64Var(&nodecsioptions.MaxVolumesPerNode, "max-volumes-per-node", 0, "Maximum number of block volumes attached to the node, the limit of the instance shape less the volumes attached outside of Kubernetes when 0")
+	flag.StringVar(&nodecsioptions.VolumeLimitsPerShape, "volume-limits-per-shape", "", "Block volume attachment limits of instance shapes as comma separated <shape>=<limit> entries, shapes that aren't listed take 32 attachments")
 
 	klog.InitFlags(nil)
 	flag.Set("logtostderr", "true")
@@ -44,6 +46,11 @@ func main() {
 
 	viper.Set("log-level", getLevel(nodecsioptions.LogLevel))
 
+	volumeLimitsPerShape, err := driver.ParseVolumeLimitsPerShape(nodecsioptions.VolumeLimitsPerShape)
+	if err != nil {
+		klog.Fatalf("Invalid --volume-limits-per-shape: %v", err)
+	}
+
 	blockvolumeNodeOptions := nodedriveroptions.NodeOptions{
 		Name:                   "BV",
 		Endpoint:               nodecsioptions.Endpoint,
@@ -53,6 +60,8 @@ func main() {
 		DriverName:             driver.BlockVolumeDriverName,
 		DriverVersion:          driver.BlockVolumeDriverVersion,
 		EnableControllerServer: false,
+		MaxVolumesPerNode:      nodecsioptions.MaxVolumesPerNode,
+		VolumeLimitsPerShape:   volumeLimitsPerShape,
 	}
 	fssNodeOptions := nodedriveroptions.NodeOptions{
 		Name:                   "FSS",
@@ -24,6 +24,8 @@ type NodeCSIOptions struct {
 
 	EnableFssDriver            bool
 	FssEndpoint                string
+	MaxVolumesPerNode          int64
+	VolumeLimitsPerShape       string
 }
 
 type NodeOptions struct {
@@ -35,4 +37,6 @@ type NodeOptions struct {
 	DriverName             string
 	DriverVersion          string
 	EnableControllerServer bool
+	MaxVolumesPerNode      int64
+	VolumeLimitsPerShape   map[string]int64
 }
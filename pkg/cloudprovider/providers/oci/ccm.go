@@ -29,6 +29,8 @@ import (
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	listersv1 "k8s.io/client-go/listers/core/v1"
+	metadataclient "k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/tools/cache"
 	cloudprovider "k8s.io/cloud-provider"
 
@@ -179,6 +181,27 @@ func (cp *CloudProvider) Initialize(clientBuilder cloudprovider.ControllerClient
 	go serviceInformer.Informer().Run(wait.NeverStop)
 	go nodeInfoController.Run(wait.NeverStop)
 
+	// Only the metadata of the secrets is cached, the secrets referenced by
+	// Services are read when they change.
+	var metadataClient metadataclient.Interface
+	config, err := clientBuilder.Config("cloud-controller-manager")
+	if err == nil {
+		metadataClient, err = metadataclient.NewForConfig(config)
+	}
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to create metadata client: %v", err))
+	} else {
+		metadataFactory := metadatainformer.NewSharedInformerFactory(metadataClient, 5*time.Minute)
+		secretInformer := metadataFactory.ForResource(SecretsResource)
+		go secretInformer.Informer().Run(wait.NeverStop)
+		sslSecretController := NewSSLSecretController(
+			secretInformer,
+			serviceInformer,
+			cp.kubeclient,
+			cp.logger)
+		go sslSecretController.Run(wait.NeverStop)
+	}
+
 	cp.logger.Info("Waiting for node informer cache to sync")
 	if !cache.WaitForCacheSync(wait.NeverStop, nodeInformer.Informer().HasSynced, serviceInformer.Informer().HasSynced) {
 		utilruntime.HandleError(fmt.Errorf("Timed out waiting for informers to sync"))
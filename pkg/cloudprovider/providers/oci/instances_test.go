@@ -303,6 +303,10 @@ func (MockOCIClient) Identity() client.IdentityInterface {
 	return &MockIdentityClient{}
 }
 
+func (MockOCIClient) Limits() client.LimitsInterface {
+	return nil
+}
+
 // MockComputeClient mocks Compute client implementation
 type MockComputeClient struct{}
 
@@ -340,11 +344,11 @@ func (MockComputeClient) FindVolumeAttachment(ctx context.Context, compartmentID
 	return nil, nil
 }
 
-func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error) {
+func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
-func (MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error) {
+func (MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
@@ -364,6 +368,18 @@ func (c *MockComputeClient) FindActiveVolumeAttachment(ctx context.Context, comp
 	return nil, nil
 }
 
+func (c *MockComputeClient) FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error) {
+	return nil, nil
+}
+
 // MockVirtualNetworkClient mocks VirtualNetwork client implementation
 type MockVirtualNetworkClient struct {
 }
@@ -436,6 +452,18 @@ func (c *MockLoadBalancerClient) CreateCertificate(ctx context.Context, lbID str
 	return "", nil
 }
 
+func (c *MockLoadBalancerClient) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
 func (c *MockLoadBalancerClient) CreateBackendSet(ctx context.Context, lbID string, name string, details *client.GenericBackendSetDetails) (string, error) {
 	return "", nil
 }
@@ -516,10 +544,34 @@ func (MockBlockStorageClient) GetVolumesByName(ctx context.Context, volumeName,
 	return nil, nil
 }
 
+func (MockBlockStorageClient) ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error) {
+	return nil, nil, nil
+}
+
 func (MockBlockStorageClient) DeleteVolume(ctx context.Context, id string) error {
 	return nil
 }
 
+func (MockBlockStorageClient) CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (MockBlockStorageClient) GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (MockBlockStorageClient) GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (MockBlockStorageClient) ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error) {
+	return nil, nil, nil
+}
+
+func (MockBlockStorageClient) DeleteVolumeBackup(ctx context.Context, id string) error {
+	return nil
+}
+
 // MockFileStorageClient mocks FileStorage client implementation.
 type MockFileStorageClient struct{}
 
@@ -555,6 +607,10 @@ func (MockFileStorageClient) FindExport(ctx context.Context, compartmentID, fsID
 	return nil, nil
 }
 
+func (MockFileStorageClient) ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]filestorage.ExportSummary, error) {
+	return nil, nil
+}
+
 func (MockFileStorageClient) AwaitExportActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*filestorage.Export, error) {
 	return nil, nil
 }
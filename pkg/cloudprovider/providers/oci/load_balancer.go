@@ -228,6 +228,19 @@ func (cp *CloudProvider) readSSLSecret(ns, name string) (*certificateData, error
 	return &certificateData{CACert: cacert, PublicCert: cert, PrivateKey: key, Passphrase: pass}, nil
 }
 
+// readCASecret returns the CA certificates from a Kubernetes Secret.
+func (cp *CloudProvider) readCASecret(ns, name string) ([]byte, error) {
+	secret, err := cp.kubeclient.CoreV1().Secrets(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cacert, ok := secret.Data[SSLCAFileName]
+	if !ok || len(cacert) == 0 {
+		return nil, errors.Errorf("%s not found in secret %s/%s", SSLCAFileName, ns, name)
+	}
+	return cacert, nil
+}
+
 // ensureSSLCertificate creates a OCI SSL certificate to the given load
 // balancer, if it doesn't already exist.
 func (clb *CloudLoadBalancerProvider) ensureSSLCertificates(ctx context.Context, lb *client.GenericLoadBalancer, spec *LBSpec) error {
@@ -257,6 +270,79 @@ func (clb *CloudLoadBalancerProvider) ensureSSLCertificates(ctx context.Context,
 	return nil
 }
 
+// deleteUnusedSSLCertificates deletes the OCI SSL certificates of the given load
+// balancer that were built from the secrets of the spec, but are not the
+// current certificates of the secrets anymore.
+func (clb *CloudLoadBalancerProvider) deleteUnusedSSLCertificates(ctx context.Context, lb *client.GenericLoadBalancer, spec *LBSpec) error {
+	if spec.SSLConfig == nil {
+		return nil
+	}
+	certs, err := spec.Certificates()
+	if err != nil {
+		return err
+	}
+
+	var secretNames []string
+	for _, secretName := range []string{spec.SSLConfig.ListenerSSLSecretName, spec.SSLConfig.BackendSetSSLSecretName} {
+		if secretName != "" {
+			secretNames = append(secretNames, secretName)
+		}
+	}
+
+	for name := range lb.Certificates {
+		if _, ok := certs[name]; ok {
+			continue
+		}
+		for _, secretName := range secretNames {
+			if !isCertificateOfSecret(name, secretName) {
+				continue
+			}
+			logger := clb.logger.With("loadBalancerID", *lb.Id, "certificateName", name)
+			wrID, err := clb.lbClient.DeleteCertificate(ctx, *lb.Id, name)
+			if err != nil {
+				return err
+			}
+			logger.With("workRequestID", wrID).Info("Await workrequest for delete certificate")
+			if _, err = clb.lbClient.AwaitWorkRequest(ctx, wrID); err != nil {
+				return err
+			}
+			logger.Info("Workrequest for certificate delete succeeded")
+			break
+		}
+	}
+	return nil
+}
+
+// ensureSSLCipherSuites creates the custom OCI SSL cipher suites of the given
+// load balancer if they don't already exist, and updates the ones whose ciphers
+// have changed.
+func (clb *CloudLoadBalancerProvider) ensureSSLCipherSuites(ctx context.Context, lb *client.GenericLoadBalancer, spec *LBSpec) error {
+	for name, suite := range spec.SSLCipherSuites {
+		logger := clb.logger.With("loadBalancerID", *lb.Id, "cipherSuiteName", name)
+		var wrID string
+		var err error
+		actual, ok := lb.SslCipherSuites[name]
+		switch {
+		case !ok:
+			wrID, err = clb.lbClient.CreateSSLCipherSuite(ctx, *lb.Id, &suite)
+		case !sets.NewString(actual.Ciphers...).Equal(sets.NewString(suite.Ciphers...)):
+			logger.With("actualCiphers", actual.Ciphers, "desiredCiphers", suite.Ciphers).Info("Cipher suite needs to be updated")
+			wrID, err = clb.lbClient.UpdateSSLCipherSuite(ctx, *lb.Id, &suite)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		logger.With("workRequestID", wrID).Info("Await workrequest for cipher suite")
+		if _, err = clb.lbClient.AwaitWorkRequest(ctx, wrID); err != nil {
+			return err
+		}
+		logger.Info("Workrequest for cipher suite succeeded")
+	}
+	return nil
+}
+
 // createLoadBalancer creates a new OCI load balancer based on the given spec.
 func (clb *CloudLoadBalancerProvider) createLoadBalancer(ctx context.Context, spec *LBSpec) (lbStatus *v1.LoadBalancerStatus, lbOCID string, err error) {
 	logger := clb.logger.With("loadBalancerName", spec.Name, "loadBalancerType", getLoadBalancerType(spec.service))
@@ -288,6 +374,7 @@ func (clb *CloudLoadBalancerProvider) createLoadBalancer(ctx context.Context, sp
 		BackendSets:             spec.BackendSets,
 		Listeners:               spec.Listeners,
 		Certificates:            certs,
+		SslCipherSuites:         spec.SSLCipherSuites,
 		NetworkSecurityGroupIds: spec.NetworkSecurityGroupIds,
 		FreeformTags:            spec.FreeformTags,
 		DefinedTags:             spec.DefinedTags,
@@ -512,6 +599,19 @@ func (cp *CloudProvider) EnsureLoadBalancer(ctx context.Context, clusterName str
 		}
 	}
 
+	// Ensure the custom cipher suites used by the SSL configurations are present.
+	if len(spec.SSLCipherSuites) != 0 {
+		if err := lbProvider.ensureSSLCipherSuites(ctx, lb, spec); err != nil {
+			logger.With(zap.Error(err)).Error("Failed to ensure ssl cipher suites")
+			errorType = util.GetError(err)
+			lbMetricDimension = util.GetMetricDimensionForComponent(errorType, util.LoadBalancerType)
+			dimensionsMap[metrics.ComponentDimension] = lbMetricDimension
+			metrics.SendMetricData(cp.metricPusher, getMetric(loadBalancerType, Update), time.Since(startTime).Seconds(), dimensionsMap)
+
+			return nil, errors.Wrap(err, "ensuring ssl cipher suites")
+		}
+	}
+
 	if len(nodes) == 0 {
 		allNodesNotReady, err := cp.checkAllBackendNodesNotReady()
 		if err != nil {
@@ -533,6 +633,15 @@ func (cp *CloudProvider) EnsureLoadBalancer(ctx context.Context, clusterName str
 		return nil, err
 	}
 
+	// The listeners and backend sets now use the current certificates, so the
+	// ones of the renewed secrets can be deleted. This is retried on the next
+	// sync when it fails.
+	if requiresCertificate(service) {
+		if err := lbProvider.deleteUnusedSSLCertificates(ctx, lb, spec); err != nil {
+			logger.With(zap.Error(err)).Warn("Failed to delete unused ssl certificates")
+		}
+	}
+
 	syncTime := time.Since(startTime).Seconds()
 	logger.Info("Successfully updated loadbalancer")
 	lbMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.LoadBalancerType)
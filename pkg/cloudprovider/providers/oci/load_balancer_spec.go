@@ -15,10 +15,13 @@
 package oci
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -142,6 +145,135 @@ const (
 	// ServiceAnnotationLoadBalancerNodeFilter is a service annotation to select specific nodes as your backend in the LB
 	// based on label selector.
 	ServiceAnnotationLoadBalancerNodeFilter = "oci.oraclecloud.com/node-label-selector"
+
+	// ServiceAnnotationLoadBalancerSessionPersistence is a service annotation for
+	// enabling cookie-based session persistence on the backend sets of the LB
+	// ("app-cookie", "lb-cookie"). It requires the HTTP backend protocol.
+	ServiceAnnotationLoadBalancerSessionPersistence = "oci.oraclecloud.com/loadbalancer-session-persistence"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookieName is a service annotation for
+	// specifying the name of the session persistence cookie. It is required for
+	// "app-cookie" persistence, where "*" matches any cookie set by the application.
+	ServiceAnnotationLoadBalancerSessionPersistenceCookieName = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-name"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback is a service annotation for
+	// rejecting the requests of persisted sessions whose backend is unavailable
+	// instead of sending them to another backend ("true", "false").
+	ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback = "oci.oraclecloud.com/loadbalancer-session-persistence-disable-fallback"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookieDomain is a service annotation for
+	// specifying the Domain attribute of the "lb-cookie" persistence cookie.
+	ServiceAnnotationLoadBalancerSessionPersistenceCookieDomain = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-domain"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookiePath is a service annotation for
+	// specifying the Path attribute of the "lb-cookie" persistence cookie.
+	ServiceAnnotationLoadBalancerSessionPersistenceCookiePath = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-path"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge is a service annotation for
+	// specifying the Max-Age attribute, in seconds, of the "lb-cookie" persistence cookie.
+	ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-max-age"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure is a service annotation for
+	// setting the Secure attribute of the "lb-cookie" persistence cookie ("true", "false").
+	ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-secure"
+
+	// ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly is a service annotation for
+	// setting the HttpOnly attribute of the "lb-cookie" persistence cookie ("true", "false").
+	ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly = "oci.oraclecloud.com/loadbalancer-session-persistence-cookie-http-only"
+
+	// ServiceAnnotationLoadBalancerSSLCipherSuite is a service annotation for
+	// specifying the cipher suite of the SSL listeners and backend sets, either
+	// a predefined cipher suite or the custom cipher suite defined by
+	// ServiceAnnotationLoadBalancerSSLCiphers.
+	ServiceAnnotationLoadBalancerSSLCipherSuite = "oci.oraclecloud.com/loadbalancer-ssl-cipher-suite"
+
+	// ServiceAnnotationLoadBalancerSSLCiphers is a service annotation for
+	// specifying a comma separated list of the ciphers of a custom cipher suite.
+	// The cipher suite is created on the LB when it does not exist.
+	ServiceAnnotationLoadBalancerSSLCiphers = "oci.oraclecloud.com/loadbalancer-ssl-ciphers"
+
+	// ServiceAnnotationLoadBalancerSSLProtocols is a service annotation for
+	// specifying a comma separated list of the TLS protocol versions allowed by
+	// the SSL listeners and backend sets ("TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3").
+	ServiceAnnotationLoadBalancerSSLProtocols = "oci.oraclecloud.com/loadbalancer-ssl-protocols"
+
+	// ServiceAnnotationLoadBalancerSSLServerOrderPreference is a service annotation for
+	// preferring the order of the ciphers of the LB to the order of the client
+	// ("Enabled", "Disabled").
+	ServiceAnnotationLoadBalancerSSLServerOrderPreference = "oci.oraclecloud.com/loadbalancer-ssl-server-order-preference"
+
+	// ServiceAnnotationLoadBalancerSSLClientCASecret is a service annotation for
+	// specifying the secret holding the CA certificates ("ca.crt") that the SSL
+	// listeners verify the client certificates with, enabling mutual TLS.
+	ServiceAnnotationLoadBalancerSSLClientCASecret = "oci.oraclecloud.com/loadbalancer-ssl-client-ca-secret"
+
+	// ServiceAnnotationLoadBalancerSSLClientVerifyDepth is a service annotation for
+	// specifying the maximum depth of the client certificate chains verified
+	// by the SSL listeners.
+	ServiceAnnotationLoadBalancerSSLClientVerifyDepth = "oci.oraclecloud.com/loadbalancer-ssl-client-verify-depth"
+
+	// ServiceAnnotationLoadBalancerSSLCertificateID is a service annotation for
+	// specifying the OCID of the OCI Certificates service certificate of the
+	// SSL listeners, used instead of the TLS secret.
+	ServiceAnnotationLoadBalancerSSLCertificateID = "oci.oraclecloud.com/loadbalancer-ssl-certificate-id"
+
+	// ServiceAnnotationLoadBalancerSSLCABundleID is a service annotation for
+	// specifying the OCID of the OCI Certificates service CA bundle that the
+	// SSL listeners verify the client certificates with.
+	ServiceAnnotationLoadBalancerSSLCABundleID = "oci.oraclecloud.com/loadbalancer-ssl-ca-bundle-id"
+
+	// ServiceAnnotationLoadBalancerSSLBackendSetCertificateID is a service
+	// annotation for specifying the OCID of the OCI Certificates service
+	// certificate of the SSL backend sets, used instead of the TLS backend set
+	// secret.
+	ServiceAnnotationLoadBalancerSSLBackendSetCertificateID = "oci.oraclecloud.com/loadbalancer-ssl-backendset-certificate-id"
+
+	// ServiceAnnotationLoadBalancerSSLBackendSetCABundleID is a service
+	// annotation for specifying the OCID of the OCI Certificates service CA
+	// bundle that the SSL backend sets verify the backend certificates with.
+	ServiceAnnotationLoadBalancerSSLBackendSetCABundleID = "oci.oraclecloud.com/loadbalancer-ssl-backendset-ca-bundle-id"
+)
+
+// Values of the ServiceAnnotationLoadBalancerSSLServerOrderPreference annotation.
+const (
+	SSLServerOrderPreferenceEnabled  = "ENABLED"
+	SSLServerOrderPreferenceDisabled = "DISABLED"
+
+	// predefinedSSLCipherSuitePrefix is reserved for the names of the
+	// predefined cipher suites.
+	predefinedSSLCipherSuitePrefix = "oci-"
+
+	defaultSSLClientVerifyDepth     = 1
+	defaultSSLBackendSetVerifyDepth = 1
+	// certificateDigestLength is the number of hex digits of the digest of the
+	// contents in the name of the certificate bundles.
+	certificateDigestLength = 12
+)
+
+var certificateDigestRegexp = regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%d}$", certificateDigestLength))
+
+// sslProtocols maps the lower case TLS protocol versions supported by the LB to
+// their names.
+var sslProtocols = map[string]string{
+	"tlsv1":   "TLSv1",
+	"tlsv1.1": "TLSv1.1",
+	"tlsv1.2": "TLSv1.2",
+	"tlsv1.3": "TLSv1.3",
+}
+
+// Session persistence types of the ServiceAnnotationLoadBalancerSessionPersistence annotation.
+const (
+	// SessionPersistenceAppCookie keeps the sessions identified by a cookie set
+	// by the application on the same backend.
+	SessionPersistenceAppCookie = "app-cookie"
+	// SessionPersistenceLBCookie keeps the sessions on the same backend with a
+	// cookie inserted by the load balancer.
+	SessionPersistenceLBCookie = "lb-cookie"
+
+	// The defaults of the LB persistence cookie, set explicitly so that the
+	// backend sets are updated when the corresponding annotations are removed.
+	defaultLBCookieName = "X-Oracle-BMC-LBS-Route"
+	defaultLBCookiePath = "/"
 )
 
 // NLB specific annotations
@@ -203,6 +335,7 @@ type certificateData struct {
 
 type sslSecretReader interface {
 	readSSLSecret(ns, name string) (sslSecret *certificateData, err error)
+	readCASecret(ns, name string) (caCert []byte, err error)
 }
 
 type noopSSLSecretReader struct{}
@@ -211,6 +344,10 @@ func (ssr noopSSLSecretReader) readSSLSecret(ns, name string) (sslSecret *certif
 	return nil, nil
 }
 
+func (ssr noopSSLSecretReader) readCASecret(ns, name string) (caCert []byte, err error) {
+	return nil, nil
+}
+
 // SSLConfig is a description of a SSL certificate.
 type SSLConfig struct {
 	Ports sets.Int
@@ -221,6 +358,21 @@ type SSLConfig struct {
 	BackendSetSSLSecretName      string
 	BackendSetSSLSecretNamespace string
 
+	// ListenerClientCASecretName and ListenerClientCASecretNamespace name the
+	// secret of the CA certificates the listeners verify clients with.
+	ListenerClientCASecretName      string
+	ListenerClientCASecretNamespace string
+
+	// ListenerCertificateID and ListenerCABundleID are the OCIDs of the OCI
+	// Certificates service certificate and CA bundle of the listeners.
+	ListenerCertificateID string
+	ListenerCABundleID    string
+
+	// BackendSetCertificateID and BackendSetCABundleID are the OCIDs of the OCI
+	// Certificates service certificate and CA bundle of the backend sets.
+	BackendSetCertificateID string
+	BackendSetCABundleID    string
+
 	sslSecretReader
 }
 
@@ -240,17 +392,98 @@ func NewSSLConfig(secretListenerString string, secretBackendSetString string, se
 
 	listenerSecretName, listenerSecretNamespace := getSecretParts(secretListenerString, service)
 	backendSecretName, backendSecretNamespace := getSecretParts(secretBackendSetString, service)
+	var clientCASecretName, clientCASecretNamespace string
+	var listenerCertificateID, listenerCABundleID, backendSetCertificateID, backendSetCABundleID string
+	if service != nil {
+		clientCASecretName, clientCASecretNamespace = getSecretParts(service.Annotations[ServiceAnnotationLoadBalancerSSLClientCASecret], service)
+		listenerCertificateID = service.Annotations[ServiceAnnotationLoadBalancerSSLCertificateID]
+		listenerCABundleID = service.Annotations[ServiceAnnotationLoadBalancerSSLCABundleID]
+		backendSetCertificateID = service.Annotations[ServiceAnnotationLoadBalancerSSLBackendSetCertificateID]
+		backendSetCABundleID = service.Annotations[ServiceAnnotationLoadBalancerSSLBackendSetCABundleID]
+	}
 
 	return &SSLConfig{
-		Ports:                        sets.NewInt(ports...),
-		ListenerSSLSecretName:        listenerSecretName,
-		ListenerSSLSecretNamespace:   listenerSecretNamespace,
-		BackendSetSSLSecretName:      backendSecretName,
-		BackendSetSSLSecretNamespace: backendSecretNamespace,
-		sslSecretReader:              ssr,
+		Ports:                           sets.NewInt(ports...),
+		ListenerSSLSecretName:           listenerSecretName,
+		ListenerSSLSecretNamespace:      listenerSecretNamespace,
+		BackendSetSSLSecretName:         backendSecretName,
+		BackendSetSSLSecretNamespace:    backendSecretNamespace,
+		ListenerClientCASecretName:      clientCASecretName,
+		ListenerClientCASecretNamespace: clientCASecretNamespace,
+		ListenerCertificateID:           listenerCertificateID,
+		ListenerCABundleID:              listenerCABundleID,
+		BackendSetCertificateID:         backendSetCertificateID,
+		BackendSetCABundleID:            backendSetCABundleID,
+		sslSecretReader:                 ssr,
 	}
 }
 
+// listenerCertificate returns the certificate bundle of the listeners, with
+// the client CA certificates when the clients are verified.
+func (c *SSLConfig) listenerCertificate() (*client.GenericCertificate, error) {
+	cert, err := c.readSSLSecret(c.ListenerSSLSecretNamespace, c.ListenerSSLSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SSL Listener Secret")
+	}
+	if cert == nil {
+		return nil, errors.Errorf("SSL Listener Secret %s/%s not found", c.ListenerSSLSecretNamespace, c.ListenerSSLSecretName)
+	}
+	if c.ListenerClientCASecretName != "" {
+		caCert, err := c.readCASecret(c.ListenerClientCASecretNamespace, c.ListenerClientCASecretName)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading SSL client CA Secret")
+		}
+		withClientCA := *cert
+		withClientCA.CACert = caCert
+		cert = &withClientCA
+	}
+	return newCertificate(c.ListenerSSLSecretName, cert), nil
+}
+
+// backendSetCertificate returns the certificate bundle of the backend sets.
+func (c *SSLConfig) backendSetCertificate() (*client.GenericCertificate, error) {
+	cert, err := c.readSSLSecret(c.BackendSetSSLSecretNamespace, c.BackendSetSSLSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SSL Backend Secret")
+	}
+	if cert == nil {
+		return nil, errors.Errorf("SSL Backend Secret %s/%s not found", c.BackendSetSSLSecretNamespace, c.BackendSetSSLSecretName)
+	}
+	return newCertificate(c.BackendSetSSLSecretName, cert), nil
+}
+
+// newCertificate builds the certificate bundle of a secret. The certificate
+// bundles of a load balancer can't be updated, so they are named after the
+// secret and a digest of their contents: when the secret is renewed, a new
+// certificate bundle is uploaded and the listeners and backend sets are
+// updated to use it.
+func newCertificate(secretName string, cert *certificateData) *client.GenericCertificate {
+	digest := sha256.New()
+	for _, data := range [][]byte{cert.CACert, cert.PublicCert, cert.PrivateKey, cert.Passphrase} {
+		digest.Write(data)
+		digest.Write([]byte{0})
+	}
+	name := fmt.Sprintf("%s-%s", secretName, hex.EncodeToString(digest.Sum(nil))[:certificateDigestLength])
+	return &client.GenericCertificate{
+		CertificateName:   &name,
+		CaCertificate:     common.String(string(cert.CACert)),
+		PublicCertificate: common.String(string(cert.PublicCert)),
+		PrivateKey:        common.String(string(cert.PrivateKey)),
+		Passphrase:        common.String(string(cert.Passphrase)),
+	}
+}
+
+// isCertificateOfSecret returns whether a certificate bundle was built from the
+// secret, either by newCertificate or, before the certificate bundles were
+// named after their contents, with the name of the secret.
+func isCertificateOfSecret(certificateName, secretName string) bool {
+	if certificateName == secretName {
+		return true
+	}
+	digest := strings.TrimPrefix(certificateName, secretName+"-")
+	return digest != certificateName && certificateDigestRegexp.MatchString(digest)
+}
+
 // LBSpec holds the data required to build a OCI load balancer from a
 // kubernetes service.
 type LBSpec struct {
@@ -268,6 +501,7 @@ type LBSpec struct {
 	Ports                       map[string]portSpec
 	SourceCIDRs                 []string
 	SSLConfig                   *SSLConfig
+	SSLCipherSuites             map[string]client.GenericSslCipherSuite
 	securityListManager         securityListManager
 	NetworkSecurityGroupIds     []string
 	FreeformTags                map[string]string
@@ -305,6 +539,11 @@ func NewLBSpec(logger *zap.SugaredLogger, svc *v1.Service, nodes []*v1.Node, sub
 
 	isPreserveSourceDestination := getPreserveSourceDestination(svc)
 
+	sslCipherSuites, err := getSSLCipherSuites(svc)
+	if err != nil {
+		return nil, err
+	}
+
 	backendSets, err := getBackendSets(logger, svc, nodes, sslConfig, isPreserveSourceDestination)
 	if err != nil {
 		return nil, err
@@ -351,6 +590,7 @@ func NewLBSpec(logger *zap.SugaredLogger, svc *v1.Service, nodes []*v1.Node, sub
 		IsPreserveSourceDestination: &isPreserveSourceDestination,
 		Ports:                       ports,
 		SSLConfig:                   sslConfig,
+		SSLCipherSuites:             sslCipherSuites,
 		SourceCIDRs:                 sourceCIDRs,
 		NetworkSecurityGroupIds:     networkSecurityGroupIds,
 		service:                     svc,
@@ -397,31 +637,19 @@ func (s *LBSpec) Certificates() (map[string]client.GenericCertificate, error) {
 	}
 
 	if s.SSLConfig.ListenerSSLSecretName != "" {
-		cert, err := s.SSLConfig.readSSLSecret(s.SSLConfig.ListenerSSLSecretNamespace, s.SSLConfig.ListenerSSLSecretName)
+		cert, err := s.SSLConfig.listenerCertificate()
 		if err != nil {
-			return nil, errors.Wrap(err, "reading SSL Listener Secret")
-		}
-		certs[s.SSLConfig.ListenerSSLSecretName] = client.GenericCertificate{
-			CertificateName:   &s.SSLConfig.ListenerSSLSecretName,
-			CaCertificate:     common.String(string(cert.CACert)),
-			PublicCertificate: common.String(string(cert.PublicCert)),
-			PrivateKey:        common.String(string(cert.PrivateKey)),
-			Passphrase:        common.String(string(cert.Passphrase)),
+			return nil, err
 		}
+		certs[*cert.CertificateName] = *cert
 	}
 
 	if s.SSLConfig.BackendSetSSLSecretName != "" {
-		cert, err := s.SSLConfig.readSSLSecret(s.SSLConfig.BackendSetSSLSecretNamespace, s.SSLConfig.BackendSetSSLSecretName)
+		cert, err := s.SSLConfig.backendSetCertificate()
 		if err != nil {
-			return nil, errors.Wrap(err, "reading SSL Backend Secret")
-		}
-		certs[s.SSLConfig.BackendSetSSLSecretName] = client.GenericCertificate{
-			CertificateName:   &s.SSLConfig.BackendSetSSLSecretName,
-			CaCertificate:     common.String(string(cert.CACert)),
-			PublicCertificate: common.String(string(cert.PublicCert)),
-			PrivateKey:        common.String(string(cert.PrivateKey)),
-			Passphrase:        common.String(string(cert.Passphrase)),
+			return nil, err
 		}
+		certs[*cert.CertificateName] = *cert
 	}
 	return certs, nil
 }
@@ -443,6 +671,22 @@ func validateService(svc *v1.Service) error {
 		return errors.New("OCI only supports SessionAffinity \"None\" currently")
 	}
 
+	if _, _, err := getSessionPersistenceConfiguration(svc); err != nil {
+		return err
+	}
+
+	if _, err := getSSLCipherConfiguration(svc); err != nil {
+		return err
+	}
+
+	if err := validateSSLCertificateIDs(svc); err != nil {
+		return err
+	}
+
+	if _, err := getSSLClientVerifyDepth(svc); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -522,28 +766,155 @@ func getBackendSets(logger *zap.SugaredLogger, svc *v1.Service, nodes []*v1.Node
 	if err != nil {
 		return nil, err
 	}
+	sessionPersistence, lbCookieSessionPersistence, err := getSessionPersistenceConfiguration(svc)
+	if err != nil {
+		return nil, err
+	}
+	sslCipherCfg, err := getSSLCipherConfiguration(svc)
+	if err != nil {
+		return nil, err
+	}
+	var certificateName, certificateID, caBundleID string
+	if sslCfg != nil {
+		certificateID, caBundleID = sslCfg.BackendSetCertificateID, sslCfg.BackendSetCABundleID
+		if len(sslCfg.BackendSetSSLSecretName) != 0 {
+			cert, err := sslCfg.backendSetCertificate()
+			if err != nil {
+				return nil, err
+			}
+			certificateName = *cert.CertificateName
+		}
+	}
 	for _, servicePort := range svc.Spec.Ports {
 		name := getBackendSetName(string(servicePort.Protocol), int(servicePort.Port))
 		port := int(servicePort.Port)
-		var secretName string
-		if sslCfg != nil && len(sslCfg.BackendSetSSLSecretName) != 0 {
-			secretName = sslCfg.BackendSetSSLSecretName
-		}
 		healthChecker, err := getHealthChecker(svc)
 		if err != nil {
 			return nil, err
 		}
+		sslConfiguration := getSSLConfiguration(sslCfg, sslCipherCfg, certificateName, certificateID, port)
+		if sslConfiguration != nil && len(caBundleID) != 0 {
+			sslConfiguration.VerifyPeerCertificate = common.Bool(true)
+			sslConfiguration.VerifyDepth = common.Int(defaultSSLBackendSetVerifyDepth)
+			sslConfiguration.TrustedCertificateAuthorityIds = []string{caBundleID}
+		}
 		backendSets[name] = client.GenericBackendSetDetails{
 			Policy:           &loadbalancerPolicy,
 			Backends:         getBackends(logger, nodes, servicePort.NodePort),
 			HealthChecker:    healthChecker,
 			IsPreserveSource: &isPreserveSourceDestination,
-			SslConfiguration: getSSLConfiguration(sslCfg, secretName, port),
+			SslConfiguration: sslConfiguration,
+
+			SessionPersistenceConfiguration:         sessionPersistence,
+			LbCookieSessionPersistenceConfiguration: lbCookieSessionPersistence,
 		}
 	}
 	return backendSets, nil
 }
 
+// getSessionPersistenceConfiguration returns the application cookie or LB
+// cookie session persistence configuration of the backend sets from the
+// service annotations. Both are nil if session persistence isn't enabled.
+func getSessionPersistenceConfiguration(svc *v1.Service) (*client.GenericSessionPersistenceConfiguration, *client.GenericLbCookieSessionPersistenceConfiguration, error) {
+	cookieAnnotations := []string{
+		ServiceAnnotationLoadBalancerSessionPersistenceCookieName,
+		ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback,
+		ServiceAnnotationLoadBalancerSessionPersistenceCookieDomain,
+		ServiceAnnotationLoadBalancerSessionPersistenceCookiePath,
+		ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge,
+		ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure,
+		ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly,
+	}
+
+	persistence, ok := svc.Annotations[ServiceAnnotationLoadBalancerSessionPersistence]
+	if !ok {
+		for _, annotation := range cookieAnnotations {
+			if _, ok := svc.Annotations[annotation]; ok {
+				return nil, nil, fmt.Errorf("annotation %s requires annotation %s", annotation, ServiceAnnotationLoadBalancerSessionPersistence)
+			}
+		}
+		return nil, nil, nil
+	}
+	persistence = strings.ToLower(persistence)
+	if persistence != SessionPersistenceAppCookie && persistence != SessionPersistenceLBCookie {
+		return nil, nil, fmt.Errorf("invalid value: %s provided for annotation: %s, only %q and %q are supported",
+			persistence, ServiceAnnotationLoadBalancerSessionPersistence, SessionPersistenceAppCookie, SessionPersistenceLBCookie)
+	}
+	if getLoadBalancerType(svc) == NLB {
+		return nil, nil, fmt.Errorf("annotation %s is not supported by network load balancers", ServiceAnnotationLoadBalancerSessionPersistence)
+	}
+	if !strings.EqualFold(svc.Annotations[ServiceAnnotationLoadBalancerBEProtocol], "HTTP") {
+		return nil, nil, fmt.Errorf("session persistence requires the HTTP backend protocol, set annotation %s to \"HTTP\"", ServiceAnnotationLoadBalancerBEProtocol)
+	}
+
+	parseBool := func(annotation string, defaultValue bool) (*bool, error) {
+		value, ok := svc.Annotations[annotation]
+		if !ok {
+			return common.Bool(defaultValue), nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %s provided for annotation: %s", value, annotation)
+		}
+		return &b, nil
+	}
+
+	cookieName := svc.Annotations[ServiceAnnotationLoadBalancerSessionPersistenceCookieName]
+	disableFallback, err := parseBool(ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if persistence == SessionPersistenceAppCookie {
+		if cookieName == "" {
+			return nil, nil, fmt.Errorf("annotation %s must name the application cookie, or be \"*\" to match any cookie", ServiceAnnotationLoadBalancerSessionPersistenceCookieName)
+		}
+		for _, annotation := range cookieAnnotations[2:] {
+			if _, ok := svc.Annotations[annotation]; ok {
+				return nil, nil, fmt.Errorf("annotation %s is only supported with %q session persistence", annotation, SessionPersistenceLBCookie)
+			}
+		}
+		return &client.GenericSessionPersistenceConfiguration{
+			CookieName:      &cookieName,
+			DisableFallback: disableFallback,
+		}, nil, nil
+	}
+
+	if cookieName == "" {
+		cookieName = defaultLBCookieName
+	} else if cookieName == "*" {
+		return nil, nil, fmt.Errorf("annotation %s can't be \"*\" with %q session persistence", ServiceAnnotationLoadBalancerSessionPersistenceCookieName, SessionPersistenceLBCookie)
+	}
+	config := &client.GenericLbCookieSessionPersistenceConfiguration{
+		CookieName:      &cookieName,
+		DisableFallback: disableFallback,
+		Path:            common.String(defaultLBCookiePath),
+	}
+	if domain, ok := svc.Annotations[ServiceAnnotationLoadBalancerSessionPersistenceCookieDomain]; ok && domain != "" {
+		config.Domain = &domain
+	}
+	if path, ok := svc.Annotations[ServiceAnnotationLoadBalancerSessionPersistenceCookiePath]; ok && path != "" {
+		if !strings.HasPrefix(path, "/") {
+			return nil, nil, fmt.Errorf("invalid value: %s provided for annotation: %s, the path must start with \"/\"", path, ServiceAnnotationLoadBalancerSessionPersistenceCookiePath)
+		}
+		config.Path = &path
+	}
+	if maxAge, ok := svc.Annotations[ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil || seconds <= 0 {
+			return nil, nil, fmt.Errorf("invalid value: %s provided for annotation: %s, it must be a positive number of seconds", maxAge, ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge)
+		}
+		config.MaxAgeInSeconds = &seconds
+	}
+	if config.IsSecure, err = parseBool(ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure, false); err != nil {
+		return nil, nil, err
+	}
+	if config.IsHttpOnly, err = parseBool(ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly, true); err != nil {
+		return nil, nil, err
+	}
+	return nil, config, nil
+}
+
 func getHealthChecker(svc *v1.Service) (*client.GenericHealthChecker, error) {
 
 	retries, err := getHealthCheckRetries(svc)
@@ -678,18 +1049,240 @@ func getHealthCheckTimeout(svc *v1.Service) (int, error) {
 	return timeoutInMillis, nil
 }
 
-func getSSLConfiguration(cfg *SSLConfig, name string, port int) *client.GenericSslConfigurationDetails {
-	if cfg == nil || !cfg.Ports.Has(port) || len(name) == 0 {
+// getSSLConfiguration returns the SSL configuration of the given port, using
+// either the certificate bundle of the given name uploaded to the LB or the OCI
+// Certificates service certificate of the given OCID.
+func getSSLConfiguration(cfg *SSLConfig, cipherCfg *sslCipherConfiguration, name string, certificateID string, port int) *client.GenericSslConfigurationDetails {
+	if cfg == nil || !cfg.Ports.Has(port) || (len(name) == 0 && len(certificateID) == 0) {
 		return nil
 	}
-	return &client.GenericSslConfigurationDetails{
-		CertificateName:       &name,
+	sslConfiguration := &client.GenericSslConfigurationDetails{
 		VerifyDepth:           common.Int(0),
 		VerifyPeerCertificate: common.Bool(false),
 	}
+	if len(certificateID) != 0 {
+		sslConfiguration.CertificateIds = []string{certificateID}
+	} else {
+		sslConfiguration.CertificateName = &name
+	}
+	if cipherCfg != nil {
+		sslConfiguration.CipherSuiteName = cipherCfg.cipherSuiteName
+		sslConfiguration.Protocols = cipherCfg.protocols
+		sslConfiguration.ServerOrderPreference = cipherCfg.serverOrderPreference
+	}
+	return sslConfiguration
+}
+
+// sslCipherConfiguration is the cipher suite, TLS protocols and server order
+// preference of the SSL listeners and backend sets. The fields that are not
+// annotated are left to the LB defaults.
+type sslCipherConfiguration struct {
+	cipherSuiteName       *string
+	protocols             []string
+	serverOrderPreference string
+
+	// customCipherSuite is the cipher suite to create on the LB, when its
+	// ciphers are annotated.
+	customCipherSuite *client.GenericSslCipherSuite
+}
+
+func getSSLCipherConfiguration(svc *v1.Service) (*sslCipherConfiguration, error) {
+	cipherAnnotations := []string{
+		ServiceAnnotationLoadBalancerSSLCipherSuite,
+		ServiceAnnotationLoadBalancerSSLCiphers,
+		ServiceAnnotationLoadBalancerSSLProtocols,
+		ServiceAnnotationLoadBalancerSSLServerOrderPreference,
+	}
+
+	var annotated []string
+	for _, annotation := range cipherAnnotations {
+		if _, ok := svc.Annotations[annotation]; ok {
+			annotated = append(annotated, annotation)
+		}
+	}
+	if len(annotated) == 0 {
+		return nil, nil
+	}
+	if getLoadBalancerType(svc) == NLB {
+		return nil, fmt.Errorf("annotation %s is not supported by network load balancers", annotated[0])
+	}
+	if _, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLPorts]; !ok {
+		return nil, fmt.Errorf("annotation %s requires annotation %s", annotated[0], ServiceAnnotationLoadBalancerSSLPorts)
+	}
+
+	cfg := &sslCipherConfiguration{}
+	if name, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLCipherSuite]; ok {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid value: %s provided for annotation: %s", name, ServiceAnnotationLoadBalancerSSLCipherSuite)
+		}
+		cfg.cipherSuiteName = &name
+	}
+
+	if ciphers, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLCiphers]; ok {
+		if cfg.cipherSuiteName == nil {
+			return nil, fmt.Errorf("annotation %s requires annotation %s to name the custom cipher suite",
+				ServiceAnnotationLoadBalancerSSLCiphers, ServiceAnnotationLoadBalancerSSLCipherSuite)
+		}
+		if strings.HasPrefix(*cfg.cipherSuiteName, predefinedSSLCipherSuitePrefix) {
+			return nil, fmt.Errorf("invalid value: %s provided for annotation: %s, the names of custom cipher suites can't start with %q",
+				*cfg.cipherSuiteName, ServiceAnnotationLoadBalancerSSLCipherSuite, predefinedSSLCipherSuitePrefix)
+		}
+		var cipherList []string
+		for _, cipher := range strings.Split(ciphers, ",") {
+			if cipher = strings.TrimSpace(cipher); cipher != "" {
+				cipherList = append(cipherList, cipher)
+			}
+		}
+		if len(cipherList) == 0 {
+			return nil, fmt.Errorf("invalid value: %s provided for annotation: %s", ciphers, ServiceAnnotationLoadBalancerSSLCiphers)
+		}
+		cfg.customCipherSuite = &client.GenericSslCipherSuite{
+			Name:    cfg.cipherSuiteName,
+			Ciphers: cipherList,
+		}
+	}
+
+	if protocols, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLProtocols]; ok {
+		for _, protocol := range strings.Split(protocols, ",") {
+			protocol = strings.TrimSpace(protocol)
+			name, ok := sslProtocols[strings.ToLower(protocol)]
+			if !ok {
+				return nil, fmt.Errorf("invalid value: %s provided for annotation: %s, only TLSv1, TLSv1.1, TLSv1.2 and TLSv1.3 are supported",
+					protocol, ServiceAnnotationLoadBalancerSSLProtocols)
+			}
+			cfg.protocols = append(cfg.protocols, name)
+		}
+	}
+
+	if preference, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLServerOrderPreference]; ok {
+		cfg.serverOrderPreference = strings.ToUpper(preference)
+		if cfg.serverOrderPreference != SSLServerOrderPreferenceEnabled && cfg.serverOrderPreference != SSLServerOrderPreferenceDisabled {
+			return nil, fmt.Errorf("invalid value: %s provided for annotation: %s, only \"Enabled\" and \"Disabled\" are supported",
+				preference, ServiceAnnotationLoadBalancerSSLServerOrderPreference)
+		}
+	}
+	return cfg, nil
+}
+
+// getSSLClientVerifyDepth returns the depth of the client certificate chains
+// verified by the SSL listeners, or nil when the clients aren't verified.
+func getSSLClientVerifyDepth(svc *v1.Service) (*int, error) {
+	verifyDepth, verifyDepthOk := svc.Annotations[ServiceAnnotationLoadBalancerSSLClientVerifyDepth]
+	_, caSecretOk := svc.Annotations[ServiceAnnotationLoadBalancerSSLClientCASecret]
+	_, caBundleIDOk := svc.Annotations[ServiceAnnotationLoadBalancerSSLCABundleID]
+	if !caSecretOk && !caBundleIDOk {
+		if verifyDepthOk {
+			return nil, fmt.Errorf("annotation %s requires annotation %s", ServiceAnnotationLoadBalancerSSLClientVerifyDepth, ServiceAnnotationLoadBalancerSSLClientCASecret)
+		}
+		return nil, nil
+	}
+	if caSecretOk {
+		if getLoadBalancerType(svc) == NLB {
+			return nil, fmt.Errorf("annotation %s is not supported by network load balancers", ServiceAnnotationLoadBalancerSSLClientCASecret)
+		}
+		for _, annotation := range []string{ServiceAnnotationLoadBalancerSSLPorts, ServiceAnnotationLoadBalancerTLSSecret} {
+			if _, ok := svc.Annotations[annotation]; !ok {
+				return nil, fmt.Errorf("annotation %s requires annotation %s", ServiceAnnotationLoadBalancerSSLClientCASecret, annotation)
+			}
+		}
+	}
+	if !verifyDepthOk {
+		return common.Int(defaultSSLClientVerifyDepth), nil
+	}
+	depth, err := strconv.Atoi(verifyDepth)
+	if err != nil || depth <= 0 {
+		return nil, fmt.Errorf("invalid value: %s provided for annotation: %s, it must be a positive number", verifyDepth, ServiceAnnotationLoadBalancerSSLClientVerifyDepth)
+	}
+	return &depth, nil
+}
+
+// validateSSLCertificateIDs validates the OCI Certificates service certificate
+// and CA bundle annotations of the SSL listeners and backend sets.
+func validateSSLCertificateIDs(svc *v1.Service) error {
+	certificateIDAnnotations := []struct {
+		certificateID string
+		caBundleID    string
+		secret        string
+	}{
+		{
+			certificateID: ServiceAnnotationLoadBalancerSSLCertificateID,
+			caBundleID:    ServiceAnnotationLoadBalancerSSLCABundleID,
+			secret:        ServiceAnnotationLoadBalancerTLSSecret,
+		},
+		{
+			certificateID: ServiceAnnotationLoadBalancerSSLBackendSetCertificateID,
+			caBundleID:    ServiceAnnotationLoadBalancerSSLBackendSetCABundleID,
+			secret:        ServiceAnnotationLoadBalancerTLSBackendSetSecret,
+		},
+	}
+	for _, annotations := range certificateIDAnnotations {
+		certificateID, certificateIDOk := svc.Annotations[annotations.certificateID]
+		caBundleID, caBundleIDOk := svc.Annotations[annotations.caBundleID]
+		if !certificateIDOk {
+			if caBundleIDOk {
+				return fmt.Errorf("annotation %s requires annotation %s", annotations.caBundleID, annotations.certificateID)
+			}
+			continue
+		}
+		if getLoadBalancerType(svc) == NLB {
+			return fmt.Errorf("annotation %s is not supported by network load balancers", annotations.certificateID)
+		}
+		if _, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLPorts]; !ok {
+			return fmt.Errorf("annotation %s requires annotation %s", annotations.certificateID, ServiceAnnotationLoadBalancerSSLPorts)
+		}
+		if _, ok := svc.Annotations[annotations.secret]; ok {
+			return fmt.Errorf("annotation %s can't be used with annotation %s", annotations.certificateID, annotations.secret)
+		}
+		if certificateID == "" {
+			return fmt.Errorf("invalid value: %s provided for annotation: %s, it must be a certificate OCID", certificateID, annotations.certificateID)
+		}
+		if caBundleIDOk && caBundleID == "" {
+			return fmt.Errorf("invalid value: %s provided for annotation: %s, it must be a CA bundle OCID", caBundleID, annotations.caBundleID)
+		}
+	}
+	if _, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLCABundleID]; ok {
+		if _, ok := svc.Annotations[ServiceAnnotationLoadBalancerSSLClientCASecret]; ok {
+			return fmt.Errorf("annotation %s can't be used with annotation %s", ServiceAnnotationLoadBalancerSSLCABundleID, ServiceAnnotationLoadBalancerSSLClientCASecret)
+		}
+	}
+	return nil
+}
+
+// getSSLCipherSuites builds a map of the custom cipher suites to create on the LB.
+func getSSLCipherSuites(svc *v1.Service) (map[string]client.GenericSslCipherSuite, error) {
+	cfg, err := getSSLCipherConfiguration(svc)
+	if err != nil || cfg == nil || cfg.customCipherSuite == nil {
+		return nil, err
+	}
+	return map[string]client.GenericSslCipherSuite{
+		*cfg.customCipherSuite.Name: *cfg.customCipherSuite,
+	}, nil
 }
 
 func getListenersOciLoadBalancer(svc *v1.Service, sslCfg *SSLConfig) (map[string]client.GenericListener, error) {
+	sslCipherCfg, err := getSSLCipherConfiguration(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	clientVerifyDepth, err := getSSLClientVerifyDepth(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificateName, certificateID, caBundleID string
+	if sslCfg != nil {
+		certificateID, caBundleID = sslCfg.ListenerCertificateID, sslCfg.ListenerCABundleID
+		if len(sslCfg.ListenerSSLSecretName) != 0 {
+			cert, err := sslCfg.listenerCertificate()
+			if err != nil {
+				return nil, err
+			}
+			certificateName = *cert.CertificateName
+		}
+	}
+
 	// Determine if connection idle timeout has been specified
 	var connectionIdleTimeout *int64
 	connectionIdleTimeoutAnnotation := svc.Annotations[ServiceAnnotationLoadBalancerConnectionIdleTimeout]
@@ -736,11 +1329,14 @@ func getListenersOciLoadBalancer(svc *v1.Service, sslCfg *SSLConfig) (map[string
 			}
 		}
 		port := int(servicePort.Port)
-		var secretName string
-		if sslCfg != nil && len(sslCfg.ListenerSSLSecretName) != 0 {
-			secretName = sslCfg.ListenerSSLSecretName
+		sslConfiguration := getSSLConfiguration(sslCfg, sslCipherCfg, certificateName, certificateID, port)
+		if sslConfiguration != nil && clientVerifyDepth != nil {
+			sslConfiguration.VerifyPeerCertificate = common.Bool(true)
+			sslConfiguration.VerifyDepth = clientVerifyDepth
+			if len(caBundleID) != 0 {
+				sslConfiguration.TrustedCertificateAuthorityIds = []string{caBundleID}
+			}
 		}
-		sslConfiguration := getSSLConfiguration(sslCfg, secretName, port)
 		name := getListenerName(protocol, port)
 
 		listener := client.GenericListener{
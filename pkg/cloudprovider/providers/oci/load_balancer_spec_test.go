@@ -64,7 +64,33 @@ func (ssr mockSSLSecretReader) readSSLSecret(ns, name string) (sslSecret *certif
 	return nil, nil
 }
 
+func (ssr mockSSLSecretReader) readCASecret(ns, name string) (caCert []byte, err error) {
+	cert, err := ssr.readSSLSecret(ns, name)
+	if err != nil || cert == nil {
+		return nil, err
+	}
+	return cert.CACert, nil
+}
+
 func TestNewLBSpecSuccess(t *testing.T) {
+	sslSecrets := &mockSSLSecretReader{
+		returnMap: map[struct {
+			namespaceArg string
+			nameArg      string
+		}]*certificateData{
+			{nameArg: listenerSecret}: {
+				CACert:     []byte("listenerca"),
+				PublicCert: []byte("listenercert"),
+				PrivateKey: []byte("listenerkey"),
+			},
+			{nameArg: backendSecret}: {
+				CACert:     []byte("backendca"),
+				PublicCert: []byte("backendcert"),
+				PrivateKey: []byte("backendkey"),
+			},
+		},
+	}
+
 	testCases := map[string]struct {
 		defaultSubnetOne string
 		defaultSubnetTwo string
@@ -1429,7 +1455,7 @@ func TestNewLBSpecSuccess(t *testing.T) {
 						Port:                  common.Int(443),
 						Protocol:              common.String("TCP"),
 						SslConfiguration: &client.GenericSslConfigurationDetails{
-							CertificateName:       &listenerSecret,
+							CertificateName:       common.String(listenerSecret + "-3640b388f5e2"),
 							VerifyDepth:           common.Int(0),
 							VerifyPeerCertificate: common.Bool(false),
 						},
@@ -1450,7 +1476,7 @@ func TestNewLBSpecSuccess(t *testing.T) {
 						IsPreserveSource: common.Bool(false),
 						Policy:           common.String("ROUND_ROBIN"),
 						SslConfiguration: &client.GenericSslConfigurationDetails{
-							CertificateName:       &backendSecret,
+							CertificateName:       common.String(backendSecret + "-696b1d135b24"),
 							VerifyDepth:           common.Int(0),
 							VerifyPeerCertificate: common.Bool(false),
 						},
@@ -1470,12 +1496,14 @@ func TestNewLBSpecSuccess(t *testing.T) {
 					Ports:                   sets.NewInt(443),
 					ListenerSSLSecretName:   listenerSecret,
 					BackendSetSSLSecretName: backendSecret,
+					sslSecretReader:         sslSecrets,
 				},
 			},
 			sslConfig: &SSLConfig{
 				Ports:                   sets.NewInt(443),
 				ListenerSSLSecretName:   listenerSecret,
 				BackendSetSSLSecretName: backendSecret,
+				sslSecretReader:         sslSecrets,
 			},
 		},
 		"custom health check config": {
@@ -2429,6 +2457,12 @@ func TestCertificates(t *testing.T) {
 	listenerSecretPrivateKey := "privatekey2"
 	listenerSecretPassphrase := "passphrase2"
 
+	backendCertificateName := backendSecret + "-bb5bf083adbd"
+	listenerCertificateName := listenerSecret + "-dcc311206f88"
+
+	clientCACert := "clientcacert"
+	clientCACertificateName := listenerSecret + "-b6ebbb072a9e"
+
 	testCases := map[string]struct {
 		lbSpec         *LBSpec
 		expectedResult map[string]client.GenericCertificate
@@ -2468,8 +2502,8 @@ func TestCertificates(t *testing.T) {
 				},
 			},
 			expectedResult: map[string]client.GenericCertificate{
-				backendSecret: {
-					CertificateName:   &backendSecret,
+				backendCertificateName: {
+					CertificateName:   &backendCertificateName,
 					CaCertificate:     &backendSecretCaCert,
 					Passphrase:        &backendSecretPassphrase,
 					PrivateKey:        &backendSecretPrivateKey,
@@ -2515,15 +2549,15 @@ func TestCertificates(t *testing.T) {
 				},
 			},
 			expectedResult: map[string]client.GenericCertificate{
-				backendSecret: {
-					CertificateName:   &backendSecret,
+				backendCertificateName: {
+					CertificateName:   &backendCertificateName,
 					CaCertificate:     &backendSecretCaCert,
 					Passphrase:        &backendSecretPassphrase,
 					PrivateKey:        &backendSecretPrivateKey,
 					PublicCertificate: &backendSecretPublicCert,
 				},
-				listenerSecret: {
-					CertificateName:   &listenerSecret,
+				listenerCertificateName: {
+					CertificateName:   &listenerCertificateName,
 					CaCertificate:     &listenerSecretCaCert,
 					Passphrase:        &listenerSecretPassphrase,
 					PrivateKey:        &listenerSecretPrivateKey,
@@ -2531,6 +2565,43 @@ func TestCertificates(t *testing.T) {
 				},
 			},
 		},
+		"Return listener SSL secret with the client CA": {
+			expectError: false,
+			lbSpec: &LBSpec{
+				SSLConfig: &SSLConfig{
+					ListenerSSLSecretName:           listenerSecret,
+					ListenerSSLSecretNamespace:      "listenernamespace",
+					ListenerClientCASecretName:      "clientca",
+					ListenerClientCASecretNamespace: "listenernamespace",
+					sslSecretReader: &mockSSLSecretReader{
+						returnError: false,
+						returnMap: map[struct {
+							namespaceArg string
+							nameArg      string
+						}]*certificateData{
+							{namespaceArg: "listenernamespace", nameArg: listenerSecret}: {
+								CACert:     []byte(listenerSecretCaCert),
+								PublicCert: []byte(listenerSecretPublicCert),
+								PrivateKey: []byte(listenerSecretPrivateKey),
+								Passphrase: []byte(listenerSecretPassphrase),
+							},
+							{namespaceArg: "listenernamespace", nameArg: "clientca"}: {
+								CACert: []byte(clientCACert),
+							},
+						},
+					},
+				},
+			},
+			expectedResult: map[string]client.GenericCertificate{
+				clientCACertificateName: {
+					CertificateName:   &clientCACertificateName,
+					CaCertificate:     &clientCACert,
+					Passphrase:        &listenerSecretPassphrase,
+					PrivateKey:        &listenerSecretPrivateKey,
+					PublicCertificate: &listenerSecretPublicCert,
+				},
+			},
+		},
 		"Error returned from SSL secret reader is handled gracefully": {
 			expectError: true,
 			lbSpec: &LBSpec{
@@ -3912,6 +3983,20 @@ func Test_validateService(t *testing.T){
 			},
 			err: fmt.Errorf("OCI only supports SessionAffinity \"None\" currently"),
 		},
+		"session persistence with nlb": {
+			service: &v1.Service{
+				Spec: v1.ServiceSpec{
+					SessionAffinity: v1.ServiceAffinityNone,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ServiceAnnotationLoadBalancerType:               "nlb",
+						ServiceAnnotationLoadBalancerSessionPersistence: "lb-cookie",
+					},
+				},
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-session-persistence is not supported by network load balancers"),
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -3928,3 +4013,547 @@ func Test_validateService(t *testing.T){
 		})
 	}
 }
+
+func Test_getSessionPersistenceConfiguration(t *testing.T) {
+	testCases := map[string]struct {
+		annotations       map[string]string
+		expectedAppCookie *client.GenericSessionPersistenceConfiguration
+		expectedLBCookie  *client.GenericLbCookieSessionPersistenceConfiguration
+		err               error
+	}{
+		"no session persistence": {
+			annotations: map[string]string{},
+		},
+		"application cookie": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                        "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:                "app-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieName:      "JSESSIONID",
+				ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback: "true",
+			},
+			expectedAppCookie: &client.GenericSessionPersistenceConfiguration{
+				CookieName:      common.String("JSESSIONID"),
+				DisableFallback: common.Bool(true),
+			},
+		},
+		"lb cookie defaults": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:         "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence: "LB-Cookie",
+			},
+			expectedLBCookie: &client.GenericLbCookieSessionPersistenceConfiguration{
+				CookieName:      common.String("X-Oracle-BMC-LBS-Route"),
+				DisableFallback: common.Bool(false),
+				Path:            common.String("/"),
+				IsSecure:        common.Bool(false),
+				IsHttpOnly:      common.Bool(true),
+			},
+		},
+		"lb cookie": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                        "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:                "lb-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieName:      "route",
+				ServiceAnnotationLoadBalancerSessionPersistenceDisableFallback: "true",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieDomain:    "example.com",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookiePath:      "/app",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge:    "3600",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure:    "true",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly:  "false",
+			},
+			expectedLBCookie: &client.GenericLbCookieSessionPersistenceConfiguration{
+				CookieName:      common.String("route"),
+				DisableFallback: common.Bool(true),
+				Domain:          common.String("example.com"),
+				Path:            common.String("/app"),
+				MaxAgeInSeconds: common.Int(3600),
+				IsSecure:        common.Bool(true),
+				IsHttpOnly:      common.Bool(false),
+			},
+		},
+		"cookie annotation without session persistence": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieName: "JSESSIONID",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-session-persistence-cookie-name requires annotation oci.oraclecloud.com/loadbalancer-session-persistence"),
+		},
+		"invalid session persistence": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:         "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence: "client-ip",
+			},
+			err: fmt.Errorf("invalid value: client-ip provided for annotation: oci.oraclecloud.com/loadbalancer-session-persistence, only \"app-cookie\" and \"lb-cookie\" are supported"),
+		},
+		"tcp backend protocol": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSessionPersistence: "lb-cookie",
+			},
+			err: fmt.Errorf("session persistence requires the HTTP backend protocol, set annotation service.beta.kubernetes.io/oci-load-balancer-backend-protocol to \"HTTP\""),
+		},
+		"application cookie without cookie name": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:         "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence: "app-cookie",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-session-persistence-cookie-name must name the application cookie, or be \"*\" to match any cookie"),
+		},
+		"application cookie with lb cookie attribute": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                     "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:             "app-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieName:   "*",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieSecure: "true",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-session-persistence-cookie-secure is only supported with \"lb-cookie\" session persistence"),
+		},
+		"lb cookie with wildcard cookie name": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                   "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:           "lb-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieName: "*",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-session-persistence-cookie-name can't be \"*\" with \"lb-cookie\" session persistence"),
+		},
+		"invalid max age": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                     "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:             "lb-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieMaxAge: "-1",
+			},
+			err: fmt.Errorf("invalid value: -1 provided for annotation: oci.oraclecloud.com/loadbalancer-session-persistence-cookie-max-age, it must be a positive number of seconds"),
+		},
+		"invalid boolean": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerBEProtocol:                       "HTTP",
+				ServiceAnnotationLoadBalancerSessionPersistence:               "lb-cookie",
+				ServiceAnnotationLoadBalancerSessionPersistenceCookieHttpOnly: "yes please",
+			},
+			err: fmt.Errorf("invalid value: yes please provided for annotation: oci.oraclecloud.com/loadbalancer-session-persistence-cookie-http-only"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			appCookie, lbCookie, err := getSessionPersistenceConfiguration(svc)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("Expected error\n%+v\nbut got\n%+v", tc.err, err)
+			}
+			if !reflect.DeepEqual(appCookie, tc.expectedAppCookie) {
+				t.Errorf("Expected application cookie configuration\n%+v\nbut got\n%+v", tc.expectedAppCookie, appCookie)
+			}
+			if !reflect.DeepEqual(lbCookie, tc.expectedLBCookie) {
+				t.Errorf("Expected LB cookie configuration\n%+v\nbut got\n%+v", tc.expectedLBCookie, lbCookie)
+			}
+		})
+	}
+}
+
+func Test_getSSLCipherConfiguration(t *testing.T) {
+	testCases := map[string]struct {
+		annotations  map[string]string
+		expected     *sslCipherConfiguration
+		cipherSuites map[string]client.GenericSslCipherSuite
+		err          error
+	}{
+		"no cipher annotations": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts: "443",
+			},
+		},
+		"predefined cipher suite": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:                 "443",
+				ServiceAnnotationLoadBalancerSSLCipherSuite:           "oci-modern-ssl-cipher-suite-v1",
+				ServiceAnnotationLoadBalancerSSLProtocols:             "tlsv1.2, TLSv1.3",
+				ServiceAnnotationLoadBalancerSSLServerOrderPreference: "Enabled",
+			},
+			expected: &sslCipherConfiguration{
+				cipherSuiteName:       common.String("oci-modern-ssl-cipher-suite-v1"),
+				protocols:             []string{"TLSv1.2", "TLSv1.3"},
+				serverOrderPreference: "ENABLED",
+			},
+		},
+		"custom cipher suite": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:       "443",
+				ServiceAnnotationLoadBalancerSSLCipherSuite: "approved-ciphers",
+				ServiceAnnotationLoadBalancerSSLCiphers:     "ECDHE-RSA-AES256-GCM-SHA384, ECDHE-RSA-AES128-GCM-SHA256,",
+			},
+			expected: &sslCipherConfiguration{
+				cipherSuiteName: common.String("approved-ciphers"),
+				customCipherSuite: &client.GenericSslCipherSuite{
+					Name:    common.String("approved-ciphers"),
+					Ciphers: []string{"ECDHE-RSA-AES256-GCM-SHA384", "ECDHE-RSA-AES128-GCM-SHA256"},
+				},
+			},
+			cipherSuites: map[string]client.GenericSslCipherSuite{
+				"approved-ciphers": {
+					Name:    common.String("approved-ciphers"),
+					Ciphers: []string{"ECDHE-RSA-AES256-GCM-SHA384", "ECDHE-RSA-AES128-GCM-SHA256"},
+				},
+			},
+		},
+		"without ssl ports": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLProtocols: "TLSv1.2",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-protocols requires annotation service.beta.kubernetes.io/oci-load-balancer-ssl-ports"),
+		},
+		"nlb": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerType:           "nlb",
+				ServiceAnnotationLoadBalancerSSLPorts:       "443",
+				ServiceAnnotationLoadBalancerSSLCipherSuite: "oci-modern-ssl-cipher-suite-v1",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-cipher-suite is not supported by network load balancers"),
+		},
+		"ciphers without cipher suite": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:   "443",
+				ServiceAnnotationLoadBalancerSSLCiphers: "ECDHE-RSA-AES256-GCM-SHA384",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-ciphers requires annotation oci.oraclecloud.com/loadbalancer-ssl-cipher-suite to name the custom cipher suite"),
+		},
+		"custom cipher suite with predefined name": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:       "443",
+				ServiceAnnotationLoadBalancerSSLCipherSuite: "oci-approved-ciphers",
+				ServiceAnnotationLoadBalancerSSLCiphers:     "ECDHE-RSA-AES256-GCM-SHA384",
+			},
+			err: fmt.Errorf("invalid value: oci-approved-ciphers provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-cipher-suite, the names of custom cipher suites can't start with \"oci-\""),
+		},
+		"empty ciphers": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:       "443",
+				ServiceAnnotationLoadBalancerSSLCipherSuite: "approved-ciphers",
+				ServiceAnnotationLoadBalancerSSLCiphers:     " , ",
+			},
+			err: fmt.Errorf("invalid value:  ,  provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-ciphers"),
+		},
+		"invalid protocol": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:     "443",
+				ServiceAnnotationLoadBalancerSSLProtocols: "TLSv1.2,SSLv3",
+			},
+			err: fmt.Errorf("invalid value: SSLv3 provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-protocols, only TLSv1, TLSv1.1, TLSv1.2 and TLSv1.3 are supported"),
+		},
+		"invalid server order preference": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:                 "443",
+				ServiceAnnotationLoadBalancerSSLServerOrderPreference: "true",
+			},
+			err: fmt.Errorf("invalid value: true provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-server-order-preference, only \"Enabled\" and \"Disabled\" are supported"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			cfg, err := getSSLCipherConfiguration(svc)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("Expected error\n%+v\nbut got\n%+v", tc.err, err)
+			}
+			if !reflect.DeepEqual(cfg, tc.expected) {
+				t.Errorf("Expected SSL cipher configuration\n%+v\nbut got\n%+v", tc.expected, cfg)
+			}
+			cipherSuites, _ := getSSLCipherSuites(svc)
+			if !reflect.DeepEqual(cipherSuites, tc.cipherSuites) {
+				t.Errorf("Expected SSL cipher suites\n%+v\nbut got\n%+v", tc.cipherSuites, cipherSuites)
+			}
+		})
+	}
+}
+
+func Test_getSSLConfigurationWithCiphers(t *testing.T) {
+	sslCfg := &SSLConfig{Ports: sets.NewInt(443)}
+	cipherCfg := &sslCipherConfiguration{
+		cipherSuiteName:       common.String("oci-modern-ssl-cipher-suite-v1"),
+		protocols:             []string{"TLSv1.2"},
+		serverOrderPreference: SSLServerOrderPreferenceEnabled,
+	}
+	expected := &client.GenericSslConfigurationDetails{
+		CertificateName:       common.String("cert"),
+		VerifyDepth:           common.Int(0),
+		VerifyPeerCertificate: common.Bool(false),
+		CipherSuiteName:       common.String("oci-modern-ssl-cipher-suite-v1"),
+		Protocols:             []string{"TLSv1.2"},
+		ServerOrderPreference: SSLServerOrderPreferenceEnabled,
+	}
+	if sslConfiguration := getSSLConfiguration(sslCfg, cipherCfg, "cert", "", 443); !reflect.DeepEqual(sslConfiguration, expected) {
+		t.Errorf("Expected SSL configuration\n%+v\nbut got\n%+v", expected, sslConfiguration)
+	}
+	if sslConfiguration := getSSLConfiguration(sslCfg, cipherCfg, "cert", "", 80); sslConfiguration != nil {
+		t.Errorf("Expected no SSL configuration for a non SSL port but got %+v", sslConfiguration)
+	}
+}
+
+func Test_getSSLClientVerifyDepth(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    *int
+		err         error
+	}{
+		"no client verification": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts: "443",
+			},
+		},
+		"default verify depth": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:          "443",
+				ServiceAnnotationLoadBalancerTLSSecret:         "ssl-certificate-secret",
+				ServiceAnnotationLoadBalancerSSLClientCASecret: "client-ca",
+			},
+			expected: common.Int(1),
+		},
+		"verify depth": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:             "443",
+				ServiceAnnotationLoadBalancerTLSSecret:            "ssl-certificate-secret",
+				ServiceAnnotationLoadBalancerSSLClientCASecret:    "client-ca",
+				ServiceAnnotationLoadBalancerSSLClientVerifyDepth: "3",
+			},
+			expected: common.Int(3),
+		},
+		"verify depth without client CA": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLClientVerifyDepth: "3",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-client-verify-depth requires annotation oci.oraclecloud.com/loadbalancer-ssl-client-ca-secret"),
+		},
+		"client CA without TLS secret": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:          "443",
+				ServiceAnnotationLoadBalancerSSLClientCASecret: "client-ca",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-client-ca-secret requires annotation service.beta.kubernetes.io/oci-load-balancer-tls-secret"),
+		},
+		"client CA with nlb": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerType:              "nlb",
+				ServiceAnnotationLoadBalancerSSLClientCASecret: "client-ca",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-client-ca-secret is not supported by network load balancers"),
+		},
+		"CA bundle verify depth": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:             "443",
+				ServiceAnnotationLoadBalancerSSLCertificateID:     "ocid1.certificate.oc1..a",
+				ServiceAnnotationLoadBalancerSSLCABundleID:        "ocid1.cabundle.oc1..a",
+				ServiceAnnotationLoadBalancerSSLClientVerifyDepth: "2",
+			},
+			expected: common.Int(2),
+		},
+		"invalid verify depth": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:             "443",
+				ServiceAnnotationLoadBalancerTLSSecret:            "ssl-certificate-secret",
+				ServiceAnnotationLoadBalancerSSLClientCASecret:    "client-ca",
+				ServiceAnnotationLoadBalancerSSLClientVerifyDepth: "0",
+			},
+			err: fmt.Errorf("invalid value: 0 provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-client-verify-depth, it must be a positive number"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			depth, err := getSSLClientVerifyDepth(svc)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("Expected error\n%+v\nbut got\n%+v", tc.err, err)
+			}
+			if !reflect.DeepEqual(depth, tc.expected) {
+				t.Errorf("Expected verify depth %v but got %v", toInt(tc.expected), toInt(depth))
+			}
+		})
+	}
+}
+
+func Test_getListenersWithClientVerification(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:             "443",
+				ServiceAnnotationLoadBalancerTLSSecret:            "ssl-certificate-secret",
+				ServiceAnnotationLoadBalancerSSLClientCASecret:    "client-ca",
+				ServiceAnnotationLoadBalancerSSLClientVerifyDepth: "2",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Protocol: v1.ProtocolTCP, Port: 443},
+			},
+		},
+	}
+	reader := &mockSSLSecretReader{
+		returnMap: map[struct {
+			namespaceArg string
+			nameArg      string
+		}]*certificateData{
+			{namespaceArg: "default", nameArg: "ssl-certificate-secret"}: {
+				PublicCert: []byte("publiccert"),
+				PrivateKey: []byte("privatekey"),
+			},
+			{namespaceArg: "default", nameArg: "client-ca"}: {CACert: []byte("clientcacert")},
+		},
+	}
+	sslCfg := NewSSLConfig("ssl-certificate-secret", "", svc, []int{443}, reader)
+
+	listeners, err := getListeners(svc, sslCfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &client.GenericSslConfigurationDetails{
+		CertificateName:       common.String("ssl-certificate-secret-8e4292a64f2b"),
+		VerifyDepth:           common.Int(2),
+		VerifyPeerCertificate: common.Bool(true),
+	}
+	if sslConfiguration := listeners["TCP-443"].SslConfiguration; !reflect.DeepEqual(sslConfiguration, expected) {
+		t.Errorf("Expected SSL configuration\n%+v\nbut got\n%+v", expected, sslConfiguration)
+	}
+
+	// Rotating the client CA renames the certificate bundle of the listener.
+	reader.returnMap[struct {
+		namespaceArg string
+		nameArg      string
+	}{namespaceArg: "default", nameArg: "client-ca"}] = &certificateData{CACert: []byte("rotatedclientcacert")}
+	listeners, err = getListeners(svc, sslCfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name := toString(listeners["TCP-443"].SslConfiguration.CertificateName); name == *expected.CertificateName {
+		t.Errorf("Expected the certificate bundle to be renamed when the client CA is rotated, got %s", name)
+	}
+}
+
+func Test_validateSSLCertificateIDs(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		err         error
+	}{
+		"no certificate OCIDs": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:  "443",
+				ServiceAnnotationLoadBalancerTLSSecret: "ssl-certificate-secret",
+			},
+		},
+		"listener and backend set certificate OCIDs": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:                   "443",
+				ServiceAnnotationLoadBalancerSSLCertificateID:           "ocid1.certificate.oc1..a",
+				ServiceAnnotationLoadBalancerSSLCABundleID:              "ocid1.cabundle.oc1..a",
+				ServiceAnnotationLoadBalancerSSLBackendSetCertificateID: "ocid1.certificate.oc1..b",
+				ServiceAnnotationLoadBalancerSSLBackendSetCABundleID:    "ocid1.cabundle.oc1..b",
+			},
+		},
+		"CA bundle without certificate": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:                "443",
+				ServiceAnnotationLoadBalancerSSLBackendSetCABundleID: "ocid1.cabundle.oc1..b",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-backendset-ca-bundle-id requires annotation oci.oraclecloud.com/loadbalancer-ssl-backendset-certificate-id"),
+		},
+		"certificate without ssl ports": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLCertificateID: "ocid1.certificate.oc1..a",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-certificate-id requires annotation service.beta.kubernetes.io/oci-load-balancer-ssl-ports"),
+		},
+		"certificate with TLS secret": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:         "443",
+				ServiceAnnotationLoadBalancerTLSSecret:        "ssl-certificate-secret",
+				ServiceAnnotationLoadBalancerSSLCertificateID: "ocid1.certificate.oc1..a",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-certificate-id can't be used with annotation service.beta.kubernetes.io/oci-load-balancer-tls-secret"),
+		},
+		"empty certificate": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:         "443",
+				ServiceAnnotationLoadBalancerSSLCertificateID: "",
+			},
+			err: fmt.Errorf("invalid value:  provided for annotation: oci.oraclecloud.com/loadbalancer-ssl-certificate-id, it must be a certificate OCID"),
+		},
+		"CA bundle with client CA secret": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:          "443",
+				ServiceAnnotationLoadBalancerSSLCertificateID:  "ocid1.certificate.oc1..a",
+				ServiceAnnotationLoadBalancerSSLCABundleID:     "ocid1.cabundle.oc1..a",
+				ServiceAnnotationLoadBalancerSSLClientCASecret: "client-ca",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-ca-bundle-id can't be used with annotation oci.oraclecloud.com/loadbalancer-ssl-client-ca-secret"),
+		},
+		"certificate with nlb": {
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerType:             "nlb",
+				ServiceAnnotationLoadBalancerSSLCertificateID: "ocid1.certificate.oc1..a",
+			},
+			err: fmt.Errorf("annotation oci.oraclecloud.com/loadbalancer-ssl-certificate-id is not supported by network load balancers"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if err := validateSSLCertificateIDs(svc); !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("Expected error\n%+v\nbut got\n%+v", tc.err, err)
+			}
+		})
+	}
+}
+
+func Test_getSSLConfigurationWithCertificateIDs(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:                   "443",
+				ServiceAnnotationLoadBalancerSSLCertificateID:           "ocid1.certificate.oc1..a",
+				ServiceAnnotationLoadBalancerSSLCABundleID:              "ocid1.cabundle.oc1..a",
+				ServiceAnnotationLoadBalancerSSLBackendSetCertificateID: "ocid1.certificate.oc1..b",
+				ServiceAnnotationLoadBalancerSSLBackendSetCABundleID:    "ocid1.cabundle.oc1..b",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Protocol: v1.ProtocolTCP, Port: 443},
+			},
+		},
+	}
+	// No secret is read when the certificates are in the OCI Certificates service.
+	sslCfg := NewSSLConfig("", "", svc, []int{443}, nil)
+
+	listeners, err := getListeners(svc, sslCfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedListener := &client.GenericSslConfigurationDetails{
+		CertificateIds:                 []string{"ocid1.certificate.oc1..a"},
+		TrustedCertificateAuthorityIds: []string{"ocid1.cabundle.oc1..a"},
+		VerifyDepth:                    common.Int(1),
+		VerifyPeerCertificate:          common.Bool(true),
+	}
+	if sslConfiguration := listeners["TCP-443"].SslConfiguration; !reflect.DeepEqual(sslConfiguration, expectedListener) {
+		t.Errorf("Expected listener SSL configuration\n%+v\nbut got\n%+v", expectedListener, sslConfiguration)
+	}
+
+	backendSets, err := getBackendSets(zap.S(), svc, nil, sslCfg, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedBackendSet := &client.GenericSslConfigurationDetails{
+		CertificateIds:                 []string{"ocid1.certificate.oc1..b"},
+		TrustedCertificateAuthorityIds: []string{"ocid1.cabundle.oc1..b"},
+		VerifyDepth:                    common.Int(1),
+		VerifyPeerCertificate:          common.Bool(true),
+	}
+	if sslConfiguration := backendSets["TCP-443"].SslConfiguration; !reflect.DeepEqual(sslConfiguration, expectedBackendSet) {
+		t.Errorf("Expected backend set SSL configuration\n%+v\nbut got\n%+v", expectedBackendSet, sslConfiguration)
+	}
+
+	certificates, err := (&LBSpec{SSLConfig: sslCfg}).Certificates()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(certificates) != 0 {
+		t.Errorf("Expected no certificate bundle to upload but got %+v", certificates)
+	}
+}
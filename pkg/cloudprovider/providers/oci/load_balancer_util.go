@@ -248,6 +248,11 @@ func hasBackendSetChanged(logger *zap.SugaredLogger, actual client.GenericBacken
 		backendSetChanges = append(backendChanges)
 	}
 
+	backendSetChanges = append(backendSetChanges, getSessionPersistenceChanges(actual, desired)...)
+	// The certificate changes when the backend set secret is renewed.
+	backendSetChanges = append(backendSetChanges, getSSLCertificateChanges("BackendSet:SSLConfiguration", actual.SslConfiguration, desired.SslConfiguration)...)
+	backendSetChanges = append(backendSetChanges, getSSLCipherChanges("BackendSet:SSLConfiguration", actual.SslConfiguration, desired.SslConfiguration)...)
+
 	if len(backendSetChanges) != 0 {
 		logger.Infof("BackendSet needs to be updated for the change(s) - %s", strings.Join(backendSetChanges, ","))
 		return true
@@ -255,6 +260,57 @@ func hasBackendSetChanged(logger *zap.SugaredLogger, actual client.GenericBacken
 	return false
 }
 
+func getSessionPersistenceChanges(actual client.GenericBackendSetDetails, desired client.GenericBackendSetDetails) []string {
+	var sessionPersistenceChanges []string
+
+	actualAppCookie, desiredAppCookie := actual.SessionPersistenceConfiguration, desired.SessionPersistenceConfiguration
+	switch {
+	case actualAppCookie == nil && desiredAppCookie != nil:
+		sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration", "NOT_PRESENT", "PRESENT"))
+	case actualAppCookie != nil && desiredAppCookie == nil:
+		sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration", "PRESENT", "NOT_PRESENT"))
+	case actualAppCookie != nil && desiredAppCookie != nil:
+		if toString(actualAppCookie.CookieName) != toString(desiredAppCookie.CookieName) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration:CookieName", toString(actualAppCookie.CookieName), toString(desiredAppCookie.CookieName)))
+		}
+		if toBool(actualAppCookie.DisableFallback) != toBool(desiredAppCookie.DisableFallback) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration:DisableFallback", toBool(actualAppCookie.DisableFallback), toBool(desiredAppCookie.DisableFallback)))
+		}
+	}
+
+	actualLBCookie, desiredLBCookie := actual.LbCookieSessionPersistenceConfiguration, desired.LbCookieSessionPersistenceConfiguration
+	switch {
+	case actualLBCookie == nil && desiredLBCookie != nil:
+		sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration", "NOT_PRESENT", "PRESENT"))
+	case actualLBCookie != nil && desiredLBCookie == nil:
+		sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration", "PRESENT", "NOT_PRESENT"))
+	case actualLBCookie != nil && desiredLBCookie != nil:
+		if toString(actualLBCookie.CookieName) != toString(desiredLBCookie.CookieName) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:CookieName", toString(actualLBCookie.CookieName), toString(desiredLBCookie.CookieName)))
+		}
+		if toBool(actualLBCookie.DisableFallback) != toBool(desiredLBCookie.DisableFallback) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:DisableFallback", toBool(actualLBCookie.DisableFallback), toBool(desiredLBCookie.DisableFallback)))
+		}
+		if toString(actualLBCookie.Domain) != toString(desiredLBCookie.Domain) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:Domain", toString(actualLBCookie.Domain), toString(desiredLBCookie.Domain)))
+		}
+		if toString(actualLBCookie.Path) != toString(desiredLBCookie.Path) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:Path", toString(actualLBCookie.Path), toString(desiredLBCookie.Path)))
+		}
+		if toInt(actualLBCookie.MaxAgeInSeconds) != toInt(desiredLBCookie.MaxAgeInSeconds) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:MaxAgeInSeconds", toInt(actualLBCookie.MaxAgeInSeconds), toInt(desiredLBCookie.MaxAgeInSeconds)))
+		}
+		if toBool(actualLBCookie.IsSecure) != toBool(desiredLBCookie.IsSecure) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:IsSecure", toBool(actualLBCookie.IsSecure), toBool(desiredLBCookie.IsSecure)))
+		}
+		if toBool(actualLBCookie.IsHttpOnly) != toBool(desiredLBCookie.IsHttpOnly) {
+			sessionPersistenceChanges = append(sessionPersistenceChanges, fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:IsHttpOnly", toBool(actualLBCookie.IsHttpOnly), toBool(desiredLBCookie.IsHttpOnly)))
+		}
+	}
+
+	return sessionPersistenceChanges
+}
+
 func healthCheckerToDetails(hc *client.GenericHealthChecker) *client.GenericHealthChecker {
 	if hc == nil {
 		return nil
@@ -276,9 +332,14 @@ func sslConfigurationToDetails(sc *client.GenericSslConfigurationDetails) *clien
 		return nil
 	}
 	return &client.GenericSslConfigurationDetails{
-		CertificateName:       sc.CertificateName,
-		VerifyDepth:           sc.VerifyDepth,
-		VerifyPeerCertificate: sc.VerifyPeerCertificate,
+		CertificateName:                sc.CertificateName,
+		CertificateIds:                 sc.CertificateIds,
+		TrustedCertificateAuthorityIds: sc.TrustedCertificateAuthorityIds,
+		VerifyDepth:                    sc.VerifyDepth,
+		VerifyPeerCertificate:          sc.VerifyPeerCertificate,
+		CipherSuiteName:                sc.CipherSuiteName,
+		Protocols:                      sc.Protocols,
+		ServerOrderPreference:          sc.ServerOrderPreference,
 	}
 }
 
@@ -337,11 +398,12 @@ func getBackendSetChanges(logger *zap.SugaredLogger, actual map[string]client.Ge
 			backendSetActions = append(backendSetActions, &BackendSetAction{
 				name: *actualBackendSet.Name,
 				BackendSet: client.GenericBackendSetDetails{
-					HealthChecker:                   healthCheckerToDetails(actualBackendSet.HealthChecker),
-					Policy:                          actualBackendSet.Policy,
-					Backends:                        backendsToBackendDetails(actualBackendSet.Backends),
-					SessionPersistenceConfiguration: actualBackendSet.SessionPersistenceConfiguration,
-					SslConfiguration:                sslConfigurationToDetails(actualBackendSet.SslConfiguration),
+					HealthChecker:                           healthCheckerToDetails(actualBackendSet.HealthChecker),
+					Policy:                                  actualBackendSet.Policy,
+					Backends:                                backendsToBackendDetails(actualBackendSet.Backends),
+					SessionPersistenceConfiguration:         actualBackendSet.SessionPersistenceConfiguration,
+					LbCookieSessionPersistenceConfiguration: actualBackendSet.LbCookieSessionPersistenceConfiguration,
+					SslConfiguration:                        sslConfigurationToDetails(actualBackendSet.SslConfiguration),
 				},
 				Ports:      portsFromBackendSet(logger, *actualBackendSet.Name, &actualBackendSet),
 				actionType: Delete,
@@ -349,6 +411,7 @@ func getBackendSetChanges(logger *zap.SugaredLogger, actual map[string]client.Ge
 			continue
 		}
 
+		desiredBackendSet.SslConfiguration = withActualSSLCipherSettings(actualBackendSet.SslConfiguration, desiredBackendSet.SslConfiguration)
 		if hasBackendSetChanged(logger, actualBackendSet, desiredBackendSet) {
 			oldPorts := portsFromBackendSet(logger, name, &actualBackendSet)
 			backendSetActions = append(backendSetActions, &BackendSetAction{
@@ -391,18 +454,79 @@ func getSSLConfigurationChanges(actual *client.GenericSslConfigurationDetails, d
 		return sslConfigurationChanges
 	}
 
-	if toString(actual.CertificateName) != toString(desired.CertificateName) {
-		sslConfigurationChanges = append(sslConfigurationChanges, fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:CertificateName", toString(actual.CertificateName), toString(desired.CertificateName)))
-	}
+	sslConfigurationChanges = append(sslConfigurationChanges, getSSLCertificateChanges("Listener:SSLConfiguration", actual, desired)...)
 	if toInt(actual.VerifyDepth) != toInt(desired.VerifyDepth) {
 		sslConfigurationChanges = append(sslConfigurationChanges, fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:VerifyDepth", toInt(actual.VerifyDepth), toInt(desired.VerifyDepth)))
 	}
 	if toBool(actual.VerifyPeerCertificate) != toBool(desired.VerifyPeerCertificate) {
 		sslConfigurationChanges = append(sslConfigurationChanges, fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:VerifyPeerCertificate", toBool(actual.VerifyPeerCertificate), toBool(desired.VerifyPeerCertificate)))
 	}
+	sslConfigurationChanges = append(sslConfigurationChanges, getSSLCipherChanges("Listener:SSLConfiguration", actual, desired)...)
 	return sslConfigurationChanges
 }
 
+// getSSLCertificateChanges compares the certificate bundle name and the OCI
+// Certificates service certificate and CA bundle OCIDs of SSL configurations.
+func getSSLCertificateChanges(field string, actual *client.GenericSslConfigurationDetails, desired *client.GenericSslConfigurationDetails) []string {
+	var sslCertificateChanges []string
+	if actual == nil || desired == nil {
+		return sslCertificateChanges
+	}
+	if toString(actual.CertificateName) != toString(desired.CertificateName) {
+		sslCertificateChanges = append(sslCertificateChanges, fmt.Sprintf(changeFmtStr, field+":CertificateName", toString(actual.CertificateName), toString(desired.CertificateName)))
+	}
+	if !sets.NewString(actual.CertificateIds...).Equal(sets.NewString(desired.CertificateIds...)) {
+		sslCertificateChanges = append(sslCertificateChanges, fmt.Sprintf(changeFmtStr, field+":CertificateIds", strings.Join(actual.CertificateIds, ","), strings.Join(desired.CertificateIds, ",")))
+	}
+	if !sets.NewString(actual.TrustedCertificateAuthorityIds...).Equal(sets.NewString(desired.TrustedCertificateAuthorityIds...)) {
+		sslCertificateChanges = append(sslCertificateChanges, fmt.Sprintf(changeFmtStr, field+":TrustedCertificateAuthorityIds", strings.Join(actual.TrustedCertificateAuthorityIds, ","), strings.Join(desired.TrustedCertificateAuthorityIds, ",")))
+	}
+	return sslCertificateChanges
+}
+
+// withActualSSLCipherSettings returns a copy of the desired SSL configuration
+// in which the cipher suite, protocols and server order preference that are
+// not desired are those of the actual configuration. Updates replace the whole
+// SSL configuration, so the settings without annotation would otherwise be
+// reset to the LB defaults whenever the listener or backend set is updated.
+func withActualSSLCipherSettings(actual *client.GenericSslConfigurationDetails, desired *client.GenericSslConfigurationDetails) *client.GenericSslConfigurationDetails {
+	if actual == nil || desired == nil {
+		return desired
+	}
+	merged := *desired
+	if merged.CipherSuiteName == nil {
+		merged.CipherSuiteName = actual.CipherSuiteName
+	}
+	if len(merged.Protocols) == 0 {
+		merged.Protocols = actual.Protocols
+	}
+	if merged.ServerOrderPreference == "" {
+		merged.ServerOrderPreference = actual.ServerOrderPreference
+	}
+	return &merged
+}
+
+// getSSLCipherChanges compares the cipher suite, protocols and server order
+// preference of SSL configurations. The settings that are not desired are kept
+// as they are, see withActualSSLCipherSettings, so they are only compared when
+// they are set.
+func getSSLCipherChanges(field string, actual *client.GenericSslConfigurationDetails, desired *client.GenericSslConfigurationDetails) []string {
+	var sslCipherChanges []string
+	if actual == nil || desired == nil {
+		return sslCipherChanges
+	}
+	if desired.CipherSuiteName != nil && toString(actual.CipherSuiteName) != toString(desired.CipherSuiteName) {
+		sslCipherChanges = append(sslCipherChanges, fmt.Sprintf(changeFmtStr, field+":CipherSuiteName", toString(actual.CipherSuiteName), toString(desired.CipherSuiteName)))
+	}
+	if len(desired.Protocols) != 0 && !sets.NewString(actual.Protocols...).Equal(sets.NewString(desired.Protocols...)) {
+		sslCipherChanges = append(sslCipherChanges, fmt.Sprintf(changeFmtStr, field+":Protocols", strings.Join(actual.Protocols, ","), strings.Join(desired.Protocols, ",")))
+	}
+	if desired.ServerOrderPreference != "" && actual.ServerOrderPreference != desired.ServerOrderPreference {
+		sslCipherChanges = append(sslCipherChanges, fmt.Sprintf(changeFmtStr, field+":ServerOrderPreference", actual.ServerOrderPreference, desired.ServerOrderPreference))
+	}
+	return sslCipherChanges
+}
+
 func hasListenerChanged(logger *zap.SugaredLogger, actual client.GenericListener, desired client.GenericListener) bool {
 	logger = logger.With("ListenerName", toString(actual.Name))
 	var listenerChanges []string
@@ -480,6 +604,7 @@ func getListenerChanges(logger *zap.SugaredLogger, actual map[string]client.Gene
 			continue
 		}
 		exists.Insert(getSanitizedName(name))
+		desiredListener.SslConfiguration = withActualSSLCipherSettings(actualListener.SslConfiguration, desiredListener.SslConfiguration)
 		if hasListenerChanged(logger, actualListener, desiredListener) {
 			listenerActions = append(listenerActions, &ListenerAction{
 				Listener:   desiredListener,
@@ -851,6 +851,51 @@ func TestGetListenerChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "certificate change keeps the cipher settings without annotation",
+			desired: map[string]client.GenericListener{
+				"TCP-443": client.GenericListener{
+					DefaultBackendSetName: common.String("TCP-443"),
+					Protocol:              common.String("TCP"),
+					Port:                  common.Int(443),
+					SslConfiguration: &client.GenericSslConfigurationDetails{
+						CertificateName: common.String("new-certificate"),
+						Protocols:       []string{"TLSv1.2"},
+					},
+				},
+			},
+			actual: map[string]client.GenericListener{
+				"TCP-443": client.GenericListener{
+					Name:                  common.String("TCP-443"),
+					DefaultBackendSetName: common.String("TCP-443"),
+					Protocol:              common.String("TCP"),
+					Port:                  common.Int(443),
+					SslConfiguration: &client.GenericSslConfigurationDetails{
+						CertificateName:       common.String("old-certificate"),
+						CipherSuiteName:       common.String("approved-ciphers"),
+						Protocols:             []string{"TLSv1.2", "TLSv1.3"},
+						ServerOrderPreference: "ENABLED",
+					},
+				},
+			},
+			expected: []Action{
+				&ListenerAction{
+					name:       "TCP-443",
+					actionType: Update,
+					Listener: client.GenericListener{
+						DefaultBackendSetName: common.String("TCP-443"),
+						Protocol:              common.String("TCP"),
+						Port:                  common.Int(443),
+						SslConfiguration: &client.GenericSslConfigurationDetails{
+							CertificateName:       common.String("new-certificate"),
+							CipherSuiteName:       common.String("approved-ciphers"),
+							Protocols:             []string{"TLSv1.2"},
+							ServerOrderPreference: "ENABLED",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range testCases {
@@ -1272,6 +1317,22 @@ func TestHasBackendSetChanged(t *testing.T) {
 		actual   client.GenericBackendSetDetails
 		expected bool
 	}{
+		{
+			name: "SSL certificate renewed",
+			desired: client.GenericBackendSetDetails{
+				Policy: common.String("policy"),
+				SslConfiguration: &client.GenericSslConfigurationDetails{
+					CertificateName: common.String("backendsecret-bb5bf083adbd"),
+				},
+			},
+			actual: client.GenericBackendSetDetails{
+				Policy: common.String("policy"),
+				SslConfiguration: &client.GenericSslConfigurationDetails{
+					CertificateName: common.String("backendsecret"),
+				},
+			},
+			expected: true,
+		},
 		{
 			name: "Policy changes",
 			desired: client.GenericBackendSetDetails{
@@ -1713,6 +1774,108 @@ func TestGetHealthCheckerChanges(t *testing.T) {
 	}
 }
 
+func TestGetSessionPersistenceChanges(t *testing.T) {
+	lbCookie := func(path string, isSecure bool) *client.GenericLbCookieSessionPersistenceConfiguration {
+		return &client.GenericLbCookieSessionPersistenceConfiguration{
+			CookieName:      common.String("X-Oracle-BMC-LBS-Route"),
+			DisableFallback: common.Bool(false),
+			Path:            common.String(path),
+			IsSecure:        common.Bool(isSecure),
+			IsHttpOnly:      common.Bool(true),
+		}
+	}
+	var testCases = []struct {
+		name     string
+		desired  client.GenericBackendSetDetails
+		actual   client.GenericBackendSetDetails
+		expected []string
+	}{
+		{
+			name:     "No session persistence",
+			expected: nil,
+		},
+		{
+			name: "Application cookie added",
+			desired: client.GenericBackendSetDetails{
+				SessionPersistenceConfiguration: &client.GenericSessionPersistenceConfiguration{CookieName: common.String("*")},
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration", "NOT_PRESENT", "PRESENT"),
+			},
+		},
+		{
+			name: "Application cookie changed",
+			desired: client.GenericBackendSetDetails{
+				SessionPersistenceConfiguration: &client.GenericSessionPersistenceConfiguration{
+					CookieName:      common.String("JSESSIONID"),
+					DisableFallback: common.Bool(true),
+				},
+			},
+			actual: client.GenericBackendSetDetails{
+				SessionPersistenceConfiguration: &client.GenericSessionPersistenceConfiguration{CookieName: common.String("*")},
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration:CookieName", "*", "JSESSIONID"),
+				fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration:DisableFallback", false, true),
+			},
+		},
+		{
+			name: "Application cookie replaced with LB cookie",
+			desired: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/", false),
+			},
+			actual: client.GenericBackendSetDetails{
+				SessionPersistenceConfiguration: &client.GenericSessionPersistenceConfiguration{CookieName: common.String("*")},
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:SessionPersistenceConfiguration", "PRESENT", "NOT_PRESENT"),
+				fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration", "NOT_PRESENT", "PRESENT"),
+			},
+		},
+		{
+			name: "LB cookie unchanged",
+			desired: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/", false),
+			},
+			actual: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/", false),
+			},
+			expected: nil,
+		},
+		{
+			name: "LB cookie changed",
+			desired: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/", false),
+			},
+			actual: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/app", true),
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:Path", "/app", "/"),
+				fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration:IsSecure", true, false),
+			},
+		},
+		{
+			name: "LB cookie removed",
+			actual: client.GenericBackendSetDetails{
+				LbCookieSessionPersistenceConfiguration: lbCookie("/", false),
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:LbCookieSessionPersistenceConfiguration", "PRESENT", "NOT_PRESENT"),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := getSessionPersistenceChanges(tt.actual, tt.desired)
+			if !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("expected SessionPersistenceChanges\n%+v\nbut got\n%+v", tt.expected, changes)
+			}
+		})
+	}
+}
+
 func TestGetSSLConfigurationChanges(t *testing.T) {
 	var testCases = []struct {
 		name     string
@@ -1752,6 +1915,142 @@ func TestGetSSLConfigurationChanges(t *testing.T) {
 	}
 }
 
+func TestGetSSLCipherChanges(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		desired  *client.GenericSslConfigurationDetails
+		actual   *client.GenericSslConfigurationDetails
+		expected []string
+	}{
+		{
+			name: "Not desired",
+			desired: &client.GenericSslConfigurationDetails{
+				CertificateName: common.String("cert"),
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CertificateName:       common.String("cert"),
+				CipherSuiteName:       common.String("oci-default-ssl-cipher-suite-v1"),
+				Protocols:             []string{"TLSv1.2"},
+				ServerOrderPreference: "DISABLED",
+			},
+			expected: nil,
+		},
+		{
+			name: "Unchanged",
+			desired: &client.GenericSslConfigurationDetails{
+				CipherSuiteName:       common.String("oci-modern-ssl-cipher-suite-v1"),
+				Protocols:             []string{"TLSv1.3", "TLSv1.2"},
+				ServerOrderPreference: "ENABLED",
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CipherSuiteName:       common.String("oci-modern-ssl-cipher-suite-v1"),
+				Protocols:             []string{"TLSv1.2", "TLSv1.3"},
+				ServerOrderPreference: "ENABLED",
+			},
+			expected: nil,
+		},
+		{
+			name: "All Changed",
+			desired: &client.GenericSslConfigurationDetails{
+				CipherSuiteName:       common.String("approved-ciphers"),
+				Protocols:             []string{"TLSv1.2"},
+				ServerOrderPreference: "ENABLED",
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CipherSuiteName:       common.String("oci-default-ssl-cipher-suite-v1"),
+				Protocols:             []string{"TLSv1", "TLSv1.1", "TLSv1.2"},
+				ServerOrderPreference: "DISABLED",
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "BackendSet:SSLConfiguration:CipherSuiteName", "oci-default-ssl-cipher-suite-v1", "approved-ciphers"),
+				fmt.Sprintf(changeFmtStr, "BackendSet:SSLConfiguration:Protocols", "TLSv1,TLSv1.1,TLSv1.2", "TLSv1.2"),
+				fmt.Sprintf(changeFmtStr, "BackendSet:SSLConfiguration:ServerOrderPreference", "DISABLED", "ENABLED"),
+			},
+		},
+		{
+			name: "SSL disabled",
+			desired: &client.GenericSslConfigurationDetails{
+				CipherSuiteName: common.String("approved-ciphers"),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := getSSLCipherChanges("BackendSet:SSLConfiguration", tt.actual, tt.desired)
+			if !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("expected SSLCipherChanges\n%+v\nbut got\n%+v", tt.expected, changes)
+			}
+		})
+	}
+}
+
+func TestGetSSLCertificateChanges(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		desired  *client.GenericSslConfigurationDetails
+		actual   *client.GenericSslConfigurationDetails
+		expected []string
+	}{
+		{
+			name: "Unchanged",
+			desired: &client.GenericSslConfigurationDetails{
+				CertificateIds:                 []string{"ocid1.certificate.oc1..a"},
+				TrustedCertificateAuthorityIds: []string{"ocid1.cabundle.oc1..a"},
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CertificateIds:                 []string{"ocid1.certificate.oc1..a"},
+				TrustedCertificateAuthorityIds: []string{"ocid1.cabundle.oc1..a"},
+			},
+			expected: nil,
+		},
+		{
+			name: "Certificate OCID changed",
+			desired: &client.GenericSslConfigurationDetails{
+				CertificateIds: []string{"ocid1.certificate.oc1..b"},
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CertificateIds: []string{"ocid1.certificate.oc1..a"},
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:CertificateIds", "ocid1.certificate.oc1..a", "ocid1.certificate.oc1..b"),
+			},
+		},
+		{
+			name: "Certificate bundle replaced with certificate OCID",
+			desired: &client.GenericSslConfigurationDetails{
+				CertificateIds:                 []string{"ocid1.certificate.oc1..a"},
+				TrustedCertificateAuthorityIds: []string{"ocid1.cabundle.oc1..a"},
+			},
+			actual: &client.GenericSslConfigurationDetails{
+				CertificateName: common.String("ssl-certificate-secret-3640b388f5e2"),
+			},
+			expected: []string{
+				fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:CertificateName", "ssl-certificate-secret-3640b388f5e2", ""),
+				fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:CertificateIds", "", "ocid1.certificate.oc1..a"),
+				fmt.Sprintf(changeFmtStr, "Listener:SSLConfiguration:TrustedCertificateAuthorityIds", "", "ocid1.cabundle.oc1..a"),
+			},
+		},
+		{
+			name: "SSL disabled",
+			desired: &client.GenericSslConfigurationDetails{
+				CertificateIds: []string{"ocid1.certificate.oc1..a"},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := getSSLCertificateChanges("Listener:SSLConfiguration", tt.actual, tt.desired)
+			if !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("expected SSLCertificateChanges\n%+v\nbut got\n%+v", tt.expected, changes)
+			}
+		})
+	}
+}
+
 func TestGetConnectionConfigurationChanges(t *testing.T) {
 	var testCases = []struct {
 		name     string
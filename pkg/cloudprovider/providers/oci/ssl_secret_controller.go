@@ -0,0 +1,234 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// SSLSecretVersionAnnotation is set by the cloud controller manager on
+// LoadBalancer Services when one of their referenced TLS secrets changes. The
+// annotation update causes the service controller to resync the Service so
+// that the new certificates are rolled out to the load balancer.
+const SSLSecretVersionAnnotation = "oci.oraclecloud.com/ssl-secret-version"
+
+// SecretsResource is watched by the SSLSecretController through a metadata
+// informer, so that the data of the secrets isn't cached.
+var SecretsResource = v1.SchemeGroupVersion.WithResource("secrets")
+
+// SSLSecretController requeues LoadBalancer Services whenever the data of a
+// TLS secret they reference changes. It only watches the metadata of the
+// secrets and reads a secret when a Service references it.
+type SSLSecretController struct {
+	secretInformer  informers.GenericInformer
+	serviceInformer coreinformers.ServiceInformer
+	kubeClient      clientset.Interface
+	queue           workqueue.RateLimitingInterface
+	logger          *zap.SugaredLogger
+}
+
+// NewSSLSecretController creates a SSLSecretController object. The secret
+// informer is a metadata informer for SecretsResource.
+func NewSSLSecretController(
+	secretInformer informers.GenericInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	kubeClient clientset.Interface,
+	logger *zap.SugaredLogger) *SSLSecretController {
+
+	ssc := &SSLSecretController{
+		secretInformer:  secretInformer,
+		serviceInformer: serviceInformer,
+		kubeClient:      kubeClient,
+		queue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		logger:          logger,
+	}
+
+	// The resource version changes with every update of the secret, the
+	// secrets whose data is unchanged are filtered out when processed.
+	ssc.secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, ok := oldObj.(metav1.Object)
+			if !ok {
+				return
+			}
+			newSecret, ok := newObj.(metav1.Object)
+			if !ok || oldSecret.GetResourceVersion() == newSecret.GetResourceVersion() {
+				return
+			}
+			key, err := cache.MetaNamespaceKeyFunc(newSecret)
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			ssc.queue.Add(key)
+		},
+	})
+
+	return ssc
+}
+
+// Run will start the SSLSecretController and manage shutdown
+func (ssc *SSLSecretController) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	defer ssc.queue.ShutDown()
+
+	ssc.logger.Info("Starting ssl secret controller")
+
+	if !cache.WaitForCacheSync(stopCh, ssc.secretInformer.Informer().HasSynced, ssc.serviceInformer.Informer().HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Timed out waiting for caches to sync"))
+		return
+	}
+
+	wait.Until(ssc.runWorker, time.Second, stopCh)
+}
+
+// A function to run the worker which will process items in the queue
+func (ssc *SSLSecretController) runWorker() {
+	for ssc.processNextItem() {
+
+	}
+}
+
+// Used to sequentially process the keys present in the queue
+func (ssc *SSLSecretController) processNextItem() bool {
+
+	key, quit := ssc.queue.Get()
+	if quit {
+		return false
+	}
+
+	defer ssc.queue.Done(key)
+
+	err := ssc.processItem(key.(string))
+
+	if err != nil {
+		ssc.logger.Errorf("Error processing secret %s (will retry): %v", key, err)
+		ssc.queue.AddRateLimited(key)
+	} else {
+		ssc.queue.Forget(key)
+	}
+	return true
+}
+
+// A function which annotates every LoadBalancer Service referencing the secret with a digest
+// of the secret's data so that the Service is reconciled against the new certificate data
+func (ssc *SSLSecretController) processItem(key string) error {
+	logger := ssc.logger.With("secret", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	services, err := ssc.serviceInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var referencing []*v1.Service
+	for _, service := range services {
+		if serviceReferencesSecret(service, namespace, name) {
+			referencing = append(referencing, service)
+		}
+	}
+	if len(referencing) == 0 {
+		return nil
+	}
+
+	secret, err := ssc.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version := fmt.Sprintf("%s/%s:%s", namespace, name, secretDataDigest(secret.Data))
+	for _, service := range referencing {
+		if service.Annotations[SSLSecretVersionAnnotation] == version {
+			continue
+		}
+
+		logger.With("service", service.Namespace+"/"+service.Name).Info("Requeueing service for updated ssl secret")
+		patchBytes := []byte(fmt.Sprintf("{\"metadata\": {\"annotations\": {\"%s\":\"%s\"}}}", SSLSecretVersionAnnotation, version))
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			_, err := ssc.kubeClient.CoreV1().Services(service.Namespace).Patch(context.Background(), service.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+			return err
+		})
+		if err != nil {
+			logger.With(zap.Error(err)).Errorf("Failed to patch service %s/%s", service.Namespace, service.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// secretDataDigest returns a digest of the data of a secret, which only
+// changes when the data does.
+func secretDataDigest(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	digest := sha256.New()
+	for _, k := range keys {
+		digest.Write([]byte(k))
+		digest.Write([]byte{0})
+		digest.Write(data[k])
+		digest.Write([]byte{0})
+	}
+	return hex.EncodeToString(digest.Sum(nil))[:certificateDigestLength]
+}
+
+// serviceReferencesSecret returns true if the LoadBalancer Service uses the
+// given secret for its listener, backend set or client CA certificates.
+func serviceReferencesSecret(service *v1.Service, namespace, name string) bool {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer || !requiresCertificate(service) {
+		return false
+	}
+	for _, annotation := range []string{
+		ServiceAnnotationLoadBalancerTLSSecret,
+		ServiceAnnotationLoadBalancerTLSBackendSetSecret,
+		ServiceAnnotationLoadBalancerSSLClientCASecret,
+	} {
+		secretName, secretNamespace := getSecretParts(service.Annotations[annotation], service)
+		if secretName == name && secretNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
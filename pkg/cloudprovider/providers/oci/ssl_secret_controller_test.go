@@ -0,0 +1,248 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestServiceReferencesSecret(t *testing.T) {
+	testCases := map[string]struct {
+		serviceType v1.ServiceType
+		annotations map[string]string
+		expected    bool
+	}{
+		"listener secret in service namespace": {
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:  "443",
+				ServiceAnnotationLoadBalancerTLSSecret: "tls-secret",
+			},
+			expected: true,
+		},
+		"backend set secret in another namespace": {
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:            "443",
+				ServiceAnnotationLoadBalancerTLSBackendSetSecret: "kube-system/tls-secret",
+			},
+			expected: false,
+		},
+		"client CA secret": {
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:          "443",
+				ServiceAnnotationLoadBalancerTLSSecret:         "listener-secret",
+				ServiceAnnotationLoadBalancerSSLClientCASecret: "default/tls-secret",
+			},
+			expected: true,
+		},
+		"no ssl ports": {
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerTLSSecret: "tls-secret",
+			},
+			expected: false,
+		},
+		"not a load balancer": {
+			serviceType: v1.ServiceTypeClusterIP,
+			annotations: map[string]string{
+				ServiceAnnotationLoadBalancerSSLPorts:  "443",
+				ServiceAnnotationLoadBalancerTLSSecret: "tls-secret",
+			},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			service := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "test",
+					Annotations: tc.annotations,
+				},
+				Spec: v1.ServiceSpec{
+					Type: tc.serviceType,
+				},
+			}
+			result := serviceReferencesSecret(service, "default", "tls-secret")
+			if result != tc.expected {
+				t.Errorf("Expected %t but got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
+// mockSSLSecretKubeClient serves the secrets and records the patched services.
+type mockSSLSecretKubeClient struct {
+	clientset.Interface
+	corev1client.CoreV1Interface
+	secrets    map[string]*v1.Secret
+	secretGets []string
+	patched    map[string]string
+}
+
+func (c *mockSSLSecretKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return c
+}
+
+func (c *mockSSLSecretKubeClient) Secrets(namespace string) corev1client.SecretInterface {
+	return &mockSSLSecrets{client: c, namespace: namespace}
+}
+
+func (c *mockSSLSecretKubeClient) Services(namespace string) corev1client.ServiceInterface {
+	return &mockSSLServices{client: c, namespace: namespace}
+}
+
+type mockSSLSecrets struct {
+	corev1client.SecretInterface
+	client    *mockSSLSecretKubeClient
+	namespace string
+}
+
+func (s *mockSSLSecrets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Secret, error) {
+	key := s.namespace + "/" + name
+	s.client.secretGets = append(s.client.secretGets, key)
+	secret, ok := s.client.secrets[key]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return secret, nil
+}
+
+type mockSSLServices struct {
+	corev1client.ServiceInterface
+	client    *mockSSLSecretKubeClient
+	namespace string
+}
+
+func (s *mockSSLServices) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*v1.Service, error) {
+	s.client.patched[s.namespace+"/"+name] = string(data)
+	return &v1.Service{}, nil
+}
+
+type fakeServiceInformer struct {
+	coreinformers.ServiceInformer
+	lister listersv1.ServiceLister
+}
+
+func (f *fakeServiceInformer) Lister() listersv1.ServiceLister {
+	return f.lister
+}
+
+func TestSSLSecretControllerProcessItem(t *testing.T) {
+	data := map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")}
+	version := fmt.Sprintf("default/tls-secret:%s", secretDataDigest(data))
+	sslService := func(name, secretName, secretVersion string) *v1.Service {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Annotations: map[string]string{
+					ServiceAnnotationLoadBalancerSSLPorts:  "443",
+					ServiceAnnotationLoadBalancerTLSSecret: secretName,
+				},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		}
+		if secretVersion != "" {
+			service.Annotations[SSLSecretVersionAnnotation] = secretVersion
+		}
+		return service
+	}
+
+	testCases := map[string]struct {
+		key        string
+		services   []*v1.Service
+		secretGets []string
+		patched    map[string]string
+	}{
+		"referencing services are annotated": {
+			key:        "default/tls-secret",
+			services:   []*v1.Service{sslService("outdated", "tls-secret", "default/tls-secret:0123456789ab"), sslService("current", "tls-secret", version)},
+			secretGets: []string{"default/tls-secret"},
+			patched: map[string]string{
+				"default/outdated": fmt.Sprintf("{\"metadata\": {\"annotations\": {\"%s\":\"%s\"}}}", SSLSecretVersionAnnotation, version),
+			},
+		},
+		"unreferenced secret isn't read": {
+			key:      "default/other-secret",
+			services: []*v1.Service{sslService("outdated", "tls-secret", "")},
+			patched:  map[string]string{},
+		},
+		"deleted secret": {
+			key:        "default/deleted-secret",
+			services:   []*v1.Service{sslService("outdated", "deleted-secret", "")},
+			secretGets: []string{"default/deleted-secret"},
+			patched:    map[string]string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, service := range tc.services {
+				indexer.Add(service)
+			}
+			kubeClient := &mockSSLSecretKubeClient{
+				secrets: map[string]*v1.Secret{
+					"default/tls-secret": {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls-secret"}, Data: data},
+				},
+				patched: map[string]string{},
+			}
+			ssc := &SSLSecretController{
+				serviceInformer: &fakeServiceInformer{lister: listersv1.NewServiceLister(indexer)},
+				kubeClient:      kubeClient,
+				logger:          zap.S(),
+			}
+
+			if err := ssc.processItem(tc.key); err != nil {
+				t.Fatalf("processItem(%s) => unexpected error: %v", tc.key, err)
+			}
+			if !reflect.DeepEqual(kubeClient.secretGets, tc.secretGets) {
+				t.Errorf("processItem(%s) => read secrets %v, expected %v", tc.key, kubeClient.secretGets, tc.secretGets)
+			}
+			if !reflect.DeepEqual(kubeClient.patched, tc.patched) {
+				t.Errorf("processItem(%s) => patched %v, expected %v", tc.key, kubeClient.patched, tc.patched)
+			}
+		})
+	}
+}
+
+func TestSecretDataDigest(t *testing.T) {
+	data := map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")}
+	if secretDataDigest(data) != secretDataDigest(map[string][]byte{v1.TLSPrivateKeyKey: []byte("key"), v1.TLSCertKey: []byte("cert")}) {
+		t.Errorf("secretDataDigest() => depends on the order of the keys")
+	}
+	if secretDataDigest(data) == secretDataDigest(map[string][]byte{v1.TLSCertKey: []byte("renewed"), v1.TLSPrivateKeyKey: []byte("key")}) {
+		t.Errorf("secretDataDigest() => unchanged for changed data")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -42,6 +43,10 @@ const (
 	// ociVolumeBackupID is the name of the oci volume backup id annotation.
 	ociVolumeBackupID = "volume.beta.kubernetes.io/oci-volume-source"
 
+	// defaultFsType is the filesystem a volume is formatted with when the
+	// storage class doesn't request one.
+	defaultFsType = "ext4"
+
 	// Block Volume Performance Units
 	VpusPerGB = "vpusPerGB"
 	LowCostPerformanceOption  = 0
@@ -57,6 +62,10 @@ type Util struct {
 var (
 	DiskByPathPatternPV    = `/dev/disk/by-path/pci-\d+:\d+:\d+\.\d+-scsi-\d+:\d+:\d+:\d+$`
 	DiskByPathPatternISCSI = `/dev/disk/by-path/ip-[\w\.]+:\d+-iscsi-[\w\.\-:]+-lun-1$`
+
+	// multipathDevicePattern matches an <ip>:<port>-<IQN> path of a multipath
+	// attachment.
+	multipathDevicePattern = regexp.MustCompile(`^([\w\.]+):(\d+)-([\w\.\-:]+)$`)
 )
 
 func (u *Util) LookupNodeID(k kubernetes.Interface, nodeName string) (string, error) {
@@ -79,15 +88,14 @@ func (u *Util) LookupNodeAvailableDomain(k kubernetes.Interface, nodeID string)
 		u.Logger.With(zap.Error(err)).With("nodeId", nodeID).Error("Failed to get Node by name.")
 		return "", fmt.Errorf("failed to get node %s", nodeID)
 	}
-	if n.Labels != nil {
-		ad, ok := n.Labels[kubeAPI.LabelZoneFailureDomain]
-		if ok {
+	for _, label := range []string{kubeAPI.LabelTopologyZone, kubeAPI.LabelZoneFailureDomain} {
+		if ad, ok := n.Labels[label]; ok {
 			return ad, nil
 		}
 	}
 
 	errMsg := fmt.Sprint("Did not find the label for the fault domain.")
-	u.Logger.With("nodeId", nodeID, "label", kubeAPI.LabelZoneFailureDomain).Error(errMsg)
+	u.Logger.With("nodeId", nodeID, "label", kubeAPI.LabelTopologyZone).Error(errMsg)
 	return "", fmt.Errorf(errMsg)
 }
 
@@ -110,6 +118,26 @@ func (u *Util) WaitForPathToExist(path string, maxRetries int) bool {
 	return false
 }
 
+// WaitForMultipathDevice waits for dm-multipath to combine the paths of an
+// attachment into a device and returns it.
+func (u *Util) WaitForMultipathDevice(diskByPath string, maxRetries int) (string, bool) {
+	for i := 0; i < maxRetries; i++ {
+		device, err := disk.FindMultipathDevice(diskByPath)
+		if err != nil && !os.IsNotExist(err) {
+			u.Logger.With(zap.Error(err), "diskByPath", diskByPath).Error("Failed to find the multipath device.")
+			return "", false
+		}
+		if device != "" {
+			return device, true
+		}
+		if i == maxRetries-1 {
+			break
+		}
+		time.Sleep(waitForPathDelay)
+	}
+	return "", false
+}
+
 // convert "zkJl:US-ASHBURN-AD-1" to "US-ASHBURN-AD-1"
 func (u *Util) GetAvailableDomainInNodeLabel(fullAD string) string {
 	adElements := strings.Split(fullAD, ":")
@@ -153,6 +181,65 @@ func ExtractISCSIInformation(attributes map[string]string) (*disk.Disk, error) {
 	}, nil
 }
 
+// ExtractMultipathISCSIInformation returns the iSCSI paths of a multipath
+// attachment from the publish context, or nil if the attachment isn't
+// multipath.
+func ExtractMultipathISCSIInformation(attributes map[string]string) ([]*disk.Disk, error) {
+	devices, ok := attributes[disk.ISCSIMULTIPATHDEVICES]
+	if !ok || devices == "" {
+		return nil, nil
+	}
+
+	var disks []*disk.Disk
+	for _, device := range strings.Split(devices, ",") {
+		m := multipathDevicePattern.FindStringSubmatch(device)
+		if m == nil {
+			return nil, fmt.Errorf("invalid multipath device %q", device)
+		}
+		port, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid port number: %s, error: %v", m[2], err)
+		}
+		disks = append(disks, &disk.Disk{
+			IQN:  m[3],
+			IPv4: m[1],
+			Port: port,
+		})
+	}
+	return disks, nil
+}
+
+// ExtractAllISCSIInformationFromMountPath returns the iSCSI disks behind the
+// given /dev/disk/by-path links, one per path of a multipath attachment.
+func ExtractAllISCSIInformationFromMountPath(logger *zap.SugaredLogger, diskPath []string) ([]*disk.Disk, error) {
+	var disks []*disk.Disk
+	seen := make(map[string]bool)
+	for _, diskByPath := range diskPath {
+		m, err := disk.FindFromDevicePath(logger, diskByPath)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid port number: %s, error: %v", m[2], err)
+		}
+		sd := &disk.Disk{
+			IQN:  m[3],
+			IPv4: m[1],
+			Port: port,
+		}
+		if seen[sd.String()] {
+			continue
+		}
+		seen[sd.String()] = true
+		disks = append(disks, sd)
+	}
+	if len(disks) == 0 {
+		return nil, fmt.Errorf("iSCSI information not found for disk paths %v", diskPath)
+	}
+	return disks, nil
+}
+
 //Extracts the vpusPerGB as int64 from given string input
 func ExtractBlockVolumePerformanceLevel(attribute string) (int64, error) {
 	vpusPerGB, err := strconv.ParseInt(attribute, 10, 64)
@@ -247,18 +334,17 @@ func FormatBytes(inputBytes int64) string {
 	return result + unit
 }
 
-func ValidateFsType(logger *zap.SugaredLogger, fsType string) string {
-	defaultFsType := "ext4"
-	if fsType == "ext4" || fsType == "ext3" {
-		return fsType
-	} else if fsType != "" {
-		//TODO: Remove this code when we support other than ext4 || ext3.
-		logger.With("fsType", fsType).Warn("Supporting only 'ext4' as fsType.")
-		return defaultFsType
-	} else {
-		//No fsType provided returning ext4
-		return defaultFsType
+// ValidateFsType returns the filesystem type to format a volume with, ext4
+// when none is requested. Filesystem types other than ext3, ext4, xfs and
+// btrfs are rejected.
+func ValidateFsType(fsType string) (string, error) {
+	switch fsType {
+	case "":
+		return defaultFsType, nil
+	case "ext3", "ext4", "xfs", "btrfs":
+		return fsType, nil
 	}
+	return "", fmt.Errorf("fsType %q is not supported, supported fsTypes are ext3, ext4, xfs and btrfs", fsType)
 }
 
 type VolumeLocks struct {
@@ -1,9 +1,12 @@
 package csi_util
 
 import (
+	"reflect"
 	"testing"
 
 	"go.uber.org/zap"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
 )
 
 func TestUtil_getAvailableDomainInNodeLabel(t *testing.T) {
@@ -49,53 +52,113 @@ func TestUtil_getAvailableDomainInNodeLabel(t *testing.T) {
 }
 
 func Test_validateFsType(t *testing.T) {
-	type args struct {
-		logger *zap.SugaredLogger
-		fsType string
-	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name    string
+		fsType  string
+		want    string
+		wantErr bool
 	}{
 		{
-			name: "Return ext4",
-			args: args{
-				logger: zap.S(),
-				fsType: "ext4",
-			},
-			want: "ext4",
+			name:   "Return ext4",
+			fsType: "ext4",
+			want:   "ext4",
 		},
 		{
-			name: "Return ext3",
-			args: args{
-				logger: zap.S(),
-				fsType: "ext3",
-			},
-			want: "ext3",
+			name:   "Return ext3",
+			fsType: "ext3",
+			want:   "ext3",
 		},
 		{
-			name: "Return default ext4 for empty string",
-			args: args{
-				logger: zap.S(),
-				fsType: "",
-			},
-			want: "ext4",
+			name:   "Return xfs",
+			fsType: "xfs",
+			want:   "xfs",
 		},
 		{
-			name: "Return default ext4 for unsupported string",
-			args: args{
-				logger: zap.S(),
-				fsType: "xxxxx",
-			},
-			want: "ext4",
+			name:   "Return btrfs",
+			fsType: "btrfs",
+			want:   "btrfs",
+		},
+		{
+			name:   "Return default ext4 for empty string",
+			fsType: "",
+			want:   "ext4",
+		},
+		{
+			name:    "Error for unsupported string",
+			fsType:  "xxxxx",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := ValidateFsType(tt.args.logger, tt.args.fsType); got != tt.want {
+			got, err := ValidateFsType(tt.fsType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFsType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
 				t.Errorf("validateFsType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestExtractMultipathISCSIInformation(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       []*disk.Disk
+		wantErr    bool
+	}{
+		{
+			name:       "Not multipath",
+			attributes: map[string]string{disk.ISCSIIQN: "iqn.2015-12.com.oracleiaas:abc"},
+			want:       nil,
+		},
+		{
+			name: "Multipath",
+			attributes: map[string]string{
+				disk.ISCSIMULTIPATHDEVICES: "169.254.2.2:3260-iqn.2015-12.com.oracleiaas:abc,169.254.2.3:3260-iqn.2015-12.com.oracleiaas:abc",
+			},
+			want: []*disk.Disk{
+				{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.2", Port: 3260},
+				{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.3", Port: 3260},
+			},
+		},
+		{
+			name:       "Invalid path",
+			attributes: map[string]string{disk.ISCSIMULTIPATHDEVICES: "169.254.2.2-iqn.2015-12.com.oracleiaas:abc"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractMultipathISCSIInformation(tt.attributes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractMultipathISCSIInformation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractMultipathISCSIInformation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractAllISCSIInformationFromMountPath(t *testing.T) {
+	diskPath := []string{
+		"/dev/disk/by-path/ip-169.254.2.2:3260-iscsi-iqn.2015-12.com.oracleiaas:abc-lun-1",
+		"/dev/disk/by-path/ip-169.254.2.3:3260-iscsi-iqn.2015-12.com.oracleiaas:abc-lun-1",
+		"/dev/disk/by-path/ip-169.254.2.2:3260-iscsi-iqn.2015-12.com.oracleiaas:abc-lun-1",
+		"/dev/disk/by-path/pci-0000:00:04.0-scsi-0:0:0:1",
+	}
+	want := []*disk.Disk{
+		{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.2", Port: 3260},
+		{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.3", Port: 3260},
+	}
+	got, err := ExtractAllISCSIInformationFromMountPath(zap.S(), diskPath)
+	if err != nil {
+		t.Fatalf("ExtractAllISCSIInformationFromMountPath() => unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractAllISCSIInformationFromMountPath() = %v, want %v", got, want)
+	}
+}
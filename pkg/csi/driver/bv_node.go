@@ -5,24 +5,98 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	kubeAPI "k8s.io/api/core/v1"
 
 	"github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
 )
 
 const (
+	// maxVolumesPerNode is the maximum number of block volumes that can be
+	// attached to an instance.
 	maxVolumesPerNode               = 32
 	volumeOperationAlreadyExistsFmt = "An operation for the volume: %s already exists."
+
+	// stagedBlockDeviceFileName is the file inside the staging path that a raw
+	// block volume's device is bind mounted to, so that NodeUnstageVolume can
+	// find the device again.
+	stagedBlockDeviceFileName = "device"
+
+	// ephemeralVolumeContextKey is set in the volume context of CSI inline
+	// ephemeral volumes, which the driver doesn't support: the node driver has
+	// no OCI credentials to create and attach volumes with. Generic ephemeral
+	// volumes are provisioned and attached by the controller like any other
+	// claim.
+	ephemeralVolumeContextKey = "csi.storage.k8s.io/ephemeral"
+)
+
+// stagedBlockDevicePath returns the path a raw block volume's device is bind
+// mounted to during NodeStageVolume.
+func stagedBlockDevicePath(stagingTargetPath string) string {
+	return filepath.Join(stagingTargetPath, stagedBlockDeviceFileName)
+}
+
+// stagedVolume is how a volume is staged on its staging path.
+type stagedVolume int
+
+const (
+	notStaged stagedVolume = iota
+	stagedFilesystem
+	stagedRawBlock
 )
 
+// mountLister lists the mount points of the node.
+type mountLister interface {
+	List() ([]mount.MountPoint, error)
+}
+
+// getStagedVolume tells from the mount table how a volume is staged. The
+// staging path of a volume with a filesystem is the root of that filesystem,
+// which may well hold a file named like the staged device file, so the file
+// alone doesn't make a volume a raw block volume.
+func getStagedVolume(ml mountLister, stagingTargetPath string) (stagedVolume, error) {
+	mountPoints, err := ml.List()
+	if err != nil {
+		return notStaged, err
+	}
+	stagingTargetPath = filepath.Clean(stagingTargetPath)
+	devicePath := stagedBlockDevicePath(stagingTargetPath)
+	stage := notStaged
+	for _, mountPoint := range mountPoints {
+		switch filepath.Clean(mountPoint.Path) {
+		case stagingTargetPath:
+			return stagedFilesystem, nil
+		case devicePath:
+			stage = stagedRawBlock
+		}
+	}
+	return stage, nil
+}
+
+// removeStaleBlockDeviceFile removes the device file left in the staging path
+// by an unstaging of a raw block volume that was interrupted after unmounting
+// it. It is only called when nothing is mounted on the staging path, so the
+// file can't belong to the filesystem of a volume, and only an empty regular
+// file, as created by NodeStageVolume, is removed.
+func removeStaleBlockDeviceFile(logger *zap.SugaredLogger, stagingTargetPath string) {
+	devicePath := stagedBlockDevicePath(stagingTargetPath)
+	info, err := os.Lstat(devicePath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() != 0 {
+		return
+	}
+	if err := os.Remove(devicePath); err != nil {
+		logger.With(zap.Error(err)).With("devicePath", devicePath).Warn("unable to remove staged device file")
+	}
+}
+
 // NodeStageVolume mounts the volume to a staging path on the node.
 func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
 	if req.VolumeId == "" {
@@ -43,6 +117,12 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 
 	logger := d.logger.With("volumeID", req.VolumeId, "stagingPath", req.StagingTargetPath)
 
+	fsType, err := csi_util.ValidateFsType(req.VolumeCapability.GetMount().GetFsType())
+	if err != nil {
+		logger.With(zap.Error(err)).Error("Invalid fsType.")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	attachment, ok := req.PublishContext[attachmentType]
 
 	if !ok {
@@ -51,6 +131,7 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 	}
 	var devicePath string
 	var mountHandler disk.Interface
+	var multipathDisks []*disk.Disk
 
 	switch attachment {
 	case attachmentTypeISCSI:
@@ -59,12 +140,34 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 			logger.With(zap.Error(err)).Error("Failed to get SCSI info from publish context.")
 			return nil, status.Error(codes.InvalidArgument, "PublishContext is invalid.")
 		}
+		multipathDisks, err = csi_util.ExtractMultipathISCSIInformation(req.PublishContext)
+		if err != nil {
+			logger.With(zap.Error(err)).Error("Failed to get the multipath SCSI info from publish context.")
+			return nil, status.Error(codes.InvalidArgument, "PublishContext is invalid.")
+		}
 
 		// Get the device path using the publish context
 		devicePath = csi_util.GetDevicePath(scsiInfo)
 
-		mountHandler = disk.NewFromISCSIDisk(d.logger, scsiInfo)
-		logger.With("devicePath", devicePath).Info("starting to stage iSCSI Mounting.")
+		if req.VolumeContext[iscsiChap] == "true" {
+			scsiInfo.ChapUsername, scsiInfo.ChapPassword, err = getISCSIChapCredentials(req.Secrets, d.nodeID)
+			if err != nil {
+				logger.With(zap.Error(err)).Error("Failed to get the CHAP credentials from the node stage secrets.")
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+		}
+
+		if len(multipathDisks) > 0 {
+			for _, path := range multipathDisks {
+				path.ChapUsername, path.ChapPassword = scsiInfo.ChapUsername, scsiInfo.ChapPassword
+			}
+			devicePath = multipathDisks[0].DevicePath()
+			mountHandler = disk.NewFromMultipathISCSIDisks(d.logger, multipathDisks)
+			logger.With("devicePath", devicePath, "paths", len(multipathDisks)).Info("starting to stage multipath iSCSI Mounting.")
+		} else {
+			mountHandler = disk.NewFromISCSIDisk(d.logger, scsiInfo)
+			logger.With("devicePath", devicePath).Info("starting to stage iSCSI Mounting.")
+		}
 
 	case attachmentTypeParavirtualized:
 		devicePath, ok = req.PublishContext[device]
@@ -79,6 +182,15 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 		return nil, status.Error(codes.InvalidArgument, "unknown attachment type. supported attachment types are iscsi and paravirtualized")
 	}
 
+	var luksPassphrase string
+	if req.VolumeContext[luksEncryption] == "true" {
+		luksPassphrase, err = getLUKSPassphrase(req.Secrets)
+		if err != nil {
+			logger.With(zap.Error(err)).Error("Failed to get the LUKS passphrase from the node stage secrets.")
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+
 	if acquired := d.volumeLocks.TryAcquire(req.VolumeId); !acquired {
 		logger.Error("Could not acquire lock for NodeStageVolume.")
 		return nil, status.Errorf(codes.Aborted, volumeOperationAlreadyExistsFmt, req.VolumeId)
@@ -86,7 +198,20 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 
 	defer d.volumeLocks.Release(req.VolumeId)
 
-	isMounted, oErr := mountHandler.DeviceOpened(devicePath)
+	// The disks of the paths of a multipath attachment are held by the
+	// multipath device, which is the one mounted.
+	openedPath := devicePath
+	if len(multipathDisks) > 0 {
+		if multipathDevice, err := disk.FindMultipathDevice(devicePath); err == nil && multipathDevice != "" {
+			openedPath = multipathDevice
+		}
+	}
+	// The device of a LUKS encrypted volume is held by its LUKS device, which
+	// is the one mounted.
+	if luksDevice, err := disk.FindLUKSDevice(openedPath); err == nil && luksDevice != "" {
+		openedPath = luksDevice
+	}
+	isMounted, oErr := mountHandler.DeviceOpened(openedPath)
 	if oErr != nil {
 		logger.With(zap.Error(oErr)).Error("getting error to get the details about volume is already mounted or not.")
 		return nil, status.Error(codes.Internal, oErr.Error())
@@ -95,7 +220,7 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 		return &csi.NodeStageVolumeResponse{}, nil
 	}
 
-	err := mountHandler.AddToDB()
+	err = mountHandler.AddToDB()
 	if err != nil {
 		logger.With(zap.Error(err)).Error("failed to add the iSCSI node record.")
 		return nil, status.Error(codes.Internal, err.Error())
@@ -131,15 +256,59 @@ func (d BlockVolumeNodeDriver) NodeStageVolume(ctx context.Context, req *csi.Nod
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if !d.util.WaitForPathToExist(devicePath, 20) {
+	if len(multipathDisks) > 0 {
+		for _, path := range multipathDisks {
+			if !d.util.WaitForPathToExist(path.DevicePath(), 20) {
+				logger.With("devicePath", path.DevicePath()).Error("failed to wait for device to exist.")
+				return nil, status.Error(codes.DeadlineExceeded, "Failed to wait for device to exist.")
+			}
+		}
+		multipathDevice, ok := d.util.WaitForMultipathDevice(devicePath, 20)
+		if !ok {
+			logger.Error("failed to wait for multipath device to exist.")
+			return nil, status.Error(codes.DeadlineExceeded, "Failed to wait for multipath device to exist.")
+		}
+		logger.With("multipathDevice", multipathDevice).Info("Found multipath device.")
+		devicePath = multipathDevice
+	} else if !d.util.WaitForPathToExist(devicePath, 20) {
 		logger.Error("failed to wait for device to exist.")
 		return nil, status.Error(codes.DeadlineExceeded, "Failed to wait for device to exist.")
 	}
 
+	// The LUKS device is staged instead of the device of an encrypted volume,
+	// an empty volume is formatted as a LUKS container first.
+	if luksPassphrase != "" {
+		readOnly := req.VolumeCapability.GetAccessMode().GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+		luksDevice, err := disk.NewLUKS(d.logger).Open(devicePath, luksMapperName(req.VolumeId), luksPassphrase, readOnly)
+		if err != nil {
+			logger.With(zap.Error(err)).Error("failed to open the LUKS device.")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		logger.With("devicePath", devicePath, "luksDevice", luksDevice).Info("Opened the LUKS device.")
+		devicePath = luksDevice
+	}
+
+	if req.VolumeCapability.GetBlock() != nil {
+		// Raw block volumes are not formatted; the device is bind mounted into
+		// the staging path only so that it can be located at unstage time.
+		stagedDevicePath := stagedBlockDevicePath(req.StagingTargetPath)
+		logger.With("devicePath", devicePath, "stagedDevicePath", stagedDevicePath).Info("staging the raw block volume.")
+		if err := mountHandler.BindMountBlockDevice(devicePath, stagedDevicePath, nil); err != nil {
+			logger.With(zap.Error(err)).Error("failed to bind mount the block device to staging path.")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		logger.With("devicePath", devicePath, "attachmentType", attachment).
+			Info("Staging the raw block volume is completed.")
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
 	mnt := req.VolumeCapability.GetMount()
 	options := mnt.MountFlags
-
-	fsType := csi_util.ValidateFsType(logger, mnt.FsType)
+	// The attachment of a MULTI_NODE_READER_ONLY volume is read-only, mount it
+	// as such so that it is not formatted either.
+	if req.VolumeCapability.GetAccessMode().GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY {
+		options = append(options, "ro")
+	}
 
 	exists := true
 	_, err = os.Stat(req.StagingTargetPath)
@@ -195,15 +364,31 @@ func (d BlockVolumeNodeDriver) NodeUnstageVolume(ctx context.Context, req *csi.N
 
 	defer d.volumeLocks.Release(req.VolumeId)
 
-	diskPath, err := disk.GetDiskPathFromMountPath(d.logger, req.GetStagingTargetPath())
+	// A raw block volume is staged as a bind mounted device file inside the
+	// staging path rather than as a filesystem mounted on it.
+	stagedPath := req.GetStagingTargetPath()
+	stage, err := getStagedVolume(mount.New(d.logger, mountPath), stagedPath)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("unable to list the mount points")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	isRawBlock := stage == stagedRawBlock
+	if isRawBlock {
+		stagedPath = stagedBlockDevicePath(stagedPath)
+	}
+
+	diskPath, err := disk.GetDiskPathFromMountPath(d.logger, stagedPath)
 
 	if err != nil {
 		// do a clean exit in case of mount point not found
 		if err == disk.ErrMountPointNotFound {
-			logger.With(zap.Error(err)).With("mountPath", req.GetStagingTargetPath()).Warn("unable to fetch mount point")
+			logger.With(zap.Error(err)).With("mountPath", stagedPath).Warn("unable to fetch mount point")
+			if stage == notStaged {
+				removeStaleBlockDeviceFile(logger, req.GetStagingTargetPath())
+			}
 			return &csi.NodeUnstageVolumeResponse{}, nil
 		}
-		logger.With(zap.Error(err)).With("mountPath", req.GetStagingTargetPath()).Error("unable to get diskPath from mount path")
+		logger.With(zap.Error(err)).With("mountPath", stagedPath).Error("unable to get diskPath from mount path")
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -216,17 +401,16 @@ func (d BlockVolumeNodeDriver) NodeUnstageVolume(ctx context.Context, req *csi.N
 	var mountHandler disk.Interface
 	switch attachmentType {
 	case attachmentTypeISCSI:
-		scsiInfo, err := csi_util.ExtractISCSIInformationFromMountPath(d.logger, diskPath)
+		mountHandler, devicePath, err = d.getISCSIMountHandler(diskPath, devicePath)
 		if err != nil {
 			logger.With(zap.Error(err)).Error("failed to ISCSI info.")
 			return nil, status.Error(codes.Internal, err.Error())
 		}
-		if scsiInfo == nil {
+		if mountHandler == nil {
 			logger.Warn("unable to get the ISCSI info")
 			return &csi.NodeUnstageVolumeResponse{}, nil
 		}
-		mountHandler = disk.NewFromISCSIDisk(d.logger, scsiInfo)
-		logger.Info("starting to unstage iscsi Mounting.")
+		logger.With("devicePath", devicePath).Info("starting to unstage iscsi Mounting.")
 	case attachmentTypeParavirtualized:
 		mountHandler = disk.NewFromPVDisk(d.logger)
 		logger.Info("starting to unstage paravirtualized Mounting.")
@@ -234,21 +418,43 @@ func (d BlockVolumeNodeDriver) NodeUnstageVolume(ctx context.Context, req *csi.N
 		logger.Error("unknown attachment type. supported attachment types are iscsi and paravirtualized")
 		return nil, status.Error(codes.InvalidArgument, "unknown attachment type. supported attachment types are iscsi and paravirtualized")
 	}
-	isMounted, oErr := mountHandler.DeviceOpened(devicePath)
-	if oErr != nil {
-		logger.With(zap.Error(oErr)).Error("getting error to get the details about volume is already mounted or not.")
-		return nil, status.Error(codes.Internal, oErr.Error())
-	} else if !isMounted {
-		logger.Info("volume is already mounted on the staging path.")
-		return &csi.NodeUnstageVolumeResponse{}, nil
+	// The filesystem of a LUKS encrypted volume is on the LUKS device opened
+	// on the volume's device, which is closed once unmounted.
+	luksDevice, err := disk.FindLUKSDevice(devicePath)
+	if err != nil {
+		logger.With(zap.Error(err)).With("devicePath", devicePath).Warn("unable to look up the LUKS device")
+	}
+	openedPath := devicePath
+	if luksDevice != "" {
+		openedPath = luksDevice
+	}
+
+	// A bind mounted raw block device is not held open exclusively, so the
+	// check below only applies to volumes with a filesystem.
+	if !isRawBlock {
+		isMounted, oErr := mountHandler.DeviceOpened(openedPath)
+		if oErr != nil {
+			logger.With(zap.Error(oErr)).Error("getting error to get the details about volume is already mounted or not.")
+			return nil, status.Error(codes.Internal, oErr.Error())
+		} else if !isMounted {
+			logger.Info("volume is already mounted on the staging path.")
+			return &csi.NodeUnstageVolumeResponse{}, nil
+		}
 	}
 
-	err = mountHandler.UnmountPath(req.StagingTargetPath)
+	err = mountHandler.UnmountPath(stagedPath)
 	if err != nil {
 		logger.With(zap.Error(err)).Error("failed to unmount the staging path")
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if luksDevice != "" {
+		if err := disk.NewLUKS(d.logger).Close(luksDevice); err != nil {
+			logger.With(zap.Error(err)).With("luksDevice", luksDevice).Error("failed to close the LUKS device")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	err = mountHandler.Logout()
 	if err != nil {
 		logger.With(zap.Error(err)).Error("failed to logout from the iSCSI target")
@@ -288,6 +494,10 @@ func (d BlockVolumeNodeDriver) NodePublishVolume(ctx context.Context, req *csi.N
 		return nil, status.Error(codes.InvalidArgument, "Volume Capability must be provided")
 	}
 
+	if req.VolumeContext[ephemeralVolumeContextKey] == "true" {
+		return nil, status.Error(codes.InvalidArgument, "CSI inline ephemeral volumes are not supported, use generic ephemeral volumes instead")
+	}
+
 	logger := d.logger.With("volumeID", req.VolumeId, "targetPath", req.TargetPath)
 
 	attachment, ok := req.PublishContext[attachmentType]
@@ -303,16 +513,23 @@ func (d BlockVolumeNodeDriver) NodePublishVolume(ctx context.Context, req *csi.N
 
 	defer d.volumeLocks.Release(req.VolumeId)
 
+	isRawBlock := req.VolumeCapability.GetBlock() != nil
+
 	// k8s v1.20+ will not create the TargetPath directory
 	// https://github.com/kubernetes/kubernetes/pull/88759
 	// if the path exists already (<v1.20) this is a no op
 	// https://golang.org/pkg/os/#MkdirAll
-	if err := os.MkdirAll(req.TargetPath, 0750); err != nil {
-		logger.With(zap.Error(err)).Error("Failed to create TargetPath directory")
-		return nil, status.Error(codes.Internal, "Failed to create TargetPath directory")
+	// Raw block volumes are published to a file which is created when the
+	// device is bind mounted.
+	if !isRawBlock {
+		if err := os.MkdirAll(req.TargetPath, 0750); err != nil {
+			logger.With(zap.Error(err)).Error("Failed to create TargetPath directory")
+			return nil, status.Error(codes.Internal, "Failed to create TargetPath directory")
+		}
 	}
 
 	var mountHandler disk.Interface
+	var devicePath string
 
 	switch attachment {
 	case attachmentTypeISCSI:
@@ -321,10 +538,24 @@ func (d BlockVolumeNodeDriver) NodePublishVolume(ctx context.Context, req *csi.N
 			logger.With(zap.Error(err)).Error("Failed to get iSCSI info from publish context")
 			return nil, status.Error(codes.InvalidArgument, "PublishContext is invalid")
 		}
+		devicePath = csi_util.GetDevicePath(scsiInfo)
+		if multipathDisks, _ := csi_util.ExtractMultipathISCSIInformation(req.PublishContext); isRawBlock && len(multipathDisks) > 0 {
+			multipathDevice, err := disk.FindMultipathDevice(multipathDisks[0].DevicePath())
+			if err != nil || multipathDevice == "" {
+				logger.With(zap.Error(err)).Error("Failed to find the multipath device of the volume.")
+				return nil, status.Error(codes.FailedPrecondition, "multipath device of the volume not found, it is not staged")
+			}
+			devicePath = multipathDevice
+		}
 		mountHandler = disk.NewFromISCSIDisk(logger, scsiInfo)
 		logger.Info("starting to publish iSCSI Mounting.")
 
 	case attachmentTypeParavirtualized:
+		devicePath, ok = req.PublishContext[device]
+		if !ok && isRawBlock {
+			logger.Error("Unable to get the device from the attribute list")
+			return nil, status.Error(codes.InvalidArgument, "Unable to get the device from the attribute list")
+		}
 		mountHandler = disk.NewFromPVDisk(d.logger)
 		logger.Info("starting to publish paravirtualized Mounting.")
 	default:
@@ -332,6 +563,29 @@ func (d BlockVolumeNodeDriver) NodePublishVolume(ctx context.Context, req *csi.N
 		return nil, status.Error(codes.InvalidArgument, "unknown attachment type. supported attachment types are iscsi and paravirtualized")
 	}
 
+	if isRawBlock && req.VolumeContext[luksEncryption] == "true" {
+		luksDevice, err := disk.FindLUKSDevice(devicePath)
+		if err != nil || luksDevice == "" {
+			logger.With(zap.Error(err)).Error("Failed to find the LUKS device of the volume.")
+			return nil, status.Error(codes.FailedPrecondition, "LUKS device of the volume not found, it is not staged")
+		}
+		devicePath = luksDevice
+	}
+
+	if isRawBlock {
+		var options []string
+		if req.Readonly {
+			options = append(options, "ro")
+		}
+		logger.With("devicePath", devicePath).Info("bind mounting the raw block device to the target path.")
+		if err := mountHandler.BindMountBlockDevice(devicePath, req.TargetPath, options); err != nil {
+			logger.With(zap.Error(err)).Error("failed to bind mount the block device.")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		logger.With("attachmentType", attachment).Info("Publish raw block volume to the Node is Completed.")
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
 	mnt := req.VolumeCapability.GetMount()
 	options := mnt.MountFlags
 
@@ -340,9 +594,13 @@ func (d BlockVolumeNodeDriver) NodePublishVolume(ctx context.Context, req *csi.N
 		options = append(options, "ro")
 	}
 
-	fsType := csi_util.ValidateFsType(logger, mnt.FsType)
+	fsType, err := csi_util.ValidateFsType(mnt.FsType)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("Invalid fsType.")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-	err := mountHandler.Mount(req.StagingTargetPath, req.TargetPath, fsType, options)
+	err = mountHandler.Mount(req.StagingTargetPath, req.TargetPath, fsType, options)
 	if err != nil {
 		logger.With(zap.Error(err)).Error("failed to format and mount.")
 		return nil, status.Error(codes.Internal, err.Error())
@@ -416,6 +674,27 @@ func (d BlockVolumeNodeDriver) NodeUnpublishVolume(ctx context.Context, req *csi
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
+// getISCSIMountHandler returns the mount handler of the iSCSI volume behind
+// the disk by-paths and its device, which is the dm-multipath device of a
+// multipath attachment. The handler is nil if there is no iSCSI information.
+func (d BlockVolumeNodeDriver) getISCSIMountHandler(diskPath []string, devicePath string) (disk.Interface, string, error) {
+	if multipathDevice, err := disk.FindMultipathDevice(devicePath); err == nil && multipathDevice != "" {
+		disks, err := csi_util.ExtractAllISCSIInformationFromMountPath(d.logger, diskPath)
+		if err != nil {
+			return nil, "", err
+		}
+		d.logger.With("multipathDevice", multipathDevice, "paths", len(disks)).Info("Found multipath ISCSIInfo.")
+		return disk.NewFromMultipathISCSIDisks(d.logger, disks), multipathDevice, nil
+	}
+
+	scsiInfo, err := csi_util.ExtractISCSIInformationFromMountPath(d.logger, diskPath)
+	if err != nil || scsiInfo == nil {
+		return nil, devicePath, err
+	}
+	d.logger.With("ISCSIInfo", scsiInfo).Info("Found ISCSIInfo.")
+	return disk.NewFromISCSIDisk(d.logger, scsiInfo), devicePath, nil
+}
+
 func getDevicePathAndAttachmentType(logger *zap.SugaredLogger, path []string) (string, string, error) {
 	for _, diskByPath := range path {
 		matched, _ := regexp.MatchString(csi_util.DiskByPathPatternPV, diskByPath)
@@ -436,7 +715,12 @@ func getDevicePathAndAttachmentType(logger *zap.SugaredLogger, path []string) (s
 // NodeGetCapabilities returns the supported capabilities of the node server
 func (d BlockVolumeNodeDriver) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
 	var nscaps []*csi.NodeServiceCapability
-	nodeCaps := []csi.NodeServiceCapability_RPC_Type{csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME, csi.NodeServiceCapability_RPC_EXPAND_VOLUME}
+	nodeCaps := []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+	}
 	for _, nodeCap := range nodeCaps {
 		c := &csi.NodeServiceCapability{
 			Type: &csi.NodeServiceCapability_Rpc{
@@ -465,20 +749,25 @@ func (d BlockVolumeNodeDriver) NodeGetInfo(ctx context.Context, req *csi.NodeGet
 	d.logger.With("nodeId", d.nodeID, "availableDomain", ad).Info("Available domain of node identified.")
 	return &csi.NodeGetInfoResponse{
 		NodeId:            d.nodeID,
-		MaxVolumesPerNode: maxVolumesPerNode,
+		MaxVolumesPerNode: d.getMaxVolumesPerNode(ctx, disk.SysBlockPath),
 
 		// make sure that the driver works on this particular AD only
-		AccessibleTopology: &csi.Topology{
-			Segments: map[string]string{
-				kubeAPI.LabelZoneFailureDomain: ad,
-			},
-		},
+		AccessibleTopology: newBlockVolumeTopology(ad),
 	}, nil
 }
 
 // NodeGetVolumeStats return the stats of the volume
 func (d BlockVolumeNodeDriver) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "NodeGetVolumeStats is not supported yet")
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeGetVolumeStats Volume ID must be provided")
+	}
+
+	if req.VolumePath == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeGetVolumeStats Volume Path must be provided")
+	}
+
+	logger := d.logger.With("volumeID", req.VolumeId, "volumePath", req.VolumePath)
+	return getVolumeStats(logger, req.VolumePath)
 }
 
 //NodeExpandVolume returns the expand of the volume
@@ -530,13 +819,12 @@ func (d BlockVolumeNodeDriver) NodeExpandVolume(ctx context.Context, req *csi.No
 	var mountHandler disk.Interface
 	switch attachmentType {
 	case attachmentTypeISCSI:
-		scsiInfo, _ := csi_util.ExtractISCSIInformationFromMountPath(d.logger, diskPath)
-		if scsiInfo == nil {
+		mountHandler, devicePath, _ = d.getISCSIMountHandler(diskPath, devicePath)
+		if mountHandler == nil {
 			logger.Warn("unable to get the ISCSI info")
 			return &csi.NodeExpandVolumeResponse{}, nil
 		}
-		mountHandler = disk.NewFromISCSIDisk(d.logger, scsiInfo)
-		d.logger.With("ISCSIInfo", scsiInfo, "mountPath", volumePath).Info("Found ISCSIInfo for NodeExpandVolume.")
+		d.logger.With("devicePath", devicePath, "mountPath", volumePath).Info("Found ISCSIInfo for NodeExpandVolume.")
 	case attachmentTypeParavirtualized:
 		mountHandler = disk.NewFromPVDisk(d.logger)
 		logger.Info("starting to expand paravirtualized Mounting.")
@@ -550,8 +838,21 @@ func (d BlockVolumeNodeDriver) NodeExpandVolume(ctx context.Context, req *csi.No
 	}
 	logger.With("devicePath", devicePath).Debug("Rescan completed")
 
-	if _, err := mountHandler.Resize(devicePath, volumePath); err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to resize volume %q (%q):  %v", volumeID, devicePath, err)
+	// The LUKS device of an encrypted volume is grown to the new size of the
+	// volume before the filesystem on it.
+	if luksDevice, err := disk.FindLUKSDevice(devicePath); err == nil && luksDevice != "" {
+		if err := disk.NewLUKS(d.logger).Resize(luksDevice); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to resize LUKS device of volume %q (%q):  %v", volumeID, luksDevice, err)
+		}
+		devicePath = luksDevice
+	}
+
+	// There is no filesystem to grow on a raw block volume, the rescan is
+	// enough for the new size to be visible.
+	if req.GetVolumeCapability().GetBlock() == nil {
+		if _, err := mountHandler.Resize(devicePath, volumePath); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to resize volume %q (%q):  %v", volumeID, devicePath, err)
+		}
 	}
 
 	allocatedSizeBytes, err := mountHandler.GetBlockSizeBytes(devicePath)
@@ -0,0 +1,127 @@
+package driver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
+)
+
+func TestBlockVolumeNodeDriver_NodePublishVolumeRejectsInlineEphemeralVolumes(t *testing.T) {
+	d := BlockVolumeNodeDriver{NodeDriver: NodeDriver{
+		logger:      zap.S(),
+		volumeLocks: csi_util.NewVolumeLocks(),
+	}}
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:          "inline-volume",
+		PublishContext:    map[string]string{attachmentType: attachmentTypeISCSI},
+		StagingTargetPath: "/var/lib/kubelet/plugins/staging",
+		TargetPath:        "/var/lib/kubelet/pods/pod/volumes/volume",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		},
+		VolumeContext: map[string]string{ephemeralVolumeContextKey: "true"},
+	}
+
+	_, err := d.NodePublishVolume(context.Background(), req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("NodePublishVolume() => %v, expected an InvalidArgument error for an inline ephemeral volume", err)
+	}
+}
+
+type fakeMountLister []mount.MountPoint
+
+func (ml fakeMountLister) List() ([]mount.MountPoint, error) {
+	return ml, nil
+}
+
+func TestGetStagedVolume(t *testing.T) {
+	stagingPath, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(stagingPath)
+	// A file of the user on the filesystem of the volume, named like the
+	// device file of a raw block volume.
+	devicePath := stagedBlockDevicePath(stagingPath)
+	if err := ioutil.WriteFile(devicePath, []byte("user data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		mountPoints fakeMountLister
+		expected    stagedVolume
+	}{
+		"regular device file on a mounted filesystem": {
+			mountPoints: fakeMountLister{{Device: "/dev/sdb", Path: stagingPath, Type: "ext4"}},
+			expected:    stagedFilesystem,
+		},
+		"bind mounted block device": {
+			mountPoints: fakeMountLister{{Device: "devtmpfs", Path: devicePath, Type: "devtmpfs"}},
+			expected:    stagedRawBlock,
+		},
+		"nothing mounted": {
+			mountPoints: fakeMountLister{{Device: "/dev/sda1", Path: "/", Type: "xfs"}},
+			expected:    notStaged,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stage, err := getStagedVolume(tc.mountPoints, stagingPath+"/")
+			if err != nil {
+				t.Fatalf("getStagedVolume() => unexpected error %v", err)
+			}
+			if stage != tc.expected {
+				t.Errorf("getStagedVolume() => %v, expected %v", stage, tc.expected)
+			}
+		})
+	}
+	if _, err := os.Stat(devicePath); err != nil {
+		t.Errorf("expected the user's device file to be kept, got %v", err)
+	}
+}
+
+func TestRemoveStaleBlockDeviceFile(t *testing.T) {
+	testCases := map[string]struct {
+		content  []byte
+		expected bool
+	}{
+		"empty device file left by an interrupted unstage": {
+			content:  []byte{},
+			expected: false,
+		},
+		"file with content": {
+			content:  []byte("user data"),
+			expected: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stagingPath, err := ioutil.TempDir("", "staging")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(stagingPath)
+			devicePath := filepath.Join(stagingPath, stagedBlockDeviceFileName)
+			if err := ioutil.WriteFile(devicePath, tc.content, 0640); err != nil {
+				t.Fatal(err)
+			}
+
+			removeStaleBlockDeviceFile(zap.S(), stagingPath)
+
+			_, err = os.Stat(devicePath)
+			if exists := err == nil; exists != tc.expected {
+				t.Errorf("device file exists => %t, expected %t", exists, tc.expected)
+			}
+		})
+	}
+}
@@ -9,11 +9,14 @@ import (
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	kubeAPI "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
 	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
@@ -38,14 +41,22 @@ const (
 	initialDefinedTagsOverride    = "oci.oraclecloud.com/initial-defined-tags-override"
 	//device is the consistent device path that would be used for paravirtualized attachment
 	device = "device"
+	//backupType is the VolumeSnapshotClass parameter that selects a full or incremental volume backup
+	backupType = "backupType"
 )
 
 var (
-	// OCI currently only support a single node to be attached to a single node
-	// in read/write mode. This corresponds to `accessModes.ReadWriteOnce` in a
-	// PVC resource on Kubernetes
-	supportedAccessMode = &csi.VolumeCapability_AccessMode{
-		Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+	// supportedAccessModes are the access modes OCI block volumes can be
+	// attached with. SINGLE_NODE_WRITER (`accessModes.ReadWriteOnce` on
+	// Kubernetes) uses an exclusive attachment, the multi node modes use
+	// shareable attachments which are read-only for MULTI_NODE_READER_ONLY
+	// (`accessModes.ReadOnlyMany`). MULTI_NODE_MULTI_WRITER
+	// (`accessModes.ReadWriteMany`) is only supported for raw block volumes
+	// as there is no cluster aware filesystem to put on the volume.
+	supportedAccessModes = []csi.VolumeCapability_AccessMode_Mode{
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
 	}
 )
 
@@ -60,6 +71,10 @@ type VolumeParameters struct {
 	definedTags map[string]map[string]interface{}
 	//volume performance units per gb describes the block volume performance level
 	vpusPerGB int64
+	//useChap requires CHAP authentication for the iSCSI attachments of the volume
+	useChap bool
+	//luksEncryption encrypts the volume on the nodes with a passphrase from the node stage secret
+	luksEncryption bool
 }
 
 // VolumeAttachmentOption holds config for attachments
@@ -120,9 +135,31 @@ func extractVolumeParameters(parameters map[string]string) (VolumeParameters, er
 				return p, status.Error(codes.InvalidArgument, err.Error())
 			}
 			p.vpusPerGB = vpusPerGB
+
+		case iscsiChap:
+			useChap, err := strconv.ParseBool(v)
+			if err != nil {
+				return p, status.Errorf(codes.InvalidArgument, "invalid %s: %s provided for storageclass, it must be true or false", iscsiChap, v)
+			}
+			p.useChap = useChap
+
+		case luksEncryption:
+			encrypted, err := strconv.ParseBool(v)
+			if err != nil {
+				return p, status.Errorf(codes.InvalidArgument, "invalid %s: %s provided for storageclass, it must be true or false", luksEncryption, v)
+			}
+			p.luksEncryption = encrypted
 		}
 
 	}
+	if p.useChap && p.attachmentParameter[attachmentType] == attachmentTypeParavirtualized {
+		return p, status.Errorf(codes.InvalidArgument, "%s is only supported with the %s attachment-type", iscsiChap, attachmentTypeISCSI)
+	}
+	// The CHAP credentials and the LUKS passphrase would both have to be the
+	// node stage secret of the volume.
+	if p.useChap && p.luksEncryption {
+		return p, status.Errorf(codes.InvalidArgument, "%s can't be combined with %s", luksEncryption, iscsiChap)
+	}
 	return p, nil
 }
 
@@ -143,7 +180,14 @@ func (d *ControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolu
 	}
 
 	if !d.validateCapabilities(req.VolumeCapabilities) {
-		return nil, status.Error(codes.InvalidArgument, "invalid volume capabilities requested. Only SINGLE_NODE_WRITER is supported ('accessModes.ReadWriteOnce' on Kubernetes)")
+		return nil, status.Error(codes.InvalidArgument, "invalid volume capabilities requested. Only SINGLE_NODE_WRITER, MULTI_NODE_READER_ONLY and MULTI_NODE_MULTI_WRITER "+
+			"(raw block volumes only) are supported ('accessModes.ReadWriteOnce', 'accessModes.ReadOnlyMany' and 'accessModes.ReadWriteMany' on Kubernetes)")
+	}
+
+	for _, cap := range req.VolumeCapabilities {
+		if _, err := csi_util.ValidateFsType(cap.GetMount().GetFsType()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 	}
 
 	size, err := csi_util.ExtractStorage(req.CapacityRange)
@@ -151,40 +195,69 @@ func (d *ControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolu
 		return nil, status.Errorf(codes.OutOfRange, "invalid capacity range: %v", err)
 	}
 
-	availableDomainShortName := ""
-	if req.AccessibilityRequirements != nil && req.AccessibilityRequirements.Preferred != nil {
-		for _, t := range req.AccessibilityRequirements.Preferred {
-			availableDomainShortName, _ = t.Segments[kubeAPI.LabelZoneFailureDomain]
-			log.With("AD", availableDomainShortName).Info("Using preferred topology for AD.")
-			if len(availableDomainShortName) > 0 {
-				break
-			}
-		}
-	}
-
-	if availableDomainShortName == "" {
-		if req.AccessibilityRequirements != nil && req.AccessibilityRequirements.Requisite != nil {
-			for _, t := range req.AccessibilityRequirements.Requisite {
-				availableDomainShortName, _ = t.Segments[kubeAPI.LabelZoneFailureDomain]
-				log.With("AD", availableDomainShortName).Info("Using requisite topology for AD.")
-				if len(availableDomainShortName) > 0 {
-					break
-				}
-			}
-		}
-	}
+	// The volume is created in the first availability domain that has capacity
+	// left, in the order of the preferred and then the requisite topologies.
+	availableDomains := getTopologyADs(req.AccessibilityRequirements)
+	log.With("ADs", availableDomains).Info("Using topology requirement for AD.")
 
 	volumeName := req.Name
 
 	dimensionsMap := make(map[string]string)
 	dimensionsMap[metrics.ResourceOCIDDimension] = volumeName
 
-	if availableDomainShortName == "" {
+	if len(availableDomains) == 0 {
 		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
 		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
 		metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
 		log.Error("Available domain short name is not found")
-		return nil, status.Errorf(codes.InvalidArgument, "%s is required in PreferredTopologies or allowedTopologies", kubeAPI.LabelZoneFailureDomain)
+		return nil, status.Errorf(codes.InvalidArgument, "%s is required in PreferredTopologies or allowedTopologies", kubeAPI.LabelTopologyZone)
+	}
+	availableDomainShortName := availableDomains[0]
+
+	var volumeContentSource *csi.VolumeContentSource
+	var volumeSourceDetails core.VolumeSourceDetails
+	if snapshot := req.GetVolumeContentSource().GetSnapshot(); snapshot != nil {
+		backupID := snapshot.GetSnapshotId()
+		log = log.With("snapshotId", backupID)
+		if err = d.validateVolumeBackupForRestore(ctx, backupID, size); err != nil {
+			log.With(zap.Error(err)).Error("Volume snapshot can not be restored.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, err
+		}
+		volumeContentSource = req.GetVolumeContentSource()
+		volumeSourceDetails = core.VolumeSourceFromVolumeBackupDetails{Id: &backupID}
+	}
+
+	if sourceVolume := req.GetVolumeContentSource().GetVolume(); sourceVolume != nil {
+		sourceVolumeID := sourceVolume.GetVolumeId()
+		log = log.With("sourceVolumeId", sourceVolumeID)
+		sourceAD, err := d.validateVolumeForClone(ctx, sourceVolumeID, size)
+		if err == nil && !strings.EqualFold(availableDomainShortName, sourceAD) {
+			// The preferred topologies are only an ordering of the accessible ones, so the clone follows its source
+			// whenever the source AD is accessible at all.
+			if topologyContainsAD(req.AccessibilityRequirements.GetPreferred(), sourceAD) ||
+				topologyContainsAD(req.AccessibilityRequirements.GetRequisite(), sourceAD) {
+				availableDomainShortName = sourceAD
+			} else {
+				err = status.Errorf(codes.InvalidArgument, "source volume %s is in availability domain %s, cloning it to "+
+					"availability domain %s is not supported", sourceVolumeID, sourceAD, availableDomainShortName)
+			}
+		}
+		if err != nil {
+			log.With(zap.Error(err)).Error("Volume can not be cloned.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, err
+		}
+		volumeContentSource = req.GetVolumeContentSource()
+		volumeSourceDetails = core.VolumeSourceFromVolumeDetails{Id: &sourceVolumeID}
+		// A clone can only be created in the availability domain of its source.
+		availableDomains = []string{availableDomainShortName}
 	}
 
 	//make sure this method is idempotent by checking existence of volume with same name.
@@ -224,17 +297,6 @@ func (d *ControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolu
 		provisionedVolume = volumes[0]
 
 	} else {
-		// Creating new volume
-		ad, err := d.client.Identity().GetAvailabilityDomainByName(context.Background(), d.config.CompartmentID, availableDomainShortName)
-		if err != nil {
-			log.With("Compartment Id", d.config.CompartmentID).Error("Failed to get available domain %s", err)
-			errorType = util.GetError(err)
-			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
-			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
-			metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
-			return nil, status.Errorf(codes.InvalidArgument, "invalid available domain: %s or compartment ID: %s", availableDomainShortName, d.config.CompartmentID)
-		}
-
 		// use initial tags for all BVs
 		bvTags := &config.TagConfig{}
 		if d.config.Tags != nil && d.config.Tags.BlockVolume != nil {
@@ -252,14 +314,35 @@ func (d *ControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolu
 			bvTags = scTags
 		}
 
-		provisionedVolume, err = provision(log, d.client, volumeName, size, *ad.Name, d.config.CompartmentID, "",
-			volumeParams.diskEncryptionKey, volumeParams.vpusPerGB, timeout, bvTags)
-		if err != nil {
+		// Creating new volume
+		for i, availableDomainShortName := range availableDomains {
+			ad, err := d.client.Identity().GetAvailabilityDomainByName(context.Background(), d.config.CompartmentID, availableDomainShortName)
+			if err != nil {
+				log.With("Compartment Id", d.config.CompartmentID).Error("Failed to get available domain %s", err)
+				errorType = util.GetError(err)
+				csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+				dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+				metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
+				return nil, status.Errorf(codes.InvalidArgument, "invalid available domain: %s or compartment ID: %s", availableDomainShortName, d.config.CompartmentID)
+			}
+
+			provisionedVolume, err = provision(log, d.client, volumeName, size, *ad.Name, d.config.CompartmentID, volumeSourceDetails,
+				volumeParams.diskEncryptionKey, volumeParams.vpusPerGB, timeout, bvTags)
+			if err == nil {
+				break
+			}
+			if client.IsOutOfCapacity(err) && i < len(availableDomains)-1 {
+				log.With(zap.Error(err)).With("Ad name", *ad.Name).Warn("Availability domain is out of capacity, trying the next one.")
+				continue
+			}
 			log.With("Ad name", *ad.Name, "Compartment Id", d.config.CompartmentID).Error("New volume creation failed %s", err)
 			errorType = util.GetError(err)
 			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
 			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
 			metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
+			if client.IsOutOfCapacity(err) {
+				return nil, status.Errorf(codes.ResourceExhausted, "New volume creation failed %v", err.Error())
+			}
 			return nil, status.Errorf(codes.Internal, "New volume creation failed %v", err.Error())
 		}
 	}
@@ -285,25 +368,112 @@ func (d *ControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolu
 	dimensionsMap[metrics.ResourceOCIDDimension] = volumeOCID
 	metrics.SendMetricData(d.metricPusher, metrics.PVProvision, time.Since(startTime).Seconds(), dimensionsMap)
 
+	volumeContext := map[string]string{
+		attachmentType:     volumeParams.attachmentParameter[attachmentType],
+		csi_util.VpusPerGB: strconv.FormatInt(volumeParams.vpusPerGB, 10),
+	}
+	if volumeParams.useChap {
+		volumeContext[iscsiChap] = strconv.FormatBool(volumeParams.useChap)
+		volumeContext[iscsiChapSecretName] = getISCSIChapSecretName(req.Name)
+	}
+	if volumeParams.luksEncryption {
+		volumeContext[luksEncryption] = strconv.FormatBool(volumeParams.luksEncryption)
+	}
+
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
 			VolumeId:      *provisionedVolume.Id,
 			CapacityBytes: *provisionedVolume.SizeInMBs * client.MiB,
 			AccessibleTopology: []*csi.Topology{
-				{
-					Segments: map[string]string{
-						kubeAPI.LabelZoneFailureDomain: d.util.GetAvailableDomainInNodeLabel(*provisionedVolume.AvailabilityDomain),
-					},
-				},
-			},
-			VolumeContext: map[string]string{
-				attachmentType:     volumeParams.attachmentParameter[attachmentType],
-				csi_util.VpusPerGB: strconv.FormatInt(volumeParams.vpusPerGB, 10),
+				newBlockVolumeTopology(d.util.GetAvailableDomainInNodeLabel(*provisionedVolume.AvailabilityDomain)),
 			},
+			VolumeContext: volumeContext,
+			ContentSource: volumeContentSource,
 		},
 	}, nil
 }
 
+// validateVolumeBackupForRestore checks that the volume backup behind a
+// VolumeSnapshot data source exists, is available and fits in a volume of the
+// requested size.
+func (d *ControllerDriver) validateVolumeBackupForRestore(ctx context.Context, backupID string, size int64) error {
+	if backupID == "" {
+		return status.Error(codes.InvalidArgument, "snapshot ID must be provided in the volume content source")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backup, err := d.client.BlockStorage().GetVolumeBackup(ctx, backupID)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return status.Errorf(codes.NotFound, "volume backup %s for snapshot source not found", backupID)
+		}
+		return status.Errorf(codes.Internal, "failed to get volume backup %s: %v", backupID, err)
+	}
+
+	if backup.LifecycleState != core.VolumeBackupLifecycleStateAvailable {
+		return status.Errorf(codes.Unavailable, "volume backup %s is in lifecycle state %s, it must be %s to be restored",
+			backupID, backup.LifecycleState, core.VolumeBackupLifecycleStateAvailable)
+	}
+
+	if backup.SizeInGBs != nil {
+		requestedSizeInGB := csi_util.RoundUpSize(size, 1*client.GiB)
+		if requestedSizeInGB < *backup.SizeInGBs {
+			return status.Errorf(codes.OutOfRange, "requested volume size %dGB is smaller than the size %dGB of volume backup %s",
+				requestedSizeInGB, *backup.SizeInGBs, backupID)
+		}
+	}
+
+	return nil
+}
+
+// validateVolumeForClone checks that the source volume of a clone exists, is
+// available and fits in a volume of the requested size. It returns the short
+// name of the source volume's availability domain, which the clone must share.
+func (d *ControllerDriver) validateVolumeForClone(ctx context.Context, sourceVolumeID string, size int64) (string, error) {
+	if sourceVolumeID == "" {
+		return "", status.Error(codes.InvalidArgument, "source volume ID must be provided in the volume content source")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sourceVolume, err := d.client.BlockStorage().GetVolume(ctx, sourceVolumeID)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return "", status.Errorf(codes.NotFound, "source volume %s not found", sourceVolumeID)
+		}
+		return "", status.Errorf(codes.Internal, "failed to get source volume %s: %v", sourceVolumeID, err)
+	}
+
+	if sourceVolume.LifecycleState != core.VolumeLifecycleStateAvailable {
+		return "", status.Errorf(codes.Unavailable, "source volume %s is in lifecycle state %s, it must be %s to be cloned",
+			sourceVolumeID, sourceVolume.LifecycleState, core.VolumeLifecycleStateAvailable)
+	}
+
+	if sourceVolume.SizeInGBs != nil {
+		requestedSizeInGB := csi_util.RoundUpSize(size, 1*client.GiB)
+		if requestedSizeInGB < *sourceVolume.SizeInGBs {
+			return "", status.Errorf(codes.OutOfRange, "requested volume size %dGB is smaller than the size %dGB of source volume %s",
+				requestedSizeInGB, *sourceVolume.SizeInGBs, sourceVolumeID)
+		}
+	}
+
+	return d.util.GetAvailableDomainInNodeLabel(*sourceVolume.AvailabilityDomain), nil
+}
+
+// topologyContainsAD returns true if any of the given topologies is in the
+// availability domain with the given short name.
+func topologyContainsAD(topologies []*csi.Topology, availableDomainShortName string) bool {
+	for _, t := range topologies {
+		if strings.EqualFold(getTopologyAD(t), availableDomainShortName) {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteVolume deletes the given volume. The function is idempotent.
 func (d *ControllerDriver) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
 	startTime := time.Now()
@@ -324,6 +494,15 @@ func (d *ControllerDriver) DeleteVolume(ctx context.Context, req *csi.DeleteVolu
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if err := d.deleteVolumeISCSIChapSecret(ctx, req.VolumeId); err != nil {
+		log.With(zap.Error(err)).Error("Failed to delete the iSCSI CHAP secret of the volume.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.PVDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to delete the iSCSI CHAP secret of volume %s: %v", req.VolumeId, err)
+	}
+
 	err := d.client.BlockStorage().DeleteVolume(ctx, req.VolumeId)
 	if err != nil && !apierrors.IsNotFound(err) {
 		log.With(zap.Error(err)).Error("Failed to delete volume.")
@@ -397,6 +576,14 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 		metrics.SendMetricData(d.metricPusher, metrics.PVAttach, time.Since(startTime).Seconds(), dimensionsMap)
 		return nil, status.Errorf(codes.InvalidArgument, "node %s has in transit encryption enabled, but attachment type is not paravirtualized. invalid input", id)
 	}
+	useChap := req.VolumeContext[iscsiChap] == "true"
+	if useChap && volumeAttachmentOptions.useParavirtualizedAttachment {
+		log.Errorf("%s is only supported with the %s attachment-type. invalid input", iscsiChap, attachmentTypeISCSI)
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.PVAttach, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.InvalidArgument, "%s is only supported with the %s attachment-type", iscsiChap, attachmentTypeISCSI)
+	}
 
 	compartmentID, err := util.LookupNodeCompartment(d.KubeClient, req.NodeId)
 	if err != nil {
@@ -408,7 +595,19 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 		return nil, status.Errorf(codes.Unknown, "failed to get compartmentID from node annotation:. error : %s", err)
 	}
 
-	volumeAttached, err := d.client.Compute().FindActiveVolumeAttachment(context.Background(), compartmentID, req.VolumeId)
+	// Shareable attachments let a volume be attached to several nodes, so only
+	// the attachment to this node matters; otherwise any attachment does.
+	accessMode := req.VolumeCapability.GetAccessMode().GetMode()
+	isShareable := isMultiNodeAccessMode(accessMode)
+	isReadOnly := req.Readonly || accessMode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+	log = log.With("accessMode", accessMode, "isShareable", isShareable, "isReadOnly", isReadOnly)
+
+	var volumeAttached core.VolumeAttachment
+	if isShareable {
+		volumeAttached, err = d.client.Compute().FindActiveVolumeAttachmentForInstance(context.Background(), compartmentID, req.VolumeId, id)
+	} else {
+		volumeAttached, err = d.client.Compute().FindActiveVolumeAttachment(context.Background(), compartmentID, req.VolumeId)
+	}
 
 	if err != nil && !client.IsNotFound(err) {
 		log.With(zap.Error(err)).Error("Got error in finding volume attachment: %s", err)
@@ -424,6 +623,15 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 		log.Warnf("No vpusPerGB found in Volume Context falling back to balanced performance")
 		vpusPerGB = "10"
 	}
+	// The performance level can be changed after the volume was created
+	// through VolumePerformanceAnnotation, in which case prefer the current one.
+	if d.volumePerformance != nil && d.volumePerformance.performanceRequested(req.VolumeId) {
+		if volume, err := d.client.BlockStorage().GetVolume(ctx, req.VolumeId); err != nil {
+			log.With(zap.Error(err)).Warnf("Failed to get the current vpusPerGB of the volume, using %s", vpusPerGB)
+		} else if volume != nil && volume.VpusPerGB != nil {
+			vpusPerGB = strconv.FormatInt(*volume.VpusPerGB, 10)
+		}
+	}
 
 	// volume already attached to an instance
 	if err == nil {
@@ -460,6 +668,16 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 					return nil, status.Errorf(codes.Internal, "Failed to attach volume to the node: %s", err)
 				}
 				log.Info("Volume is already ATTACHED to node.")
+				if useChap {
+					if err = storeISCSIChapCredentials(ctx, d.KubeClient, req.VolumeContext[iscsiChapSecretName], req.NodeId, volumeAttached); err != nil {
+						log.With(zap.Error(err)).Error("Failed to store the CHAP credentials of the attachment.")
+						errorType = util.GetError(err)
+						csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+						dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+						metrics.SendMetricData(d.metricPusher, metrics.PVAttach, time.Since(startTime).Seconds(), dimensionsMap)
+						return nil, status.Errorf(codes.Internal, "Failed to store the CHAP credentials of the attachment: %s", err)
+					}
+				}
 				return generatePublishContext(volumeAttachmentOptions, log, volumeAttached, vpusPerGB), nil
 			}
 		}
@@ -468,7 +686,7 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 	log.Info("Attaching volume to instance")
 
 	if volumeAttachmentOptions.useParavirtualizedAttachment {
-		volumeAttached, err = d.client.Compute().AttachParavirtualizedVolume(context.Background(), id, req.VolumeId, volumeAttachmentOptions.enableInTransitEncryption)
+		volumeAttached, err = d.client.Compute().AttachParavirtualizedVolume(context.Background(), id, req.VolumeId, volumeAttachmentOptions.enableInTransitEncryption, isShareable, isReadOnly)
 		if err != nil {
 			log.With(zap.Error(err)).Info("failed paravirtualized attachment instance to volume.")
 			errorType = util.GetError(err)
@@ -478,7 +696,7 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 			return nil, status.Errorf(codes.Internal, "failed paravirtualized attachment instance to volume. error : %s", err)
 		}
 	} else {
-		volumeAttached, err = d.client.Compute().AttachVolume(context.Background(), id, req.VolumeId)
+		volumeAttached, err = d.client.Compute().AttachVolume(context.Background(), id, req.VolumeId, isShareable, isReadOnly, useChap)
 		if err != nil {
 			log.With(zap.Error(err)).Info("failed iscsi attachment instance to volume.")
 			errorType = util.GetError(err)
@@ -499,6 +717,17 @@ func (d *ControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi
 		return nil, status.Errorf(codes.Internal, "Failed to attach volume to the node %s", err)
 	}
 
+	if useChap {
+		if err = storeISCSIChapCredentials(ctx, d.KubeClient, req.VolumeContext[iscsiChapSecretName], req.NodeId, volumeAttached); err != nil {
+			log.With(zap.Error(err)).Error("Failed to store the CHAP credentials of the attachment.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.PVAttach, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, status.Errorf(codes.Internal, "Failed to store the CHAP credentials of the attachment: %s", err)
+		}
+	}
+
 	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
 	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
 	metrics.SendMetricData(d.metricPusher, metrics.PVAttach, time.Since(startTime).Seconds(), dimensionsMap)
@@ -521,14 +750,25 @@ func generatePublishContext(volumeAttachmentOptions VolumeAttachmentOption, log
 
 	log.With("volumeAttachedId", *volumeAttached.GetId()).Info("Publishing iSCSI Volume Completed.")
 
+	publishContext := map[string]string{
+		attachmentType:     attachmentTypeISCSI,
+		disk.ISCSIIQN:      *iSCSIVolumeAttached.Iqn,
+		disk.ISCSIIP:       *iSCSIVolumeAttached.Ipv4,
+		disk.ISCSIPORT:     strconv.Itoa(*iSCSIVolumeAttached.Port),
+		csi_util.VpusPerGB: vpusPerGB,
+	}
+	// Multipath attachments of ultra high performance volumes have an iSCSI
+	// endpoint per path, the node logs into all of them.
+	if iSCSIVolumeAttached.IsMultipath != nil && *iSCSIVolumeAttached.IsMultipath && len(iSCSIVolumeAttached.MultipathDevices) > 1 {
+		var devices []string
+		for _, device := range iSCSIVolumeAttached.MultipathDevices {
+			devices = append(devices, (&disk.Disk{IQN: *device.Iqn, IPv4: *device.Ipv4, Port: *device.Port}).String())
+		}
+		publishContext[disk.ISCSIMULTIPATHDEVICES] = strings.Join(devices, ",")
+	}
+
 	return &csi.ControllerPublishVolumeResponse{
-		PublishContext: map[string]string{
-			attachmentType:     attachmentTypeISCSI,
-			disk.ISCSIIQN:      *iSCSIVolumeAttached.Iqn,
-			disk.ISCSIIP:       *iSCSIVolumeAttached.Ipv4,
-			disk.ISCSIPORT:     strconv.Itoa(*iSCSIVolumeAttached.Port),
-			csi_util.VpusPerGB: vpusPerGB,
-		},
+		PublishContext: publishContext,
 	}
 }
 
@@ -564,7 +804,20 @@ func (d *ControllerDriver) ControllerUnpublishVolume(ctx context.Context, req *c
 		return nil, status.Errorf(codes.Unknown, "failed to get compartmentID from node annotation:: error : %s", err)
 	}
 
-	attachedVolume, err := d.client.Compute().FindVolumeAttachment(context.Background(), compartmentID, req.VolumeId)
+	instanceID, err := d.util.LookupNodeID(d.KubeClient, req.NodeId)
+	if err != nil {
+		log.With(zap.Error(err)).With("nodeId", req.NodeId).Error("Failed to lookup node")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.PVDetach, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Unknown, "failed to get ProviderID by nodeName. error : %s", err)
+	}
+	instanceID = client.MapProviderIDToInstanceID(instanceID)
+
+	// A shareable volume may be attached to other nodes too, only the
+	// attachment to this node is detached.
+	attachedVolume, err := d.client.Compute().FindVolumeAttachmentForInstance(context.Background(), compartmentID, req.VolumeId, instanceID)
 	if err != nil {
 		if client.IsNotFound(err) {
 			log.With(zap.Error(err)).With("compartmentID", compartmentID).With("nodeId", req.NodeId).Error("Unable to find volume " +
@@ -600,6 +853,11 @@ func (d *ControllerDriver) ControllerUnpublishVolume(ctx context.Context, req *c
 		return nil, status.Errorf(codes.Unknown, "timed out waiting for volume to be detached %s", err)
 	}
 
+	// Left behind credentials are removed with the volume's secret.
+	if err = d.removeNodeISCSIChapCredentials(ctx, req.VolumeId, req.NodeId, attachedVolume); err != nil {
+		log.With(zap.Error(err)).With("nodeId", req.NodeId).Warn("Failed to remove the iSCSI CHAP credentials of the node.")
+	}
+
 	log.With("volumeAttachedId", attachedVolume.GetId()).Info("Un-publishing Volume Completed.")
 	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
 	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
@@ -632,27 +890,175 @@ func (d *ControllerDriver) ValidateVolumeCapabilities(ctx context.Context, req *
 	}
 
 	if *volume.Id == req.VolumeId {
+		if !d.validateCapabilities(req.VolumeCapabilities) {
+			return &csi.ValidateVolumeCapabilitiesResponse{
+				Message: "requested volume capabilities are not supported",
+			}, nil
+		}
 		return &csi.ValidateVolumeCapabilitiesResponse{
 			Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
-				VolumeCapabilities: []*csi.VolumeCapability{
-					{
-						AccessMode: supportedAccessMode,
-					},
-				},
+				VolumeCapabilities: req.VolumeCapabilities,
 			},
 		}, nil
 	}
 	return nil, status.Errorf(codes.NotFound, "VolumeId mis-match.")
 }
 
-// ListVolumes returns a list of all requested volumes
+// ListVolumes returns a page of the block volumes in the cluster compartment
+// along with the nodes each volume is published to and its condition. Only the
+// volumes of the persistent volumes of the driver are listed, leaving out the
+// volumes of other clusters and the ones created outside of Kubernetes. The
+// pages are those of the compartment's volumes, so a page may hold fewer
+// entries than MaxEntries, or none, and still have a NextToken.
 func (d *ControllerDriver) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+	log := d.logger.With("startingToken", req.StartingToken)
+
+	if req.MaxEntries < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ListVolumes max entries must not be negative: %d", req.MaxEntries)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var page *string
+	if req.StartingToken != "" {
+		page = &req.StartingToken
+	}
+
+	volumes, nextPage, err := d.client.BlockStorage().ListVolumes(ctx, d.config.CompartmentID, int(req.MaxEntries), page)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to list volumes.")
+		if page != nil && client.IsBadRequest(err) {
+			return nil, status.Errorf(codes.Aborted, "invalid starting token %q: %v", req.StartingToken, err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list volumes %v", err)
+	}
+
+	volumeHandles, err := d.getVolumeHandles(ctx)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the persistent volumes of the driver.")
+		return nil, status.Errorf(codes.Internal, "failed to list persistent volumes %v", err)
+	}
+	driverVolumeIDs := make(map[string]bool, len(volumeHandles))
+	for _, volumeID := range volumeHandles {
+		driverVolumeIDs[volumeID] = true
+	}
+
+	publishedNodeIDs, err := d.getPublishedNodeIDs(ctx, "")
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the nodes volumes are published to.")
+		return nil, status.Errorf(codes.Internal, "failed to get published nodes %v", err)
+	}
+
+	expectedNodeIDs, err := d.getExpectedNodeIDs(ctx, volumeHandles)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the nodes volumes are expected to be attached to.")
+		return nil, status.Errorf(codes.Internal, "failed to get volume attachments %v", err)
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, 0, len(volumes))
+	for i := range volumes {
+		if client.IsVolumeTerminated(&volumes[i]) || !driverVolumeIDs[*volumes[i].Id] {
+			continue
+		}
+		volumeID := *volumes[i].Id
+		entries = append(entries, &csi.ListVolumesResponse_Entry{
+			Volume: d.volumeToCSIVolume(&volumes[i]),
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodeIDs[volumeID],
+				VolumeCondition:  getVolumeCondition(&volumes[i], publishedNodeIDs[volumeID], expectedNodeIDs[volumeID]),
+			},
+		})
+	}
+
+	resp := &csi.ListVolumesResponse{Entries: entries}
+	if nextPage != nil {
+		resp.NextToken = *nextPage
+	}
+	return resp, nil
 }
 
-// GetCapacity returns the capacity of the storage pool
+// GetCapacity returns the block volume capacity left in the availability
+// domain of the requested topology, or in all availability domains if no
+// topology is given, according to the block volume service limits and quotas
+// of the cluster compartment.
 func (d *ControllerDriver) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+	log := d.logger.With("accessibleTopology", req.AccessibleTopology)
+
+	if len(req.VolumeCapabilities) > 0 && !d.validateCapabilities(req.VolumeCapabilities) {
+		return &csi.GetCapacityResponse{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var availabilityDomains []string
+	availableDomainShortName := getTopologyAD(req.GetAccessibleTopology())
+	if availableDomainShortName != "" {
+		ad, err := d.client.Identity().GetAvailabilityDomainByName(ctx, d.config.CompartmentID, availableDomainShortName)
+		if err != nil {
+			log.With(zap.Error(err)).Error("Failed to get availability domain.")
+			return nil, status.Errorf(codes.Internal, "failed to get availability domain %s: %v", availableDomainShortName, err)
+		}
+		availabilityDomains = append(availabilityDomains, *ad.Name)
+	} else {
+		ads, err := d.client.Identity().ListAvailabilityDomains(ctx, d.config.CompartmentID)
+		if err != nil {
+			log.With(zap.Error(err)).Error("Failed to list availability domains.")
+			return nil, status.Errorf(codes.Internal, "failed to list availability domains: %v", err)
+		}
+		for _, ad := range ads {
+			availabilityDomains = append(availabilityDomains, *ad.Name)
+		}
+	}
+
+	var availableCapacity int64
+	for _, ad := range availabilityDomains {
+		capacity, err := d.getAvailableCapacity(ctx, ad)
+		if err != nil {
+			log.With(zap.Error(err)).With("availabilityDomain", ad).Error("Failed to get block volume resource availability.")
+			return nil, status.Errorf(codes.Internal, "failed to get block volume resource availability in %s: %v", ad, err)
+		}
+		availableCapacity += capacity
+	}
+
+	resp := &csi.GetCapacityResponse{
+		AvailableCapacity: availableCapacity,
+		MinimumVolumeSize: &wrappers.Int64Value{Value: csi_util.MinimumVolumeSizeInBytes},
+	}
+	// A volume can't span availability domains, so the largest volume that
+	// can be created is only known for a single one.
+	if len(availabilityDomains) == 1 {
+		maximumVolumeSize := availableCapacity
+		if maximumVolumeSize > csi_util.MaximumVolumeSizeInBytes {
+			maximumVolumeSize = csi_util.MaximumVolumeSizeInBytes
+		}
+		resp.MaximumVolumeSize = &wrappers.Int64Value{Value: maximumVolumeSize}
+	}
+	return resp, nil
+}
+
+// getAvailableCapacity returns the block volume storage left in the
+// availability domain, which is none once the volume count limit is reached.
+func (d *ControllerDriver) getAvailableCapacity(ctx context.Context, availabilityDomain string) (int64, error) {
+	volumeCount, err := d.client.Limits().GetResourceAvailability(ctx, client.BlockStorageServiceName, client.VolumeCountLimit,
+		d.config.CompartmentID, availabilityDomain)
+	if err != nil {
+		return 0, err
+	}
+	if volumeCount.Available != nil && *volumeCount.Available <= 0 {
+		return 0, nil
+	}
+
+	storage, err := d.client.Limits().GetResourceAvailability(ctx, client.BlockStorageServiceName, client.TotalStorageGBLimit,
+		d.config.CompartmentID, availabilityDomain)
+	if err != nil {
+		return 0, err
+	}
+	if storage.Available == nil || *storage.Available <= 0 {
+		return 0, nil
+	}
+	return *storage.Available * client.GiB, nil
 }
 
 // ControllerGetCapabilities returns the capabilities of the controller service.
@@ -672,6 +1078,14 @@ func (d *ControllerDriver) ControllerGetCapabilities(ctx context.Context, req *c
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
 	} {
 		caps = append(caps, newCap(cap))
 	}
@@ -686,11 +1100,9 @@ func (d *ControllerDriver) ControllerGetCapabilities(ctx context.Context, req *c
 // validateCapabilities validates the requested capabilities. It returns false
 // if it doesn't satisfy the currently supported modes of OCI Block Volume
 func (d *ControllerDriver) validateCapabilities(caps []*csi.VolumeCapability) bool {
-	vcaps := []*csi.VolumeCapability_AccessMode{supportedAccessMode}
-
 	hasSupport := func(mode csi.VolumeCapability_AccessMode_Mode) bool {
-		for _, m := range vcaps {
-			if mode == m.Mode {
+		for _, m := range supportedAccessModes {
+			if mode == m {
 				return true
 			}
 		}
@@ -708,25 +1120,286 @@ func (d *ControllerDriver) validateCapabilities(caps []*csi.VolumeCapability) bo
 			supported = false
 			break
 		}
+		if cap.AccessMode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER && cap.GetBlock() == nil {
+			d.logger.Errorf("The VolumeCapability isn't supported: %s is only supported for raw block volumes", cap.AccessMode)
+			supported = false
+			break
+		}
 	}
 
 	return supported
 }
 
+// isMultiNodeAccessMode returns true if the access mode requires the volume to
+// be attached to several nodes at once, i.e. a shareable attachment.
+func isMultiNodeAccessMode(mode csi.VolumeCapability_AccessMode_Mode) bool {
+	return mode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY ||
+		mode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+}
+
 // CreateSnapshot will be called by the CO to create a new snapshot from a
-// source volume on behalf of a user.
+// source volume on behalf of a user. Snapshots are backed by OCI block volume
+// backups; the backup display name is the snapshot name, which keeps the call
+// idempotent.
 func (d *ControllerDriver) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "CreateSnapshot is not supported yet")
+	startTime := time.Now()
+	log := d.logger.With("snapshotName", req.Name, "sourceVolumeId", req.SourceVolumeId)
+	var errorType string
+	var csiMetricDimension string
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "CreateSnapshot name must be provided")
+	}
+
+	if req.SourceVolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "CreateSnapshot source volume ID must be provided")
+	}
+
+	dimensionsMap := make(map[string]string)
+	dimensionsMap[metrics.ResourceOCIDDimension] = req.Name
+
+	backupType, err := extractSnapshotParameters(req.GetParameters())
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to parse volumesnapshotclass parameters.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	//make sure this method is idempotent by checking existence of backup with same name.
+	backups, err := d.client.BlockStorage().GetVolumeBackupsByName(ctx, req.Name, d.config.CompartmentID)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to check existence of volume backup.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to check existence of volume backup %v", err)
+	}
+
+	if len(backups) > 1 {
+		log.Error("Duplicate volume backups exist.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "duplicate volume backups with name %q exist", req.Name)
+	}
+
+	var backup *core.VolumeBackup
+	if len(backups) == 1 {
+		backup = &backups[0]
+		if backup.VolumeId == nil || *backup.VolumeId != req.SourceVolumeId {
+			log.With("backupId", *backup.Id).Error("Volume backup already exists for another volume.")
+			csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, status.Errorf(codes.AlreadyExists, "volume backup %q already exists for a different source volume", req.Name)
+		}
+		log.With("backupId", *backup.Id).Info("Volume backup already created!")
+	} else {
+		if _, err = d.client.BlockStorage().GetVolume(ctx, req.SourceVolumeId); err != nil {
+			log.With(zap.Error(err)).Error("Failed to get source volume.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+			if client.IsNotFound(err) {
+				return nil, status.Errorf(codes.NotFound, "source volume %s not found", req.SourceVolumeId)
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get source volume %v", err)
+		}
+
+		details := core.CreateVolumeBackupDetails{
+			VolumeId:    &req.SourceVolumeId,
+			DisplayName: &req.Name,
+			Type:        backupType,
+		}
+		if d.config.Tags != nil && d.config.Tags.BlockVolume != nil {
+			details.FreeformTags = d.config.Tags.BlockVolume.FreeformTags
+			details.DefinedTags = d.config.Tags.BlockVolume.DefinedTags
+		}
+
+		backup, err = d.client.BlockStorage().CreateVolumeBackup(ctx, details)
+		if err != nil {
+			log.With(zap.Error(err)).Error("Failed to create volume backup.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, status.Errorf(codes.Internal, "failed to create volume backup %v", err)
+		}
+		log.With("backupId", *backup.Id).Info("Volume backup is being created.")
+	}
+
+	if backup.LifecycleState == core.VolumeBackupLifecycleStateFaulty {
+		log.With("backupId", *backup.Id).Error("Volume backup is FAULTY.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		dimensionsMap[metrics.ResourceOCIDDimension] = *backup.Id
+		metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "volume backup %s is in lifecycle state %s", *backup.Id, backup.LifecycleState)
+	}
+
+	snapshot, err := volumeBackupToSnapshot(backup)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to convert volume backup to snapshot.")
+		return nil, status.Errorf(codes.Internal, "failed to convert volume backup to snapshot %v", err)
+	}
+
+	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+	dimensionsMap[metrics.ResourceOCIDDimension] = *backup.Id
+	metrics.SendMetricData(d.metricPusher, metrics.BackupCreate, time.Since(startTime).Seconds(), dimensionsMap)
+
+	return &csi.CreateSnapshotResponse{Snapshot: snapshot}, nil
 }
 
 // DeleteSnapshot will be called by the CO to delete a snapshot.
 func (d *ControllerDriver) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "DeleteSnapshot is not supported yet")
+	startTime := time.Now()
+	log := d.logger.With("snapshotId", req.SnapshotId)
+	var errorType string
+	var csiMetricDimension string
+
+	if req.SnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "DeleteSnapshot snapshot ID must be provided")
+	}
+
+	dimensionsMap := make(map[string]string)
+	dimensionsMap[metrics.ResourceOCIDDimension] = req.SnapshotId
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := d.client.BlockStorage().DeleteVolumeBackup(ctx, req.SnapshotId)
+	if err != nil && !client.IsNotFound(err) {
+		log.With(zap.Error(err)).Error("Failed to delete volume backup.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.BackupDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to delete volume backup, snapshotId: %s, error: %v", req.SnapshotId, err)
+	}
+
+	log.Info("Volume backup is deleted.")
+	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+	metrics.SendMetricData(d.metricPusher, metrics.BackupDelete, time.Since(startTime).Seconds(), dimensionsMap)
+	return &csi.DeleteSnapshotResponse{}, nil
 }
 
 // ListSnapshots returns all the matched snapshots
 func (d *ControllerDriver) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ListSnapshots is not supported yet")
+	log := d.logger.With("snapshotId", req.SnapshotId, "sourceVolumeId", req.SourceVolumeId)
+
+	if req.MaxEntries < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ListSnapshots max entries must not be negative: %d", req.MaxEntries)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if req.SnapshotId != "" {
+		backup, err := d.client.BlockStorage().GetVolumeBackup(ctx, req.SnapshotId)
+		if err != nil {
+			if client.IsNotFound(err) {
+				return &csi.ListSnapshotsResponse{}, nil
+			}
+			log.With(zap.Error(err)).Error("Failed to get volume backup.")
+			return nil, status.Errorf(codes.Internal, "failed to get volume backup %v", err)
+		}
+		if client.IsVolumeBackupTerminated(backup) ||
+			(req.SourceVolumeId != "" && (backup.VolumeId == nil || *backup.VolumeId != req.SourceVolumeId)) {
+			return &csi.ListSnapshotsResponse{}, nil
+		}
+		snapshot, err := volumeBackupToSnapshot(backup)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert volume backup to snapshot %v", err)
+		}
+		return &csi.ListSnapshotsResponse{
+			Entries: []*csi.ListSnapshotsResponse_Entry{{Snapshot: snapshot}},
+		}, nil
+	}
+
+	var page *string
+	if req.StartingToken != "" {
+		page = &req.StartingToken
+	}
+
+	backups, nextPage, err := d.client.BlockStorage().ListVolumeBackups(ctx, d.config.CompartmentID, req.SourceVolumeId, int(req.MaxEntries), page)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to list volume backups.")
+		if page != nil && client.IsBadRequest(err) {
+			return nil, status.Errorf(codes.Aborted, "invalid starting token %q: %v", req.StartingToken, err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list volume backups %v", err)
+	}
+
+	entries := make([]*csi.ListSnapshotsResponse_Entry, 0, len(backups))
+	for i := range backups {
+		// Only backups of volumes are snapshots; copies of other backups have no source volume.
+		if client.IsVolumeBackupTerminated(&backups[i]) || backups[i].VolumeId == nil {
+			continue
+		}
+		snapshot, err := volumeBackupToSnapshot(&backups[i])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert volume backup to snapshot %v", err)
+		}
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{Snapshot: snapshot})
+	}
+
+	resp := &csi.ListSnapshotsResponse{Entries: entries}
+	if nextPage != nil {
+		resp.NextToken = *nextPage
+	}
+	return resp, nil
+}
+
+// extractSnapshotParameters parses the parameters of a VolumeSnapshotClass.
+func extractSnapshotParameters(parameters map[string]string) (core.CreateVolumeBackupDetailsTypeEnum, error) {
+	backupTypeParameter := core.CreateVolumeBackupDetailsTypeIncremental
+	for k, v := range parameters {
+		switch k {
+		case backupType:
+			switch strings.ToLower(v) {
+			case "full":
+				backupTypeParameter = core.CreateVolumeBackupDetailsTypeFull
+			case "incremental":
+				backupTypeParameter = core.CreateVolumeBackupDetailsTypeIncremental
+			default:
+				return backupTypeParameter, status.Errorf(codes.InvalidArgument, "invalid backupType: %s provided "+
+					"for volumesnapshotclass. supported backupTypes are full and incremental", v)
+			}
+		}
+	}
+	return backupTypeParameter, nil
+}
+
+// volumeBackupToSnapshot maps an OCI volume backup to a CSI snapshot. A
+// snapshot is ready to use once the backup reaches the AVAILABLE state.
+func volumeBackupToSnapshot(backup *core.VolumeBackup) (*csi.Snapshot, error) {
+	snapshot := &csi.Snapshot{
+		SnapshotId: *backup.Id,
+		ReadyToUse: backup.LifecycleState == core.VolumeBackupLifecycleStateAvailable,
+	}
+	if backup.VolumeId != nil {
+		snapshot.SourceVolumeId = *backup.VolumeId
+	}
+	if backup.SizeInGBs != nil {
+		snapshot.SizeBytes = *backup.SizeInGBs * client.GiB
+	}
+	if backup.TimeCreated != nil {
+		creationTime, err := ptypes.TimestampProto(backup.TimeCreated.Time)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.CreationTime = creationTime
+	}
+	return snapshot, nil
 }
 
 // ControllerExpandVolume returns ControllerExpandVolume request
@@ -804,13 +1477,206 @@ func (d *ControllerDriver) ControllerExpandVolume(ctx context.Context, req *csi.
 	}, nil
 }
 
-// ControllerGetVolume returns ControllerGetVolumeResponse response
+// ControllerGetVolume returns the volume along with the nodes it is published
+// to. A volume that is FAULTY, deleted outside of Kubernetes or detached from a
+// node it should be attached to is reported with an abnormal condition.
 func (d *ControllerDriver) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ControllerGetVolume is not supported yet")
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "ControllerGetVolume Volume ID must be provided")
+	}
+
+	log := d.logger.With("volumeID", req.VolumeId)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	volume, err := d.client.BlockStorage().GetVolume(ctx, req.VolumeId)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "volume %s not found", req.VolumeId)
+		}
+		log.With(zap.Error(err)).Error("Failed to get volume.")
+		return nil, status.Errorf(codes.Internal, "failed to get volume %v", err)
+	}
+
+	publishedNodeIDs, err := d.getPublishedNodeIDs(ctx, req.VolumeId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the nodes the volume is published to.")
+		return nil, status.Errorf(codes.Internal, "failed to get published nodes %v", err)
+	}
+
+	volumeHandles, err := d.getVolumeHandles(ctx)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the persistent volumes of the driver.")
+		return nil, status.Errorf(codes.Internal, "failed to list persistent volumes %v", err)
+	}
+
+	expectedNodeIDs, err := d.getExpectedNodeIDs(ctx, volumeHandles)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the nodes the volume is expected to be attached to.")
+		return nil, status.Errorf(codes.Internal, "failed to get volume attachments %v", err)
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: d.volumeToCSIVolume(volume),
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: publishedNodeIDs[req.VolumeId],
+			VolumeCondition:  getVolumeCondition(volume, publishedNodeIDs[req.VolumeId], expectedNodeIDs[req.VolumeId]),
+		},
+	}, nil
+}
+
+// volumeToCSIVolume maps an OCI block volume to a CSI volume.
+func (d *ControllerDriver) volumeToCSIVolume(volume *core.Volume) *csi.Volume {
+	csiVolume := &csi.Volume{
+		VolumeId: *volume.Id,
+	}
+	if volume.SizeInGBs != nil {
+		csiVolume.CapacityBytes = *volume.SizeInGBs * client.GiB
+	}
+	if volume.AvailabilityDomain != nil {
+		csiVolume.AccessibleTopology = []*csi.Topology{
+			newBlockVolumeTopology(d.util.GetAvailableDomainInNodeLabel(*volume.AvailabilityDomain)),
+		}
+	}
+	return csiVolume
+}
+
+// getVolumeCondition returns the condition of the volume given the nodes it is
+// attached to in OCI and the nodes Kubernetes expects it to be attached to.
+func getVolumeCondition(volume *core.Volume, publishedNodeIDs, expectedNodeIDs []string) *csi.VolumeCondition {
+	switch {
+	case volume.LifecycleState == core.VolumeLifecycleStateFaulty:
+		return &csi.VolumeCondition{Abnormal: true, Message: fmt.Sprintf("volume %s is FAULTY", *volume.Id)}
+	case client.IsVolumeTerminated(volume):
+		return &csi.VolumeCondition{Abnormal: true, Message: fmt.Sprintf("volume %s has been deleted", *volume.Id)}
+	}
+
+	for _, expected := range expectedNodeIDs {
+		attached := false
+		for _, published := range publishedNodeIDs {
+			if expected == published {
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("volume %s is not attached to node %s", *volume.Id, expected),
+			}
+		}
+	}
+
+	return &csi.VolumeCondition{Message: fmt.Sprintf("volume %s is %s", *volume.Id, volume.LifecycleState)}
+}
+
+// getPublishedNodeIDs returns the names of the cluster nodes the volumes are
+// attached to in OCI, keyed by volume OCID. Attachments are looked up in the
+// compartments of the nodes, restricted to a single volume if volumeID is not
+// empty.
+func (d *ControllerDriver) getPublishedNodeIDs(ctx context.Context, volumeID string) (map[string][]string, error) {
+	instanceNodes, compartmentIDs, err := d.getClusterNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	publishedNodeIDs := make(map[string][]string)
+	for _, compartmentID := range compartmentIDs {
+		attachments, err := d.client.Compute().ListVolumeAttachments(ctx, compartmentID, volumeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, attachment := range attachments {
+			if attachment.GetInstanceId() == nil || attachment.GetVolumeId() == nil {
+				continue
+			}
+			// Attachments to instances outside of the cluster have no CSI node ID.
+			nodeName, ok := instanceNodes[*attachment.GetInstanceId()]
+			if !ok {
+				continue
+			}
+			publishedNodeIDs[*attachment.GetVolumeId()] = append(publishedNodeIDs[*attachment.GetVolumeId()], nodeName)
+		}
+	}
+	return publishedNodeIDs, nil
+}
+
+// getClusterNodes returns the names of the cluster nodes keyed by instance
+// OCID, and the compartments of the cluster, in which the volume attachments of
+// the nodes are.
+func (d *ControllerDriver) getClusterNodes(ctx context.Context) (map[string]string, []string, error) {
+	nodes, err := d.KubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	compartmentIDs := []string{d.config.CompartmentID}
+	instanceNodes := make(map[string]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		if node.Spec.ProviderID == "" {
+			continue
+		}
+		instanceNodes[client.MapProviderIDToInstanceID(node.Spec.ProviderID)] = node.Name
+
+		compartmentID := node.Annotations[util.CompartmentIDAnnotation]
+		if compartmentID == "" {
+			continue
+		}
+		known := false
+		for _, c := range compartmentIDs {
+			if c == compartmentID {
+				known = true
+				break
+			}
+		}
+		if !known {
+			compartmentIDs = append(compartmentIDs, compartmentID)
+		}
+	}
+	return instanceNodes, compartmentIDs, nil
+}
+
+// getVolumeHandles returns the volume OCIDs of the persistent volumes of this
+// driver, keyed by persistent volume name.
+func (d *ControllerDriver) getVolumeHandles(ctx context.Context) (map[string]string, error) {
+	pvs, err := d.KubeClient.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	volumeHandles := make(map[string]string, len(pvs.Items))
+	for _, pv := range pvs.Items {
+		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == BlockVolumeDriverName {
+			volumeHandles[pv.Name] = pv.Spec.CSI.VolumeHandle
+		}
+	}
+	return volumeHandles, nil
+}
+
+// getExpectedNodeIDs returns the names of the nodes Kubernetes has attached
+// the volumes of the given persistent volumes to, keyed by volume OCID.
+func (d *ControllerDriver) getExpectedNodeIDs(ctx context.Context, volumeHandles map[string]string) (map[string][]string, error) {
+	volumeAttachments, err := d.KubeClient.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expectedNodeIDs := make(map[string][]string)
+	for _, va := range volumeAttachments.Items {
+		if va.Spec.Attacher != BlockVolumeDriverName || !va.Status.Attached || va.Spec.Source.PersistentVolumeName == nil {
+			continue
+		}
+		volumeID, ok := volumeHandles[*va.Spec.Source.PersistentVolumeName]
+		if !ok {
+			continue
+		}
+		expectedNodeIDs[volumeID] = append(expectedNodeIDs[volumeID], va.Spec.NodeName)
+	}
+	return expectedNodeIDs, nil
 }
 
-func provision(log *zap.SugaredLogger, c client.Interface, volName string, volSize int64, availDomainName, compartmentID,
-	backupID, kmsKeyID string, vpusPerGB int64, timeout time.Duration, bvTags *config.TagConfig) (core.Volume, error) {
+func provision(log *zap.SugaredLogger, c client.Interface, volName string, volSize int64, availDomainName, compartmentID string,
+	volumeSourceDetails core.VolumeSourceDetails, kmsKeyID string, vpusPerGB int64, timeout time.Duration, bvTags *config.TagConfig) (core.Volume, error) {
 
 	ctx := context.Background()
 
@@ -828,8 +1694,8 @@ func provision(log *zap.SugaredLogger, c client.Interface, volName string, volSi
 		VpusPerGB:          &vpusPerGB,
 	}
 
-	if backupID != "" {
-		volumeDetails.SourceDetails = &core.VolumeSourceFromVolumeBackupDetails{Id: &backupID}
+	if volumeSourceDetails != nil {
+		volumeDetails.SourceDetails = volumeSourceDetails
 	}
 
 	if kmsKeyID != "" {
@@ -3,22 +3,33 @@ package driver
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	kubeAPI "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	storagev1client "k8s.io/client-go/kubernetes/typed/storage/v1"
 
 	providercfg "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
 	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
 	"github.com/oracle/oci-go-sdk/v50/common"
 	"github.com/oracle/oci-go-sdk/v50/core"
 	"github.com/oracle/oci-go-sdk/v50/filestorage"
 	"github.com/oracle/oci-go-sdk/v50/identity"
+	"github.com/oracle/oci-go-sdk/v50/limits"
 	"github.com/oracle/oci-go-sdk/v50/loadbalancer"
 )
 
@@ -27,9 +38,22 @@ const (
 )
 
 var (
+	testSnapshotCreationTime    = time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
 	inTransitEncryptionEnabled  = true
 	inTransitEncryptionDisabled = false
-	instances                   = map[string]*core.Instance{
+	singleNodeWriterCapability  = &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		},
+	}
+	ad1TopologyRequirement = &csi.TopologyRequirement{
+		Preferred: []*csi.Topology{
+			{
+				Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1"},
+			},
+		},
+	}
+	instances = map[string]*core.Instance{
 		"inTransitEnabled": {
 			LaunchOptions: &core.LaunchOptions{
 				IsPvEncryptionInTransitEnabled: &inTransitEncryptionEnabled,
@@ -40,6 +64,196 @@ var (
 				IsPvEncryptionInTransitEnabled: &inTransitEncryptionDisabled,
 			},
 		},
+		"terminated_instance_id": {
+			Id:             common.String("terminated_instance_id"),
+			LifecycleState: core.InstanceLifecycleStateTerminated,
+		},
+		"deleted_node_instance_id": {
+			Id:             common.String("deleted_node_instance_id"),
+			CompartmentId:  common.String("compartment_id"),
+			DisplayName:    common.String("deleted-node"),
+			LifecycleState: core.InstanceLifecycleStateRunning,
+		},
+		"renamed_node_instance_id": {
+			Id:             common.String("renamed_node_instance_id"),
+			CompartmentId:  common.String("compartment_id"),
+			DisplayName:    common.String("renamed-instance"),
+			LifecycleState: core.InstanceLifecycleStateRunning,
+		},
+		"external_instance_id": {
+			Id:             common.String("external_instance_id"),
+			CompartmentId:  common.String("compartment_id"),
+			DisplayName:    common.String("external-instance"),
+			LifecycleState: core.InstanceLifecycleStateRunning,
+		},
+	}
+	vnics = map[string]*core.Vnic{
+		"renamed_node_instance_id": {
+			HostnameLabel: common.String("renamed-node"),
+			PrivateIp:     common.String("10.0.10.5"),
+		},
+		"external_instance_id": {
+			HostnameLabel: common.String("external-instance"),
+			PrivateIp:     common.String("10.0.10.6"),
+		},
+	}
+	mountTargets = map[string]*filestorage.MountTarget{
+		"mount_target_id": {
+			Id:                 common.String("mount_target_id"),
+			AvailabilityDomain: common.String("US-ASHBURN-AD-1"),
+			PrivateIpIds:       []string{"private_ip_id"},
+			ExportSetId:        common.String("export_set_id"),
+		},
+		"mount_target_without_ip_id": {
+			Id:                 common.String("mount_target_without_ip_id"),
+			AvailabilityDomain: common.String("US-ASHBURN-AD-1"),
+			ExportSetId:        common.String("export_set_id"),
+		},
+	}
+	fileSystems = map[string]*filestorage.FileSystem{
+		"fs_id": {
+			Id:             common.String("fs_id"),
+			CompartmentId:  common.String("compartment_id"),
+			LifecycleState: filestorage.FileSystemLifecycleStateActive,
+		},
+		"deleted_fs_id": {
+			Id:             common.String("deleted_fs_id"),
+			CompartmentId:  common.String("compartment_id"),
+			LifecycleState: filestorage.FileSystemLifecycleStateDeleted,
+		},
+		"undeletable_fs_id": {
+			Id:             common.String("undeletable_fs_id"),
+			CompartmentId:  common.String("compartment_id"),
+			LifecycleState: filestorage.FileSystemLifecycleStateActive,
+		},
+		"shared_fs_id": {
+			Id:                 common.String("shared_fs_id"),
+			CompartmentId:      common.String("compartment_id"),
+			AvailabilityDomain: common.String("US-ASHBURN-AD-1"),
+			LifecycleState:     filestorage.FileSystemLifecycleStateActive,
+		},
+		"other_ad_fs_id": {
+			Id:                 common.String("other_ad_fs_id"),
+			CompartmentId:      common.String("compartment_id"),
+			AvailabilityDomain: common.String("US-ASHBURN-AD-2"),
+			LifecycleState:     filestorage.FileSystemLifecycleStateActive,
+		},
+	}
+	volumes = map[string]*core.Volume{
+		"attached_volume_id": {
+			Id:                 common.String("attached_volume_id"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			VpusPerGB:          common.Int64(10),
+			LifecycleState:     core.VolumeLifecycleStateAvailable,
+		},
+		"detached_volume_id": {
+			Id:                 common.String("detached_volume_id"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			LifecycleState:     core.VolumeLifecycleStateAvailable,
+		},
+		"faulty_volume_id": {
+			Id:                 common.String("faulty_volume_id"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			LifecycleState:     core.VolumeLifecycleStateFaulty,
+		},
+		"terminated_volume_id": {
+			Id:                 common.String("terminated_volume_id"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			LifecycleState:     core.VolumeLifecycleStateTerminated,
+		},
+		"unmanaged_volume_id": {
+			Id:                 common.String("unmanaged_volume_id"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			LifecycleState:     core.VolumeLifecycleStateAvailable,
+		},
+		"chap_volume_id": {
+			Id:                 common.String("chap_volume_id"),
+			DisplayName:        common.String("chap-pv"),
+			AvailabilityDomain: common.String("zkJl:US-ASHBURN-AD-1"),
+			SizeInGBs:          common.Int64(50),
+			LifecycleState:     core.VolumeLifecycleStateAvailable,
+		},
+	}
+	volumeAttachments = []core.VolumeAttachment{
+		core.IScsiVolumeAttachment{
+			Id:             common.String("attached_attachment_id"),
+			InstanceId:     common.String("node1_instance_id"),
+			VolumeId:       common.String("attached_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("detached_attachment_id"),
+			InstanceId:     common.String("non_cluster_instance_id"),
+			VolumeId:       common.String("detached_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("terminated_instance_attachment_id"),
+			InstanceId:     common.String("terminated_instance_id"),
+			VolumeId:       common.String("terminated_instance_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("deleted_node_attachment_id"),
+			InstanceId:     common.String("deleted_node_instance_id"),
+			VolumeId:       common.String("deleted_node_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("renamed_node_attachment_id"),
+			InstanceId:     common.String("renamed_node_instance_id"),
+			VolumeId:       common.String("renamed_node_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("shared_node1_attachment_id"),
+			InstanceId:     common.String("node1_instance_id"),
+			VolumeId:       common.String("shared_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+		core.IScsiVolumeAttachment{
+			Id:             common.String("shared_external_attachment_id"),
+			InstanceId:     common.String("external_instance_id"),
+			VolumeId:       common.String("shared_volume_id"),
+			LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+		},
+	}
+	volumeBackups = map[string]*core.VolumeBackup{
+		"available_backup_id": {
+			Id:             common.String("available_backup_id"),
+			DisplayName:    common.String("existing-snapshot"),
+			VolumeId:       common.String("valid_volume_id"),
+			SizeInGBs:      common.Int64(50),
+			LifecycleState: core.VolumeBackupLifecycleStateAvailable,
+			TimeCreated:    &common.SDKTime{Time: testSnapshotCreationTime},
+		},
+		"faulty_backup_id": {
+			Id:             common.String("faulty_backup_id"),
+			DisplayName:    common.String("faulty-snapshot"),
+			VolumeId:       common.String("valid_volume_id"),
+			LifecycleState: core.VolumeBackupLifecycleStateFaulty,
+			TimeCreated:    &common.SDKTime{Time: testSnapshotCreationTime},
+		},
+		"large_backup_id": {
+			Id:             common.String("large_backup_id"),
+			DisplayName:    common.String("large-snapshot"),
+			VolumeId:       common.String("valid_volume_id"),
+			SizeInGBs:      common.Int64(100),
+			LifecycleState: core.VolumeBackupLifecycleStateAvailable,
+			TimeCreated:    &common.SDKTime{Time: testSnapshotCreationTime},
+		},
+		"terminated_backup_id": {
+			Id:             common.String("terminated_backup_id"),
+			DisplayName:    common.String("terminated-snapshot"),
+			VolumeId:       common.String("valid_volume_id"),
+			LifecycleState: core.VolumeBackupLifecycleStateTerminated,
+			TimeCreated:    &common.SDKTime{Time: testSnapshotCreationTime},
+		},
 	}
 )
 
@@ -69,6 +283,10 @@ func (MockOCIClient) Identity() client.IdentityInterface {
 	return &MockIdentityClient{}
 }
 
+func (MockOCIClient) Limits() client.LimitsInterface {
+	return &MockLimitsClient{}
+}
+
 type MockBlockStorageClient struct {
 }
 
@@ -81,6 +299,9 @@ func (c *MockBlockStorageClient) AwaitVolumeAvailableORTimeout(ctx context.Conte
 }
 
 func (c *MockBlockStorageClient) GetVolume(ctx context.Context, id string) (*core.Volume, error) {
+	if volume, ok := volumes[id]; ok {
+		return volume, nil
+	}
 	if id == "invalid_volume_id" {
 		return nil, fmt.Errorf("failed to find existence of volume")
 	} else if id == "valid_volume_id" {
@@ -92,6 +313,15 @@ func (c *MockBlockStorageClient) GetVolume(ctx context.Context, id string) (*cor
 			AvailabilityDomain: &ad,
 			SizeInGBs:          &oldSizeInGB,
 		}, nil
+	} else if id == "clone_source_volume_id" {
+		ad := "zkJl:US-ASHBURN-AD-1"
+		sizeInGB := int64(50)
+		return &core.Volume{
+			Id:                 &id,
+			AvailabilityDomain: &ad,
+			SizeInGBs:          &sizeInGB,
+			LifecycleState:     core.VolumeLifecycleStateAvailable,
+		}, nil
 	} else if id == "valid_volume_id_valid_old_size_fail" {
 		ad := "zkJl:US-ASHBURN-AD-1"
 		var oldSizeInBytes int64 = 2147483648
@@ -110,13 +340,46 @@ func (c *MockBlockStorageClient) GetVolumesByName(ctx context.Context, volumeNam
 	return []core.Volume{}, nil
 }
 
+func (c *MockBlockStorageClient) ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error) {
+	if page == nil {
+		nextPage := "page2"
+		return []core.Volume{*volumes["attached_volume_id"], *volumes["terminated_volume_id"]}, &nextPage, nil
+	}
+	if *page == "page2" {
+		return []core.Volume{*volumes["detached_volume_id"], *volumes["faulty_volume_id"], *volumes["unmanaged_volume_id"]}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("invalid page")
+}
+
 // CreateVolume mocks the BlockStorage CreateVolume implementation
+// outOfCapacityAD is an availability domain without block volume capacity.
+const outOfCapacityAD = "zkJl:US-ASHBURN-AD-3"
+
+// outOfCapacityError mocks the service error of a block volume limit that is
+// used up.
+type outOfCapacityError struct{}
+
+func (outOfCapacityError) Error() string           { return "volume limit exceeded" }
+func (outOfCapacityError) GetHTTPStatusCode() int  { return http.StatusBadRequest }
+func (outOfCapacityError) GetMessage() string      { return "volume limit exceeded" }
+func (outOfCapacityError) GetCode() string         { return client.HTTP400LimitExceededCode }
+func (outOfCapacityError) GetOpcRequestID() string { return "" }
+
 func (c *MockBlockStorageClient) CreateVolume(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error) {
+	if details.AvailabilityDomain != nil && *details.AvailabilityDomain == outOfCapacityAD {
+		return nil, outOfCapacityError{}
+	}
 	id := "oc1.volume1.xxxx"
 	ad := "zkJl:US-ASHBURN-AD-1"
+	var sizeInMBs int64
+	if details.SizeInGBs != nil {
+		sizeInMBs = *details.SizeInGBs * 1024
+	}
 	return &core.Volume{
 		Id:                 &id,
 		AvailabilityDomain: &ad,
+		SizeInMBs:          &sizeInMBs,
+		SourceDetails:      details.SourceDetails,
 	}, nil
 }
 
@@ -141,6 +404,51 @@ func (c *MockBlockStorageClient) AwaitVolumeAvailable(ctx context.Context, id st
 	return nil, nil
 }
 
+func (c *MockBlockStorageClient) CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error) {
+	id := "oc1.volumebackup1.xxxx"
+	return &core.VolumeBackup{
+		Id:             &id,
+		DisplayName:    details.DisplayName,
+		VolumeId:       details.VolumeId,
+		LifecycleState: core.VolumeBackupLifecycleStateCreating,
+		TimeCreated:    &common.SDKTime{Time: testSnapshotCreationTime},
+	}, nil
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error) {
+	if backup, ok := volumeBackups[id]; ok {
+		return backup, nil
+	}
+	return nil, fmt.Errorf("failed to get volume backup")
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error) {
+	switch snapshotName {
+	case "existing-snapshot":
+		return []core.VolumeBackup{*volumeBackups["available_backup_id"]}, nil
+	case "faulty-snapshot":
+		return []core.VolumeBackup{*volumeBackups["faulty_backup_id"]}, nil
+	case "duplicate-snapshot":
+		return []core.VolumeBackup{*volumeBackups["available_backup_id"], *volumeBackups["faulty_backup_id"]}, nil
+	}
+	return []core.VolumeBackup{}, nil
+}
+
+func (c *MockBlockStorageClient) ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error) {
+	if page == nil {
+		nextPage := "page2"
+		return []core.VolumeBackup{*volumeBackups["available_backup_id"], *volumeBackups["terminated_backup_id"]}, &nextPage, nil
+	}
+	return []core.VolumeBackup{*volumeBackups["faulty_backup_id"]}, nil, nil
+}
+
+func (c *MockBlockStorageClient) DeleteVolumeBackup(ctx context.Context, id string) error {
+	if id == "invalid_backup_id" {
+		return fmt.Errorf("failed to delete volume backup")
+	}
+	return nil
+}
+
 // BlockStorage mocks client BlockStorage implementation
 func (p *MockProvisionerClient) BlockStorage() client.BlockStorageInterface {
 	return p.Storage
@@ -152,7 +460,8 @@ type MockVirtualNetworkClient struct {
 
 // GetPrivateIP mocks the VirtualNetwork GetPrivateIP implementation
 func (c *MockVirtualNetworkClient) GetPrivateIP(ctx context.Context, id string) (*core.PrivateIp, error) {
-	return nil, nil
+	ip := "10.0.10.1"
+	return &core.PrivateIp{Id: &id, IpAddress: &ip}, nil
 }
 
 func (c *MockVirtualNetworkClient) GetSubnet(ctx context.Context, id string) (*core.Subnet, error) {
@@ -214,6 +523,18 @@ func (c *MockLoadBalancerClient) CreateCertificate(ctx context.Context, lbID str
 	return "", nil
 }
 
+func (c *MockLoadBalancerClient) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
 func (c *MockLoadBalancerClient) CreateBackendSet(ctx context.Context, lbID string, name string, details *client.GenericBackendSetDetails) (string, error) {
 	return "", nil
 }
@@ -280,7 +601,7 @@ func (c *MockComputeClient) GetInstanceByNodeName(ctx context.Context, compartme
 }
 
 func (c *MockComputeClient) GetPrimaryVNICForInstance(ctx context.Context, compartmentID, instanceID string) (*core.Vnic, error) {
-	return nil, nil
+	return vnics[instanceID], nil
 }
 
 func (c *MockComputeClient) FindVolumeAttachment(ctx context.Context, compartmentID, volumeID string) (core.VolumeAttachment, error) {
@@ -291,11 +612,19 @@ func (c *MockComputeClient) FindActiveVolumeAttachment(ctx context.Context, comp
 	return nil, nil
 }
 
-func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error) {
+func (c *MockComputeClient) FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
-func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error) {
+func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
@@ -309,6 +638,114 @@ func (c *MockComputeClient) WaitForVolumeDetached(ctx context.Context, attachmen
 	return nil
 }
 
+func (c *MockComputeClient) ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error) {
+	var attachments []core.VolumeAttachment
+	for _, attachment := range volumeAttachments {
+		if volumeID == "" || *attachment.GetVolumeId() == volumeID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+// MockKubeClient serves the nodes, persistent volumes and volume attachments
+// looked up by the controller, node1 is expected to have the attached and
+// detached volumes attached and the deleted node the deleted node volume.
+type MockKubeClient struct {
+	kubernetes.Interface
+}
+
+type MockCoreV1 struct {
+	corev1client.CoreV1Interface
+}
+
+type MockNodes struct {
+	corev1client.NodeInterface
+}
+
+type MockPersistentVolumes struct {
+	corev1client.PersistentVolumeInterface
+}
+
+type MockStorageV1 struct {
+	storagev1client.StorageV1Interface
+}
+
+type MockVolumeAttachments struct {
+	storagev1client.VolumeAttachmentInterface
+}
+
+func (MockKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return MockCoreV1{}
+}
+
+func (MockKubeClient) StorageV1() storagev1client.StorageV1Interface {
+	return MockStorageV1{}
+}
+
+func (MockCoreV1) Nodes() corev1client.NodeInterface {
+	return MockNodes{}
+}
+
+func (MockCoreV1) PersistentVolumes() corev1client.PersistentVolumeInterface {
+	return MockPersistentVolumes{}
+}
+
+func (MockStorageV1) VolumeAttachments() storagev1client.VolumeAttachmentInterface {
+	return MockVolumeAttachments{}
+}
+
+func (MockNodes) List(ctx context.Context, opts metav1.ListOptions) (*kubeAPI.NodeList, error) {
+	return &kubeAPI.NodeList{Items: []kubeAPI.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Spec:       kubeAPI.NodeSpec{ProviderID: "oci://node1_instance_id"},
+		},
+	}}, nil
+}
+
+func (MockPersistentVolumes) List(ctx context.Context, opts metav1.ListOptions) (*kubeAPI.PersistentVolumeList, error) {
+	pv := func(name, volumeHandle string) kubeAPI.PersistentVolume {
+		return kubeAPI.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kubeAPI.PersistentVolumeSpec{
+				PersistentVolumeSource: kubeAPI.PersistentVolumeSource{
+					CSI: &kubeAPI.CSIPersistentVolumeSource{Driver: BlockVolumeDriverName, VolumeHandle: volumeHandle},
+				},
+			},
+		}
+	}
+	return &kubeAPI.PersistentVolumeList{Items: []kubeAPI.PersistentVolume{
+		pv("attached-pv", "attached_volume_id"),
+		pv("detached-pv", "detached_volume_id"),
+		pv("terminated-instance-pv", "terminated_instance_volume_id"),
+		pv("deleted-node-pv", "deleted_node_volume_id"),
+		pv("renamed-node-pv", "renamed_node_volume_id"),
+		pv("shared-pv", "shared_volume_id"),
+		pv("faulty-pv", "faulty_volume_id"),
+	}}, nil
+}
+
+func (MockVolumeAttachments) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.VolumeAttachmentList, error) {
+	va := func(pvName, nodeName string) storagev1.VolumeAttachment {
+		return storagev1.VolumeAttachment{
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: BlockVolumeDriverName,
+				NodeName: nodeName,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: common.String(pvName)},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: true},
+		}
+	}
+	return &storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{
+		va("attached-pv", "node1"),
+		va("detached-pv", "node1"),
+		va("deleted-node-pv", "deleted-node"),
+		va("renamed-node-pv", "renamed-node"),
+		va("shared-pv", "deleted-node"),
+	}}, nil
+}
+
 func (p *MockProvisionerClient) Compute() client.ComputeInterface {
 	return &MockComputeClient{}
 }
@@ -319,11 +756,14 @@ type MockIdentityClient struct {
 }
 
 func (client MockIdentityClient) ListAvailabilityDomains(ctx context.Context, compartmentID string) ([]identity.AvailabilityDomain, error) {
-	return nil, nil
+	return []identity.AvailabilityDomain{{Name: common.String("AD1")}, {Name: common.String("AD2")}}, nil
 }
 
 // ListAvailabilityDomains mocks the client ListAvailabilityDomains implementation
 func (client MockIdentityClient) GetAvailabilityDomainByName(ctx context.Context, compartmentID, name string) (*identity.AvailabilityDomain, error) {
+	if name == "US-ASHBURN-AD-3" {
+		return &identity.AvailabilityDomain{Name: common.String(outOfCapacityAD)}, nil
+	}
 	ad1 := "AD1"
 	return &identity.AvailabilityDomain{Name: &ad1}, nil
 }
@@ -333,34 +773,66 @@ func (p *MockProvisionerClient) Identity() client.IdentityInterface {
 	return &MockIdentityClient{}
 }
 
+// MockLimitsClient mocks the limits client, AD1 has 100 GB of block volume
+// storage left and AD2 has reached its volume count limit.
+type MockLimitsClient struct{}
+
+func (MockLimitsClient) GetResourceAvailability(ctx context.Context, serviceName, limitName, compartmentID, availabilityDomain string) (*limits.ResourceAvailability, error) {
+	available := map[string]map[string]int64{
+		"AD1": {client.VolumeCountLimit: 10, client.TotalStorageGBLimit: 100},
+		"AD2": {client.VolumeCountLimit: 0, client.TotalStorageGBLimit: 100},
+	}
+	if _, ok := available[availabilityDomain]; !ok {
+		return nil, fmt.Errorf("unknown availability domain %s", availabilityDomain)
+	}
+	return &limits.ResourceAvailability{Available: common.Int64(available[availabilityDomain][limitName])}, nil
+}
+
+// Limits mocks client Limits implementation
+func (p *MockProvisionerClient) Limits() client.LimitsInterface {
+	return &MockLimitsClient{}
+}
+
 type MockFileStorageClient struct{}
 
 // CreateFileSystem mocks the FileStorage CreateFileSystem implementation.
 func (c *MockFileStorageClient) CreateFileSystem(ctx context.Context, details filestorage.CreateFileSystemDetails) (*filestorage.FileSystem, error) {
-	return nil, nil
+	id := "fs_id"
+	return &filestorage.FileSystem{Id: &id, CompartmentId: details.CompartmentId}, nil
 }
 
 // GetFileSystem mocks the FileStorage GetFileSystem implementation.
 func (c *MockFileStorageClient) GetFileSystem(ctx context.Context, id string) (*filestorage.FileSystem, error) {
-	return nil, nil
+	if fileSystem, ok := fileSystems[id]; ok {
+		return fileSystem, nil
+	}
+	return nil, fmt.Errorf("failed to get file system %s", id)
 }
 
 func (c *MockFileStorageClient) AwaitFileSystemActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*filestorage.FileSystem, error) {
-	return nil, nil
+	return &filestorage.FileSystem{Id: &id, LifecycleState: filestorage.FileSystemLifecycleStateActive}, nil
 }
 
 func (c *MockFileStorageClient) GetFileSystemSummaryByDisplayName(ctx context.Context, compartmentID, ad, displayName string) (*filestorage.FileSystemSummary, error) {
+	if displayName == "existing-fs-volume" {
+		id := "existing_fs_id"
+		return &filestorage.FileSystemSummary{Id: &id}, nil
+	}
 	return nil, nil
 }
 
 // DeleteFileSystem mocks the FileStorage DeleteFileSystem implementation
 func (c *MockFileStorageClient) DeleteFileSystem(ctx context.Context, id string) error {
+	if id == "undeletable_fs_id" {
+		return fmt.Errorf("failed to delete file system %s", id)
+	}
 	return nil
 }
 
 // CreateExport mocks the FileStorage CreateExport implementation
 func (c *MockFileStorageClient) CreateExport(ctx context.Context, details filestorage.CreateExportDetails) (*filestorage.Export, error) {
-	return nil, nil
+	id := "export_id"
+	return &filestorage.Export{Id: &id, Path: details.Path}, nil
 }
 
 // GetExport mocks the FileStorage CreateExport implementation.
@@ -368,13 +840,22 @@ func (c *MockFileStorageClient) GetExport(ctx context.Context, request filestora
 	return filestorage.GetExportResponse{}, nil
 }
 func (c *MockFileStorageClient) AwaitExportActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*filestorage.Export, error) {
-	return nil, nil
+	path := "/export-path"
+	return &filestorage.Export{Id: &id, Path: &path, LifecycleState: filestorage.ExportLifecycleStateActive}, nil
 }
 
 func (c *MockFileStorageClient) FindExport(ctx context.Context, compartmentID, fsID, exportSetID string) (*filestorage.ExportSummary, error) {
+	if fsID == "shared_fs_id" {
+		return &filestorage.ExportSummary{Id: common.String("shared_export_id"), Path: common.String("/shared")}, nil
+	}
 	return nil, nil
 }
 
+func (c *MockFileStorageClient) ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]filestorage.ExportSummary, error) {
+	id := "export_id"
+	return []filestorage.ExportSummary{{Id: &id}}, nil
+}
+
 // DeleteExport mocks the FileStorage DeleteExport implementation
 func (c *MockFileStorageClient) DeleteExport(ctx context.Context, id string) error {
 	return nil
@@ -382,7 +863,10 @@ func (c *MockFileStorageClient) DeleteExport(ctx context.Context, id string) err
 
 // GetMountTarget mocks the FileStorage GetMountTarget implementation
 func (c *MockFileStorageClient) AwaitMountTargetActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*filestorage.MountTarget, error) {
-	return nil, nil
+	if mountTarget, ok := mountTargets[id]; ok {
+		return mountTarget, nil
+	}
+	return nil, fmt.Errorf("mount target %s not found", id)
 }
 
 // FSS mocks client FileStorage implementation
@@ -394,6 +878,22 @@ func NewClientProvisioner(pcData client.Interface, storage *MockBlockStorageClie
 	return &MockProvisionerClient{Storage: storage}
 }
 
+func snapshotContentSource(snapshotID string) *csi.VolumeContentSource {
+	return &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Snapshot{
+			Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: snapshotID},
+		},
+	}
+}
+
+func volumeContentSource(volumeID string) *csi.VolumeContentSource {
+	return &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Volume{
+			Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: volumeID},
+		},
+	}
+}
+
 func TestControllerDriver_CreateVolume(t *testing.T) {
 	type fields struct {
 		KubeClient kubernetes.Interface
@@ -454,15 +954,36 @@ func TestControllerDriver_CreateVolume(t *testing.T) {
 			wantErr: errors.New("VolumeCapabilities must be provided in CreateVolumeRequest"),
 		},
 		{
-			name:   "Error for unsupported VolumeCapabilities: MULTI_NODE_READER_ONLY provided in CreateVolumeRequest",
+			name:   "Error for unsupported fsType provided in CreateVolumeRequest",
+			fields: fields{},
+			args: args{
+				ctx: nil,
+				req: &csi.CreateVolumeRequest{
+					Name: "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{FsType: "ntfs"},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+						},
+					}},
+				},
+			},
+			want:    nil,
+			wantErr: errors.New(`fsType "ntfs" is not supported`),
+		},
+		{
+			name:   "Error for unsupported VolumeCapabilities: MULTI_NODE_MULTI_WRITER filesystem provided in CreateVolumeRequest",
 			fields: fields{},
 			args: args{
 				ctx: nil,
 				req: &csi.CreateVolumeRequest{
 					Name: "ut-volume",
 					VolumeCapabilities: []*csi.VolumeCapability{{
+						AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
 						AccessMode: &csi.VolumeCapability_AccessMode{
-							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
 						},
 					}},
 				},
@@ -535,94 +1056,366 @@ func TestControllerDriver_CreateVolume(t *testing.T) {
 			want:    nil,
 			wantErr: errors.New("required in PreferredTopologies or allowedTopologies"),
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := &ControllerDriver{
-				KubeClient: nil,
-				logger:     zap.S(),
-				config:     &providercfg.Config{CompartmentID: ""},
-				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
-				util:       &csi_util.Util{},
-			}
-			got, err := d.CreateVolume(tt.args.ctx, tt.args.req)
-			if tt.wantErr == nil && err != nil {
-				t.Errorf("got error %q, want none", err)
-			}
-			if tt.wantErr != nil && !strings.Contains(err.Error(), tt.wantErr.Error()) {
-				t.Errorf("want error %q to include %q", err, tt.wantErr)
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ControllerDriver.CreateVolume() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestControllerDriver_DeleteVolume(t *testing.T) {
-	type fields struct {
-		KubeClient kubernetes.Interface
-		logger     *zap.SugaredLogger
-		config     *providercfg.Config
-		client     client.Interface
-		util       *csi_util.Util
-	}
-	type args struct {
-		ctx context.Context
-		req *csi.DeleteVolumeRequest
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    *csi.DeleteVolumeResponse
-		wantErr error
-	}{
 		{
-			name:   "Error for volume OCID missing in delete block volume",
+			name:   "Error for restoring a snapshot that is not available",
 			fields: fields{},
 			args: args{
-				ctx: nil,
-				req: &csi.DeleteVolumeRequest{},
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:                      "ut-volume",
+					VolumeCapabilities:        []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: ad1TopologyRequirement,
+					VolumeContentSource:       snapshotContentSource("faulty_backup_id"),
+				},
 			},
 			want:    nil,
-			wantErr: errors.New("DeleteVolume Volume ID must be provided"),
+			wantErr: errors.New("it must be AVAILABLE to be restored"),
 		},
 		{
-			name:   "Delete volume and get empty response",
+			name:   "Error for restoring a snapshot into a smaller volume",
 			fields: fields{},
 			args: args{
 				ctx: context.Background(),
-				req: &csi.DeleteVolumeRequest{VolumeId: "oc1.volume1.xxxx"},
+				req: &csi.CreateVolumeRequest{
+					Name:                      "ut-volume",
+					VolumeCapabilities:        []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: ad1TopologyRequirement,
+					VolumeContentSource:       snapshotContentSource("large_backup_id"),
+				},
 			},
-			want:    &csi.DeleteVolumeResponse{},
-			wantErr: nil,
+			want:    nil,
+			wantErr: errors.New("is smaller than the size 100GB of volume backup"),
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := &ControllerDriver{
-				KubeClient: nil,
-				logger:     zap.S(),
-				config:     &providercfg.Config{CompartmentID: ""},
-				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
-				util:       &csi_util.Util{},
-			}
-			got, err := d.DeleteVolume(tt.args.ctx, tt.args.req)
-			if tt.wantErr == nil && err != nil {
-				t.Errorf("got error %q, want none", err)
-			}
-			if tt.wantErr != nil && !strings.Contains(err.Error(), tt.wantErr.Error()) {
-				t.Errorf("want error %q to include %q", err, tt.wantErr)
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ControllerDriver.CreateVolume() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestControllerDriver_ControllerExpandVolume(t *testing.T) {
+		{
+			name:   "Error for cloning a volume into another availability domain",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:               "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: &csi.TopologyRequirement{
+						Preferred: []*csi.Topology{
+							{
+								Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-2"},
+							},
+						},
+					},
+					VolumeContentSource: volumeContentSource("clone_source_volume_id"),
+				},
+			},
+			want:    nil,
+			wantErr: errors.New("cloning it to availability domain US-ASHBURN-AD-2 is not supported"),
+		},
+		{
+			name:   "Error for cloning a volume that is not available",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:                      "ut-volume",
+					VolumeCapabilities:        []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: ad1TopologyRequirement,
+					VolumeContentSource:       volumeContentSource("valid_volume_id"),
+				},
+			},
+			want:    nil,
+			wantErr: errors.New("it must be AVAILABLE to be cloned"),
+		},
+		{
+			name:   "Clone volume into the requisite availability domain of its source",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:               "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: &csi.TopologyRequirement{
+						Requisite: []*csi.Topology{
+							{
+								Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-2"},
+							},
+							{
+								Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1"},
+							},
+						},
+					},
+					VolumeContentSource: volumeContentSource("clone_source_volume_id"),
+				},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "oc1.volume1.xxxx",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					AccessibleTopology: []*csi.Topology{
+						{
+							Segments: map[string]string{
+								kubeAPI.LabelTopologyZone:      "US-ASHBURN-AD-1",
+								BlockVolumeTopologyKey:         "US-ASHBURN-AD-1",
+								kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1",
+							},
+						},
+					},
+					VolumeContext: map[string]string{
+						attachmentType:     "",
+						csi_util.VpusPerGB: "10",
+					},
+					ContentSource: volumeContentSource("clone_source_volume_id"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "Clone volume into the preferred availability domain of its source",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:               "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: &csi.TopologyRequirement{
+						Requisite: []*csi.Topology{
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-2"},
+							},
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-1"},
+							},
+						},
+						Preferred: []*csi.Topology{
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-2"},
+							},
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-1"},
+							},
+						},
+					},
+					VolumeContentSource: volumeContentSource("clone_source_volume_id"),
+				},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "oc1.volume1.xxxx",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					AccessibleTopology: []*csi.Topology{
+						{
+							Segments: map[string]string{
+								kubeAPI.LabelTopologyZone:      "US-ASHBURN-AD-1",
+								BlockVolumeTopologyKey:         "US-ASHBURN-AD-1",
+								kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1",
+							},
+						},
+					},
+					VolumeContext: map[string]string{
+						attachmentType:     "",
+						csi_util.VpusPerGB: "10",
+					},
+					ContentSource: volumeContentSource("clone_source_volume_id"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "Fall back to the next preferred availability domain with capacity",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:               "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: &csi.TopologyRequirement{
+						Preferred: []*csi.Topology{
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-3"},
+							},
+							{
+								Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-1"},
+							},
+						},
+					},
+				},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "oc1.volume1.xxxx",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					AccessibleTopology: []*csi.Topology{
+						{
+							Segments: map[string]string{
+								kubeAPI.LabelTopologyZone:      "US-ASHBURN-AD-1",
+								BlockVolumeTopologyKey:         "US-ASHBURN-AD-1",
+								kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1",
+							},
+						},
+					},
+					VolumeContext: map[string]string{
+						attachmentType:     "",
+						csi_util.VpusPerGB: "10",
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "Error for no availability domain with capacity",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:               "ut-volume",
+					VolumeCapabilities: []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: &csi.TopologyRequirement{
+						Requisite: []*csi.Topology{
+							{
+								Segments: map[string]string{BlockVolumeTopologyKey: "US-ASHBURN-AD-3"},
+							},
+						},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: errors.New("New volume creation failed volume limit exceeded"),
+		},
+		{
+			name:   "Restore volume from snapshot",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.CreateVolumeRequest{
+					Name:                      "ut-volume",
+					VolumeCapabilities:        []*csi.VolumeCapability{singleNodeWriterCapability},
+					AccessibilityRequirements: ad1TopologyRequirement,
+					VolumeContentSource:       snapshotContentSource("available_backup_id"),
+				},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "oc1.volume1.xxxx",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					AccessibleTopology: []*csi.Topology{
+						{
+							Segments: map[string]string{
+								kubeAPI.LabelTopologyZone:      "US-ASHBURN-AD-1",
+								BlockVolumeTopologyKey:         "US-ASHBURN-AD-1",
+								kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-1",
+							},
+						},
+					},
+					VolumeContext: map[string]string{
+						attachmentType:     "",
+						csi_util.VpusPerGB: "10",
+					},
+					ContentSource: snapshotContentSource("available_backup_id"),
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: ""},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{Logger: zap.S()},
+			}
+			got, err := d.CreateVolume(tt.args.ctx, tt.args.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControllerDriver.CreateVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_DeleteVolume(t *testing.T) {
+	type fields struct {
+		KubeClient kubernetes.Interface
+		logger     *zap.SugaredLogger
+		config     *providercfg.Config
+		client     client.Interface
+		util       *csi_util.Util
+	}
+	type args struct {
+		ctx context.Context
+		req *csi.DeleteVolumeRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *csi.DeleteVolumeResponse
+		wantErr error
+	}{
+		{
+			name:   "Error for volume OCID missing in delete block volume",
+			fields: fields{},
+			args: args{
+				ctx: nil,
+				req: &csi.DeleteVolumeRequest{},
+			},
+			want:    nil,
+			wantErr: errors.New("DeleteVolume Volume ID must be provided"),
+		},
+		{
+			name:   "Delete volume and get empty response",
+			fields: fields{},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.DeleteVolumeRequest{VolumeId: "oc1.volume1.xxxx"},
+			},
+			want:    &csi.DeleteVolumeResponse{},
+			wantErr: nil,
+		},
+		{
+			name: "Delete volume and its iSCSI CHAP secret",
+			fields: fields{
+				KubeClient: &mockSecretsKubeClient{secrets: map[string]*kubeAPI.Secret{
+					"chap-pv-iscsi-chap": chapSecret("chap-pv-iscsi-chap", "node1"),
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &csi.DeleteVolumeRequest{VolumeId: "chap_volume_id"},
+			},
+			want:    &csi.DeleteVolumeResponse{},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: tt.fields.KubeClient,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: ""},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{},
+			}
+			got, err := d.DeleteVolume(tt.args.ctx, tt.args.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControllerDriver.CreateVolume() = %v, want %v", got, tt.want)
+			}
+			if kubeClient, ok := tt.fields.KubeClient.(*mockSecretsKubeClient); ok && len(kubeClient.secrets) != 0 {
+				t.Errorf("ControllerDriver.DeleteVolume() left secrets %v behind", kubeClient.secrets)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_ControllerExpandVolume(t *testing.T) {
 	type fields struct {
 		KubeClient kubernetes.Interface
 		logger     *zap.SugaredLogger
@@ -996,45 +1789,123 @@ func TestExtractVolumeParameters(t *testing.T) {
 			},
 			wantErr: true,
 		},
-	}
-
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			volumeParameters, err := extractVolumeParameters(tt.storageParameters)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("extractVolumeParameters() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(volumeParameters, tt.volumeParameters) {
-				t.Errorf("extractStorage() = %+v, want %+v", volumeParameters, tt.volumeParameters)
-			}
-		})
-	}
-}
-
-func TestGetAttachmentOptions(t *testing.T) {
-	tests := map[string]struct {
-		attachmentType         string
-		instanceID             string
-		volumeAttachmentOption VolumeAttachmentOption
-		wantErr                bool
-	}{
-		"PV attachment with instance in-transit encryption enabled": {
-			attachmentType: attachmentTypeParavirtualized,
-			instanceID:     "inTransitEnabled",
-			volumeAttachmentOption: VolumeAttachmentOption{
-				enableInTransitEncryption:    true,
-				useParavirtualizedAttachment: true,
+		"if iscsi-chap is true then CHAP is used": {
+			storageParameters: map[string]string{
+				attachmentType: attachmentTypeISCSI,
+				iscsiChap:      "true",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey: "",
+				attachmentParameter: map[string]string{
+					attachmentType: attachmentTypeISCSI,
+				},
+				vpusPerGB: 10,
+				useChap:   true,
 			},
 			wantErr: false,
 		},
-
-		"PV attachment with instance in-transit encryption disabled": {
-			attachmentType: attachmentTypeParavirtualized,
-			instanceID:     "inTransitDisabled",
-			volumeAttachmentOption: VolumeAttachmentOption{
-				enableInTransitEncryption:    false,
-				useParavirtualizedAttachment: true,
+		"if invalid parameter for iscsi-chap then return error": {
+			storageParameters: map[string]string{
+				iscsiChap: "maybe",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey:   "",
+				attachmentParameter: make(map[string]string),
+				vpusPerGB:           10,
+			},
+			wantErr: true,
+		},
+		"if iscsi-chap is used with paravirtualized attachments then return error": {
+			storageParameters: map[string]string{
+				attachmentType: attachmentTypeParavirtualized,
+				iscsiChap:      "true",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey: "",
+				attachmentParameter: map[string]string{
+					attachmentType: attachmentTypeParavirtualized,
+				},
+				vpusPerGB: 10,
+				useChap:   true,
+			},
+			wantErr: true,
+		},
+		"if luks-encryption is true then the volume is encrypted on the nodes": {
+			storageParameters: map[string]string{
+				luksEncryption: "true",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey:   "",
+				attachmentParameter: make(map[string]string),
+				vpusPerGB:           10,
+				luksEncryption:      true,
+			},
+			wantErr: false,
+		},
+		"if invalid parameter for luks-encryption then return error": {
+			storageParameters: map[string]string{
+				luksEncryption: "maybe",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey:   "",
+				attachmentParameter: make(map[string]string),
+				vpusPerGB:           10,
+			},
+			wantErr: true,
+		},
+		"if luks-encryption is used with iscsi-chap then return error": {
+			storageParameters: map[string]string{
+				iscsiChap:      "true",
+				luksEncryption: "true",
+			},
+			volumeParameters: VolumeParameters{
+				diskEncryptionKey:   "",
+				attachmentParameter: make(map[string]string),
+				vpusPerGB:           10,
+				useChap:             true,
+				luksEncryption:      true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			volumeParameters, err := extractVolumeParameters(tt.storageParameters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("extractVolumeParameters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(volumeParameters, tt.volumeParameters) {
+				t.Errorf("extractStorage() = %+v, want %+v", volumeParameters, tt.volumeParameters)
+			}
+		})
+	}
+}
+
+func TestGetAttachmentOptions(t *testing.T) {
+	tests := map[string]struct {
+		attachmentType         string
+		instanceID             string
+		volumeAttachmentOption VolumeAttachmentOption
+		wantErr                bool
+	}{
+		"PV attachment with instance in-transit encryption enabled": {
+			attachmentType: attachmentTypeParavirtualized,
+			instanceID:     "inTransitEnabled",
+			volumeAttachmentOption: VolumeAttachmentOption{
+				enableInTransitEncryption:    true,
+				useParavirtualizedAttachment: true,
+			},
+			wantErr: false,
+		},
+
+		"PV attachment with instance in-transit encryption disabled": {
+			attachmentType: attachmentTypeParavirtualized,
+			instanceID:     "inTransitDisabled",
+			volumeAttachmentOption: VolumeAttachmentOption{
+				enableInTransitEncryption:    false,
+				useParavirtualizedAttachment: true,
 			},
 			wantErr: false,
 		},
@@ -1080,3 +1951,558 @@ func TestGetAttachmentOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerDriver_CreateSnapshot(t *testing.T) {
+	creationTime, _ := ptypes.TimestampProto(testSnapshotCreationTime)
+	tests := []struct {
+		name    string
+		req     *csi.CreateSnapshotRequest
+		want    *csi.CreateSnapshotResponse
+		wantErr error
+	}{
+		{
+			name:    "Error for snapshot name not provided",
+			req:     &csi.CreateSnapshotRequest{SourceVolumeId: "valid_volume_id"},
+			want:    nil,
+			wantErr: errors.New("CreateSnapshot name must be provided"),
+		},
+		{
+			name:    "Error for source volume not provided",
+			req:     &csi.CreateSnapshotRequest{Name: "new-snapshot"},
+			want:    nil,
+			wantErr: errors.New("CreateSnapshot source volume ID must be provided"),
+		},
+		{
+			name: "Error for invalid backup type",
+			req: &csi.CreateSnapshotRequest{
+				Name:           "new-snapshot",
+				SourceVolumeId: "valid_volume_id",
+				Parameters:     map[string]string{backupType: "differential"},
+			},
+			want:    nil,
+			wantErr: errors.New("invalid backupType"),
+		},
+		{
+			name:    "Error for duplicate volume backups",
+			req:     &csi.CreateSnapshotRequest{Name: "duplicate-snapshot", SourceVolumeId: "valid_volume_id"},
+			want:    nil,
+			wantErr: errors.New("duplicate volume backups"),
+		},
+		{
+			name:    "Error for existing volume backup of another volume",
+			req:     &csi.CreateSnapshotRequest{Name: "existing-snapshot", SourceVolumeId: "other_volume_id"},
+			want:    nil,
+			wantErr: errors.New("already exists for a different source volume"),
+		},
+		{
+			name:    "Error for faulty volume backup",
+			req:     &csi.CreateSnapshotRequest{Name: "faulty-snapshot", SourceVolumeId: "valid_volume_id"},
+			want:    nil,
+			wantErr: errors.New("is in lifecycle state FAULTY"),
+		},
+		{
+			name:    "Error for missing source volume",
+			req:     &csi.CreateSnapshotRequest{Name: "new-snapshot", SourceVolumeId: "invalid_volume_id"},
+			want:    nil,
+			wantErr: errors.New("failed to get source volume"),
+		},
+		{
+			name: "Existing volume backup is returned and ready to use",
+			req:  &csi.CreateSnapshotRequest{Name: "existing-snapshot", SourceVolumeId: "valid_volume_id"},
+			want: &csi.CreateSnapshotResponse{
+				Snapshot: &csi.Snapshot{
+					SnapshotId:     "available_backup_id",
+					SourceVolumeId: "valid_volume_id",
+					SizeBytes:      50 * client.GiB,
+					CreationTime:   creationTime,
+					ReadyToUse:     true,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "New volume backup is created and not yet ready to use",
+			req:  &csi.CreateSnapshotRequest{Name: "new-snapshot", SourceVolumeId: "valid_volume_id"},
+			want: &csi.CreateSnapshotResponse{
+				Snapshot: &csi.Snapshot{
+					SnapshotId:     "oc1.volumebackup1.xxxx",
+					SourceVolumeId: "valid_volume_id",
+					CreationTime:   creationTime,
+					ReadyToUse:     false,
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: ""},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{},
+			}
+			got, err := d.CreateSnapshot(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControllerDriver.CreateSnapshot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_DeleteSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *csi.DeleteSnapshotRequest
+		want    *csi.DeleteSnapshotResponse
+		wantErr error
+	}{
+		{
+			name:    "Error for snapshot ID not provided",
+			req:     &csi.DeleteSnapshotRequest{},
+			want:    nil,
+			wantErr: errors.New("DeleteSnapshot snapshot ID must be provided"),
+		},
+		{
+			name:    "Error for failed backup deletion",
+			req:     &csi.DeleteSnapshotRequest{SnapshotId: "invalid_backup_id"},
+			want:    nil,
+			wantErr: errors.New("failed to delete volume backup"),
+		},
+		{
+			name:    "Delete volume backup and get empty response",
+			req:     &csi.DeleteSnapshotRequest{SnapshotId: "available_backup_id"},
+			want:    &csi.DeleteSnapshotResponse{},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: ""},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{},
+			}
+			got, err := d.DeleteSnapshot(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControllerDriver.DeleteSnapshot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_ListSnapshots(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *csi.ListSnapshotsRequest
+		wantIDs       []string
+		wantNextToken string
+		wantErr       error
+	}{
+		{
+			name:    "Error for negative max entries",
+			req:     &csi.ListSnapshotsRequest{MaxEntries: -1},
+			wantErr: errors.New("max entries must not be negative"),
+		},
+		{
+			name:    "Snapshot ID lookup",
+			req:     &csi.ListSnapshotsRequest{SnapshotId: "available_backup_id"},
+			wantIDs: []string{"available_backup_id"},
+		},
+		{
+			name:    "Error for failed snapshot ID lookup",
+			req:     &csi.ListSnapshotsRequest{SnapshotId: "invalid_backup_id"},
+			wantErr: errors.New("failed to get volume backup"),
+		},
+		{
+			name:    "Snapshot ID lookup of terminated backup",
+			req:     &csi.ListSnapshotsRequest{SnapshotId: "terminated_backup_id"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "Snapshot ID lookup filtered by another source volume",
+			req:     &csi.ListSnapshotsRequest{SnapshotId: "available_backup_id", SourceVolumeId: "other_volume_id"},
+			wantIDs: []string{},
+		},
+		{
+			name:          "First page skips terminated backups",
+			req:           &csi.ListSnapshotsRequest{SourceVolumeId: "valid_volume_id", MaxEntries: 2},
+			wantIDs:       []string{"available_backup_id"},
+			wantNextToken: "page2",
+		},
+		{
+			name:    "Last page",
+			req:     &csi.ListSnapshotsRequest{StartingToken: "page2"},
+			wantIDs: []string{"faulty_backup_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: ""},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{},
+			}
+			got, err := d.ListSnapshots(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("want error %q to include %q", err, tt.wantErr)
+				}
+				return
+			}
+			gotIDs := []string{}
+			for _, entry := range got.Entries {
+				gotIDs = append(gotIDs, entry.Snapshot.SnapshotId)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("ControllerDriver.ListSnapshots() = %v, want %v", gotIDs, tt.wantIDs)
+			}
+			if got.NextToken != tt.wantNextToken {
+				t.Errorf("ControllerDriver.ListSnapshots() next token = %q, want %q", got.NextToken, tt.wantNextToken)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_validateCapabilities(t *testing.T) {
+	mountCapability := func(mode csi.VolumeCapability_AccessMode_Mode) *csi.VolumeCapability {
+		return &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+		}
+	}
+	blockCapability := func(mode csi.VolumeCapability_AccessMode_Mode) *csi.VolumeCapability {
+		return &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+		}
+	}
+	tests := []struct {
+		name string
+		caps []*csi.VolumeCapability
+		want bool
+	}{
+		{
+			name: "SINGLE_NODE_WRITER filesystem",
+			caps: []*csi.VolumeCapability{mountCapability(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER)},
+			want: true,
+		},
+		{
+			name: "MULTI_NODE_READER_ONLY filesystem",
+			caps: []*csi.VolumeCapability{mountCapability(csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY)},
+			want: true,
+		},
+		{
+			name: "MULTI_NODE_MULTI_WRITER raw block",
+			caps: []*csi.VolumeCapability{blockCapability(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER)},
+			want: true,
+		},
+		{
+			name: "MULTI_NODE_MULTI_WRITER filesystem",
+			caps: []*csi.VolumeCapability{mountCapability(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER)},
+			want: false,
+		},
+		{
+			name: "MULTI_NODE_SINGLE_WRITER",
+			caps: []*csi.VolumeCapability{blockCapability(csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER)},
+			want: false,
+		},
+		{
+			name: "one unsupported capability",
+			caps: []*csi.VolumeCapability{
+				mountCapability(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER),
+				mountCapability(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{logger: zap.S()}
+			if got := d.validateCapabilities(tt.caps); got != tt.want {
+				t.Errorf("ControllerDriver.validateCapabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_ListVolumes(t *testing.T) {
+	type entry struct {
+		volumeID         string
+		publishedNodeIDs []string
+		abnormal         bool
+	}
+	tests := []struct {
+		name          string
+		req           *csi.ListVolumesRequest
+		want          []entry
+		wantNextToken string
+		wantErr       error
+	}{
+		{
+			name:    "Error for negative max entries",
+			req:     &csi.ListVolumesRequest{MaxEntries: -1},
+			wantErr: errors.New("max entries must not be negative"),
+		},
+		{
+			name:    "Error for invalid starting token",
+			req:     &csi.ListVolumesRequest{StartingToken: "invalid_page"},
+			wantErr: errors.New("failed to list volumes"),
+		},
+		{
+			name:          "First page skips terminated volumes",
+			req:           &csi.ListVolumesRequest{MaxEntries: 2},
+			want:          []entry{{volumeID: "attached_volume_id", publishedNodeIDs: []string{"node1"}}},
+			wantNextToken: "page2",
+		},
+		{
+			name: "Last page reports detached and faulty volumes of the driver only",
+			req:  &csi.ListVolumesRequest{StartingToken: "page2"},
+			want: []entry{
+				{volumeID: "detached_volume_id", abnormal: true},
+				{volumeID: "faulty_volume_id", abnormal: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: MockKubeClient{},
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: "compartment_id"},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{Logger: zap.S()},
+			}
+			got, err := d.ListVolumes(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("want error %q to include %q", err, tt.wantErr)
+				}
+				return
+			}
+			var gotEntries []entry
+			for _, e := range got.Entries {
+				gotEntries = append(gotEntries, entry{
+					volumeID:         e.Volume.VolumeId,
+					publishedNodeIDs: e.Status.PublishedNodeIds,
+					abnormal:         e.Status.VolumeCondition.Abnormal,
+				})
+			}
+			if !reflect.DeepEqual(gotEntries, tt.want) {
+				t.Errorf("ControllerDriver.ListVolumes() = %+v, want %+v", gotEntries, tt.want)
+			}
+			if got.NextToken != tt.wantNextToken {
+				t.Errorf("ControllerDriver.ListVolumes() next token = %q, want %q", got.NextToken, tt.wantNextToken)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_ControllerGetVolume(t *testing.T) {
+	tests := []struct {
+		name                 string
+		req                  *csi.ControllerGetVolumeRequest
+		wantPublishedNodeIDs []string
+		wantCondition        *csi.VolumeCondition
+		wantErr              error
+	}{
+		{
+			name:    "Error for volume ID not provided",
+			req:     &csi.ControllerGetVolumeRequest{},
+			wantErr: errors.New("ControllerGetVolume Volume ID must be provided"),
+		},
+		{
+			name:    "Error for failed volume lookup",
+			req:     &csi.ControllerGetVolumeRequest{VolumeId: "invalid_volume_id"},
+			wantErr: errors.New("failed to get volume"),
+		},
+		{
+			name:                 "Attached volume is healthy",
+			req:                  &csi.ControllerGetVolumeRequest{VolumeId: "attached_volume_id"},
+			wantPublishedNodeIDs: []string{"node1"},
+			wantCondition:        &csi.VolumeCondition{Message: "volume attached_volume_id is AVAILABLE"},
+		},
+		{
+			name: "Volume detached outside of Kubernetes",
+			req:  &csi.ControllerGetVolumeRequest{VolumeId: "detached_volume_id"},
+			wantCondition: &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  "volume detached_volume_id is not attached to node node1",
+			},
+		},
+		{
+			name:          "Faulty volume",
+			req:           &csi.ControllerGetVolumeRequest{VolumeId: "faulty_volume_id"},
+			wantCondition: &csi.VolumeCondition{Abnormal: true, Message: "volume faulty_volume_id is FAULTY"},
+		},
+		{
+			name:          "Volume deleted outside of Kubernetes",
+			req:           &csi.ControllerGetVolumeRequest{VolumeId: "terminated_volume_id"},
+			wantCondition: &csi.VolumeCondition{Abnormal: true, Message: "volume terminated_volume_id has been deleted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: MockKubeClient{},
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: "compartment_id"},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{Logger: zap.S()},
+			}
+			got, err := d.ControllerGetVolume(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("want error %q to include %q", err, tt.wantErr)
+				}
+				return
+			}
+			if got.Volume.VolumeId != tt.req.VolumeId {
+				t.Errorf("ControllerDriver.ControllerGetVolume() volume = %q, want %q", got.Volume.VolumeId, tt.req.VolumeId)
+			}
+			if !reflect.DeepEqual(got.Status.PublishedNodeIds, tt.wantPublishedNodeIDs) {
+				t.Errorf("ControllerDriver.ControllerGetVolume() published nodes = %v, want %v", got.Status.PublishedNodeIds, tt.wantPublishedNodeIDs)
+			}
+			if !reflect.DeepEqual(got.Status.VolumeCondition, tt.wantCondition) {
+				t.Errorf("ControllerDriver.ControllerGetVolume() condition = %v, want %v", got.Status.VolumeCondition, tt.wantCondition)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_GetCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *csi.GetCapacityRequest
+		want    *csi.GetCapacityResponse
+		wantErr error
+	}{
+		{
+			name: "No capacity for unsupported capabilities",
+			req: &csi.GetCapacityRequest{
+				VolumeCapabilities: []*csi.VolumeCapability{{
+					AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+					AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+				}},
+			},
+			want: &csi.GetCapacityResponse{},
+		},
+		{
+			name: "Capacity left in the availability domain of the topology",
+			req: &csi.GetCapacityRequest{
+				AccessibleTopology: &csi.Topology{Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "AD1"}},
+			},
+			want: &csi.GetCapacityResponse{
+				AvailableCapacity: 100 * client.GiB,
+				MaximumVolumeSize: &wrappers.Int64Value{Value: 100 * client.GiB},
+				MinimumVolumeSize: &wrappers.Int64Value{Value: csi_util.MinimumVolumeSizeInBytes},
+			},
+		},
+		{
+			name: "Capacity left in all availability domains",
+			req:  &csi.GetCapacityRequest{},
+			want: &csi.GetCapacityResponse{
+				AvailableCapacity: 100 * client.GiB,
+				MinimumVolumeSize: &wrappers.Int64Value{Value: csi_util.MinimumVolumeSizeInBytes},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: "compartment_id"},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{Logger: zap.S()},
+			}
+			got, err := d.GetCapacity(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControllerDriver.GetCapacity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePublishContext(t *testing.T) {
+	iqn := "iqn.2015-12.com.oracleiaas:abc"
+	attachment := core.IScsiVolumeAttachment{
+		Id:   common.String("attachment_id"),
+		Iqn:  common.String(iqn),
+		Ipv4: common.String("169.254.2.2"),
+		Port: common.Int(3260),
+	}
+	multipathAttachment := attachment
+	multipathAttachment.IsMultipath = common.Bool(true)
+	multipathAttachment.MultipathDevices = []core.MultipathDevice{
+		{Iqn: common.String(iqn), Ipv4: common.String("169.254.2.2"), Port: common.Int(3260)},
+		{Iqn: common.String(iqn), Ipv4: common.String("169.254.2.3"), Port: common.Int(3260)},
+	}
+
+	tests := map[string]struct {
+		attachment core.VolumeAttachment
+		want       map[string]string
+	}{
+		"single path iSCSI attachment": {
+			attachment: attachment,
+			want: map[string]string{
+				attachmentType:     attachmentTypeISCSI,
+				disk.ISCSIIQN:      iqn,
+				disk.ISCSIIP:       "169.254.2.2",
+				disk.ISCSIPORT:     "3260",
+				csi_util.VpusPerGB: "30",
+			},
+		},
+		"multipath iSCSI attachment": {
+			attachment: multipathAttachment,
+			want: map[string]string{
+				attachmentType:             attachmentTypeISCSI,
+				disk.ISCSIIQN:              iqn,
+				disk.ISCSIIP:               "169.254.2.2",
+				disk.ISCSIPORT:             "3260",
+				csi_util.VpusPerGB:         "30",
+				disk.ISCSIMULTIPATHDEVICES: "169.254.2.2:3260-" + iqn + ",169.254.2.3:3260-" + iqn,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := generatePublishContext(VolumeAttachmentOption{}, zap.S(), tt.attachment, "30")
+			if !reflect.DeepEqual(resp.PublishContext, tt.want) {
+				t.Errorf("generatePublishContext() = %v, want %v", resp.PublishContext, tt.want)
+			}
+		})
+	}
+}
@@ -9,13 +9,20 @@ import (
 	"path"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/kubernetes-csi/csi-lib-utils/protosanitizer"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	kubeAPI "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/oracle/oci-cloud-controller-manager/cmd/oci-csi-node-driver/nodedriveroptions"
 	providercfg "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
@@ -23,6 +30,7 @@ import (
 	"github.com/oracle/oci-cloud-controller-manager/pkg/metrics"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/instance/metadata"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/nfs"
 )
 
 const (
@@ -64,15 +72,39 @@ type ControllerDriver struct {
 	client       client.Interface
 	util         *csi_util.Util
 	metricPusher *metrics.MetricPusher
+
+	// orphanedAttachmentGracePeriod is how long volumes stay attached to
+	// deleted or terminated nodes before they are detached, 0 (the default)
+	// disables it.
+	orphanedAttachmentGracePeriod time.Duration
+
+	// volumePerformance is nil unless the block volume controller is running.
+	volumePerformance *volumePerformanceController
+}
+
+// FSSControllerDriver extends ControllerDriver to implement the CSI
+// Controller interfaces for FSS
+type FSSControllerDriver struct {
+	*ControllerDriver
+
+	// subdirectories provisions the volumes that are subdirectories of a
+	// shared file system.
+	subdirectories nfs.Subdirectories
 }
 
 // NodeDriver implements CSI Node interfaces
 type NodeDriver struct {
-	nodeID      string
-	KubeClient  kubernetes.Interface
-	logger      *zap.SugaredLogger
-	util        *csi_util.Util
-	volumeLocks *csi_util.VolumeLocks
+	nodeID            string
+	maxVolumesPerNode int64
+	KubeClient        kubernetes.Interface
+	logger            *zap.SugaredLogger
+	util              *csi_util.Util
+	volumeLocks       *csi_util.VolumeLocks
+
+	// volumeLimitsPerShape maps instance shapes to the number of block
+	// volumes that can be attached to their instances.
+	volumeLimitsPerShape map[string]int64
+	metadata             metadata.Interface
 }
 
 // BlockVolumeNodeDriver extends NodeDriver
@@ -85,22 +117,26 @@ type FSSNodeDriver struct {
 	NodeDriver
 }
 
-func newNodeDriver(nodeID string, kubeClientSet kubernetes.Interface, logger *zap.SugaredLogger) NodeDriver {
+func newNodeDriver(nodeOptions nodedriveroptions.NodeOptions, kubeClientSet kubernetes.Interface, logger *zap.SugaredLogger) NodeDriver {
 	return NodeDriver{
-		nodeID:      nodeID,
-		KubeClient:  kubeClientSet,
-		logger:      logger,
-		util:        &csi_util.Util{Logger: logger},
-		volumeLocks: csi_util.NewVolumeLocks(),
+		nodeID:            nodeOptions.NodeID,
+		maxVolumesPerNode: nodeOptions.MaxVolumesPerNode,
+		KubeClient:        kubeClientSet,
+		logger:            logger,
+		util:              &csi_util.Util{Logger: logger},
+		volumeLocks:       csi_util.NewVolumeLocks(),
+
+		volumeLimitsPerShape: nodeOptions.VolumeLimitsPerShape,
+		metadata:             metadata.New(),
 	}
 }
 
-func GetNodeDriver(name string, nodeID string, kubeClientSet kubernetes.Interface, logger *zap.SugaredLogger) csi.NodeServer {
-	if name == BlockVolumeDriverName {
-		return BlockVolumeNodeDriver{NodeDriver: newNodeDriver(nodeID, kubeClientSet, logger)}
+func GetNodeDriver(nodeOptions nodedriveroptions.NodeOptions, kubeClientSet kubernetes.Interface, logger *zap.SugaredLogger) csi.NodeServer {
+	if nodeOptions.DriverName == BlockVolumeDriverName {
+		return BlockVolumeNodeDriver{NodeDriver: newNodeDriver(nodeOptions, kubeClientSet, logger)}
 	}
-	if name == FSSDriverName {
-		return FSSNodeDriver{NodeDriver: newNodeDriver(nodeID, kubeClientSet, logger)}
+	if nodeOptions.DriverName == FSSDriverName {
+		return FSSNodeDriver{NodeDriver: newNodeDriver(nodeOptions, kubeClientSet, logger)}
 	}
 	return nil
 }
@@ -114,7 +150,7 @@ func NewNodeDriver(logger *zap.SugaredLogger, nodeOptions nodedriveroptions.Node
 
 	return &Driver{
 		ControllerDriver:       nil,
-		nodeDriver:             GetNodeDriver(nodeOptions.DriverName, nodeOptions.NodeID, kubeClientSet, logger),
+		nodeDriver:             GetNodeDriver(nodeOptions, kubeClientSet, logger),
 		endpoint:               nodeOptions.Endpoint,
 		logger:                 logger,
 		enableControllerServer: nodeOptions.EnableControllerServer,
@@ -125,7 +161,7 @@ func NewNodeDriver(logger *zap.SugaredLogger, nodeOptions nodedriveroptions.Node
 }
 
 // NewControllerDriver creates a new CSI driver for OCI blockvolume
-func NewControllerDriver(logger *zap.SugaredLogger, endpoint, kubeconfig, master string, enableControllerServer bool, name, version string) (*Driver, error) {
+func NewControllerDriver(logger *zap.SugaredLogger, endpoint, kubeconfig, master string, enableControllerServer bool, name, version string, orphanedAttachmentGracePeriod time.Duration) (*Driver, error) {
 	logger.With("endpoint", endpoint, "kubeconfig", kubeconfig, "master",
 		master).Info("Creating a new CSI Controller driver.")
 
@@ -141,6 +177,8 @@ func NewControllerDriver(logger *zap.SugaredLogger, endpoint, kubeconfig, master
 		util:       &csi_util.Util{Logger: logger},
 		config:     cfg,
 		client:     c,
+
+		orphanedAttachmentGracePeriod: orphanedAttachmentGracePeriod,
 	}
 
 	return &Driver{
@@ -154,6 +192,19 @@ func NewControllerDriver(logger *zap.SugaredLogger, endpoint, kubeconfig, master
 	}, nil
 }
 
+func (d *Driver) GetControllerDriver() csi.ControllerServer {
+	if d.name == BlockVolumeDriverName {
+		return d.ControllerDriver
+	}
+	if d.name == FSSDriverName {
+		return &FSSControllerDriver{
+			ControllerDriver: d.ControllerDriver,
+			subdirectories:   nfs.NewSubdirectories(d.logger, d.KubeClient),
+		}
+	}
+	return nil
+}
+
 func (d *Driver) GetNodeDriver() csi.NodeServer {
 	if d.name == BlockVolumeDriverName {
 		return d.nodeDriver.(BlockVolumeNodeDriver)
@@ -212,7 +263,7 @@ func (d *Driver) Run() error {
 	d.srv = grpc.NewServer(grpc.UnaryInterceptor(errHandler))
 	csi.RegisterIdentityServer(d.srv, d)
 	if d.enableControllerServer {
-		csi.RegisterControllerServer(d.srv, d)
+		csi.RegisterControllerServer(d.srv, d.GetControllerDriver())
 	} else {
 		csi.RegisterNodeServer(d.srv, d.GetNodeDriver())
 	}
@@ -230,10 +281,39 @@ func (d *Driver) Run() error {
 		d.logger.Info("Metrics collection is not enabled")
 	}
 
+	if d.enableControllerServer && d.name == BlockVolumeDriverName {
+		// The caches are needed by every replica to publish volumes, while
+		// the volumes are only updated by the leader.
+		factory := informers.NewSharedInformerFactory(d.KubeClient, 0)
+		d.volumePerformance = newVolumePerformanceController(d.ControllerDriver, factory, d.newEventRecorder())
+		factory.Start(wait.NeverStop)
+		go d.ControllerDriver.runWithLeaderElection(func(stopCh <-chan struct{}) {
+			go d.volumePerformance.Run(volumePerformanceWorkers, stopCh)
+			if d.orphanedAttachmentGracePeriod > 0 {
+				go d.ControllerDriver.runOrphanedAttachmentReconciler(d.orphanedAttachmentGracePeriod, stopCh)
+			}
+		})
+	}
+
+	if !d.enableControllerServer && d.name == BlockVolumeDriverName {
+		nodeDriver := d.nodeDriver.(BlockVolumeNodeDriver).NodeDriver
+		factory := informers.NewSharedInformerFactory(nodeDriver.KubeClient, 0)
+		queueDepth := newQueueDepthController(nodeDriver, factory)
+		factory.Start(wait.NeverStop)
+		go queueDepth.Run(wait.NeverStop)
+	}
+
 	d.logger.Info("CSI Driver has started.")
 	return d.srv.Serve(listener)
 }
 
+// newEventRecorder returns a recorder for the events of the controller driver.
+func (d *ControllerDriver) newEventRecorder() record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: d.KubeClient.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, kubeAPI.EventSource{Component: "oci-csi-controller"})
+}
+
 func getConfig(logger *zap.SugaredLogger) *providercfg.Config {
 	configPath, ok := os.LookupEnv("CONFIG_YAML_FILENAME")
 	if !ok {
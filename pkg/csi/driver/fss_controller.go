@@ -0,0 +1,631 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	fss "github.com/oracle/oci-go-sdk/v50/filestorage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/metrics"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/nfs"
+)
+
+const (
+	// mountTargetOcid is the StorageClass parameter naming the mount target the
+	// file system is exported on. The file system is created in the mount
+	// target's availability domain.
+	mountTargetOcid = "mountTargetOcid"
+	// compartmentOcid is the StorageClass parameter overriding the compartment
+	// the file system is created in.
+	compartmentOcid = "compartmentOcid"
+	// kmsKeyOcid is the StorageClass parameter naming the KMS key used to
+	// encrypt the file system.
+	kmsKeyOcid = "kmsKeyOcid"
+	// encryptInTransit is the StorageClass parameter, passed on to the node
+	// driver in the volume context, enabling in-transit encryption.
+	encryptInTransit = "encryptInTransit"
+
+	// fileSystemOcid is the StorageClass parameter naming an existing file
+	// system that the volumes are provisioned as subdirectories of, instead of
+	// creating a file system for each volume.
+	fileSystemOcid = "fileSystemOcid"
+	// exportPath is the StorageClass parameter giving the path the shared file
+	// system is exported with on the mount target. It defaults to the path of
+	// the export of the file system in the mount target's export set.
+	exportPath = "exportPath"
+	// archiveOnDelete is the StorageClass parameter renaming the subdirectory
+	// of a deleted volume instead of removing it. DeleteVolume requests don't
+	// carry the volume context, so the volume ID of these volumes ends with
+	// archiveOnDeleteVolumeIDSuffix instead.
+	archiveOnDelete               = "archiveOnDelete"
+	archiveOnDeleteVolumeIDSuffix = "archive"
+	// directoryMode, directoryUid and directoryGid are the StorageClass
+	// parameters giving the permissions and ownership of the subdirectories.
+	directoryMode = "directoryMode"
+	directoryUid  = "directoryUid"
+	directoryGid  = "directoryGid"
+
+	defaultDirectoryMode os.FileMode = 0777
+)
+
+// FSSVolumeParameters holds the StorageClass parameters of a FSS volume.
+type FSSVolumeParameters struct {
+	mountTargetID    string
+	compartmentID    string
+	kmsKeyID         string
+	encryptInTransit string
+
+	// The parameters of volumes provisioned as subdirectories of a shared
+	// file system.
+	fileSystemID    string
+	exportPath      string
+	archiveOnDelete string
+	directoryMode   os.FileMode
+	directoryUID    int
+	directoryGID    int
+}
+
+func extractFSSVolumeParameters(parameters map[string]string, defaultCompartmentID string) (FSSVolumeParameters, error) {
+	p := FSSVolumeParameters{
+		compartmentID: defaultCompartmentID,
+		directoryMode: defaultDirectoryMode,
+	}
+	for k, v := range parameters {
+		switch k {
+		case mountTargetOcid:
+			p.mountTargetID = v
+		case compartmentOcid:
+			if v != "" {
+				p.compartmentID = v
+			}
+		case kmsKeyOcid:
+			p.kmsKeyID = v
+		case encryptInTransit:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return p, fmt.Errorf("%s must be a boolean value", encryptInTransit)
+			}
+			p.encryptInTransit = v
+		case fileSystemOcid:
+			p.fileSystemID = v
+		case exportPath:
+			if !strings.HasPrefix(v, "/") || strings.Contains(v, ":") {
+				return p, fmt.Errorf("%s must be an absolute path without colons", exportPath)
+			}
+			p.exportPath = v
+		case archiveOnDelete:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return p, fmt.Errorf("%s must be a boolean value", archiveOnDelete)
+			}
+			p.archiveOnDelete = v
+		case directoryMode:
+			mode, err := nfs.ParseMode(v)
+			if err != nil {
+				return p, fmt.Errorf("%s: %v", directoryMode, err)
+			}
+			p.directoryMode = mode
+		case directoryUid, directoryGid:
+			id, err := nfs.ParseID(v)
+			if err != nil {
+				return p, fmt.Errorf("%s: %v", k, err)
+			}
+			if k == directoryUid {
+				p.directoryUID = id
+			} else {
+				p.directoryGID = id
+			}
+		}
+	}
+	if p.mountTargetID == "" {
+		return p, fmt.Errorf("%s must be provided in the storage class parameters", mountTargetOcid)
+	}
+	if p.fileSystemID == "" {
+		for _, k := range []string{exportPath, archiveOnDelete, directoryMode, directoryUid, directoryGid} {
+			if _, ok := parameters[k]; ok {
+				return p, fmt.Errorf("%s can only be used with %s", k, fileSystemOcid)
+			}
+		}
+	} else if p.kmsKeyID != "" {
+		return p, fmt.Errorf("%s can't be used with %s, the shared file system is already encrypted", kmsKeyOcid, fileSystemOcid)
+	}
+	return p, nil
+}
+
+// subdirectoryFromVolumeID returns the parts of a volume ID of the form
+// fsId:mountTargetIP:exportPath:subdirectory[:archive], the ID of the volumes
+// that are subdirectories of a shared file system, and whether the
+// subdirectory is archived on delete. ok is false for other volume IDs.
+func subdirectoryFromVolumeID(volumeID string) (mountTargetIP, exportPath, subdirectory string, archive, ok bool) {
+	parts := strings.Split(volumeID, ":")
+	if len(parts) < 4 || fileSystemIDFromVolumeID(volumeID) == "" {
+		return "", "", "", false, false
+	}
+	return parts[1], parts[2], parts[3], len(parts) == 5, true
+}
+
+// fileSystemIDFromVolumeID returns the file system OCID from a volume ID of
+// the form fsId:mountTargetIP:exportPath or
+// fsId:mountTargetIP:exportPath:subdirectory[:archive].
+func fileSystemIDFromVolumeID(volumeID string) string {
+	if mountTargetIP, exportPath := validateVolumeId(volumeID); mountTargetIP == "" || exportPath == "" {
+		return ""
+	}
+	return strings.Split(volumeID, ":")[0]
+}
+
+// CreateVolume creates a file system and exports it on the mount target given
+// in the StorageClass parameters. The function is idempotent: the file system
+// display name is the volume name. If the StorageClass names a shared file
+// system, the volume is a subdirectory of it named after the volume instead.
+func (d *FSSControllerDriver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+	startTime := time.Now()
+	log := d.logger.With("volumeName", req.Name)
+	var errorType string
+	var csiMetricDimension string
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "CreateVolume Name must be provided")
+	}
+
+	if req.VolumeCapabilities == nil || len(req.VolumeCapabilities) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "VolumeCapabilities must be provided in CreateVolumeRequest")
+	}
+
+	if !d.validateCapabilities(req.VolumeCapabilities) {
+		return nil, status.Error(codes.InvalidArgument, "invalid volume capabilities requested. Raw block volumes are not supported by FSS")
+	}
+
+	for _, cap := range req.VolumeCapabilities {
+		if err := validateFSSMountOptions(cap.GetMount().GetMountFlags()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid mount options: %v", err)
+		}
+	}
+
+	if req.VolumeContentSource != nil {
+		return nil, status.Error(codes.InvalidArgument, "volume content source is not supported by FSS")
+	}
+
+	dimensionsMap := make(map[string]string)
+
+	volumeParams, err := extractFSSVolumeParameters(req.Parameters, d.config.CompartmentID)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to parse storageclass parameters.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse storageclass parameters: %v", err)
+	}
+	log = log.With("mountTargetID", volumeParams.mountTargetID, "compartmentID", volumeParams.compartmentID)
+
+	mountTarget, err := d.client.FSS().AwaitMountTargetActive(ctx, log, volumeParams.mountTargetID)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get active mount target.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to get active mount target %s: %v", volumeParams.mountTargetID, err)
+	}
+	if len(mountTarget.PrivateIpIds) == 0 || mountTarget.ExportSetId == nil {
+		log.Error("Mount target has no private IP or export set associated with it.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.FailedPrecondition, "mount target %s has no private IP or export set associated with it", volumeParams.mountTargetID)
+	}
+
+	// The first private IP is used so that retries produce the same volume ID.
+	privateIP, err := d.client.Networking().GetPrivateIP(ctx, mountTarget.PrivateIpIds[0])
+	if err != nil || privateIP.IpAddress == nil {
+		log.With(zap.Error(err)).Error("Failed to get the IP address of the mount target.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to get the IP address of mount target %s: %v", volumeParams.mountTargetID, err)
+	}
+	log = log.With("mountTargetIP", *privateIP.IpAddress)
+
+	if volumeParams.fileSystemID != "" {
+		return d.createSubdirectoryVolume(ctx, log, req, mountTarget, *privateIP.IpAddress, volumeParams, startTime)
+	}
+
+	fileSystem, err := d.getOrCreateFileSystem(ctx, log, req.Name, *mountTarget.AvailabilityDomain, volumeParams)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to create file system.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to create file system: %v", err)
+	}
+	dimensionsMap[metrics.ResourceOCIDDimension] = *fileSystem.Id
+	log = log.With("fileSystemID", *fileSystem.Id)
+
+	export, err := d.getOrCreateExport(ctx, log, req.Name, *fileSystem.Id, *mountTarget.ExportSetId, volumeParams.compartmentID)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to create export.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to create export: %v", err)
+	}
+
+	volumeContext := map[string]string{}
+	if volumeParams.encryptInTransit != "" {
+		volumeContext[encryptInTransit] = volumeParams.encryptInTransit
+	}
+
+	log.With("exportID", *export.Id, "exportPath", *export.Path).Info("File system is provisioned.")
+	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+	metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId: fmt.Sprintf("%s:%s:%s", *fileSystem.Id, *privateIP.IpAddress, *export.Path),
+			// FSS doesn't enforce a quota, the requested capacity is reported as is.
+			CapacityBytes: req.GetCapacityRange().GetRequiredBytes(),
+			VolumeContext: volumeContext,
+		},
+	}, nil
+}
+
+// createSubdirectoryVolume provisions the volume as a subdirectory of the
+// shared file system, exported on the mount target.
+func (d *FSSControllerDriver) createSubdirectoryVolume(ctx context.Context, log *zap.SugaredLogger, req *csi.CreateVolumeRequest, mountTarget *fss.MountTarget, mountTargetIP string, volumeParams FSSVolumeParameters, startTime time.Time) (*csi.CreateVolumeResponse, error) {
+	var errorType string
+	var csiMetricDimension string
+	dimensionsMap := map[string]string{metrics.ResourceOCIDDimension: volumeParams.fileSystemID}
+	log = log.With("fileSystemID", volumeParams.fileSystemID, "subdirectory", req.Name)
+
+	if err := nfs.ValidateName(req.Name); err != nil {
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.ErrValidation, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.InvalidArgument, "volume name can't be used as a subdirectory: %v", err)
+	}
+
+	path, err := d.getSharedExportPath(ctx, mountTarget, volumeParams)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to get the export of the shared file system.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to get the export of file system %s: %v", volumeParams.fileSystemID, err)
+	}
+	log = log.With("exportPath", path)
+
+	if err := d.subdirectories.Create(ctx, mountTargetIP, path, req.Name, volumeParams.directoryMode, volumeParams.directoryUID, volumeParams.directoryGID); err != nil {
+		log.With(zap.Error(err)).Error("Failed to create subdirectory.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.GetError(err), util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to create subdirectory: %v", err)
+	}
+
+	volumeContext := map[string]string{}
+	if volumeParams.encryptInTransit != "" {
+		volumeContext[encryptInTransit] = volumeParams.encryptInTransit
+	}
+
+	volumeID := fmt.Sprintf("%s:%s:%s:%s", volumeParams.fileSystemID, mountTargetIP, path, req.Name)
+	if archive, _ := strconv.ParseBool(volumeParams.archiveOnDelete); archive {
+		volumeID += ":" + archiveOnDeleteVolumeIDSuffix
+	}
+
+	log.Info("Subdirectory of shared file system is provisioned.")
+	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+	metrics.SendMetricData(d.metricPusher, metrics.FSSProvision, time.Since(startTime).Seconds(), dimensionsMap)
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      volumeID,
+			CapacityBytes: req.GetCapacityRange().GetRequiredBytes(),
+			VolumeContext: volumeContext,
+		},
+	}, nil
+}
+
+// getSharedExportPath checks that the shared file system can be exported on
+// the mount target and returns the export path, looking up the export in the
+// mount target's export set unless it is given in the StorageClass.
+func (d *FSSControllerDriver) getSharedExportPath(ctx context.Context, mountTarget *fss.MountTarget, volumeParams FSSVolumeParameters) (string, error) {
+	fileSystem, err := d.client.FSS().GetFileSystem(ctx, volumeParams.fileSystemID)
+	if err != nil {
+		return "", err
+	}
+	if fileSystem.LifecycleState != fss.FileSystemLifecycleStateActive {
+		return "", fmt.Errorf("file system is %s", fileSystem.LifecycleState)
+	}
+	if fileSystem.AvailabilityDomain != nil && *fileSystem.AvailabilityDomain != *mountTarget.AvailabilityDomain {
+		return "", fmt.Errorf("file system is in availability domain %s, mount target %s is in %s",
+			*fileSystem.AvailabilityDomain, volumeParams.mountTargetID, *mountTarget.AvailabilityDomain)
+	}
+	if volumeParams.exportPath != "" {
+		return volumeParams.exportPath, nil
+	}
+
+	export, err := d.client.FSS().FindExport(ctx, *fileSystem.CompartmentId, volumeParams.fileSystemID, *mountTarget.ExportSetId)
+	if err != nil && !client.IsNotFound(err) {
+		return "", err
+	}
+	if export == nil || export.Path == nil {
+		return "", fmt.Errorf("file system isn't exported on mount target %s", volumeParams.mountTargetID)
+	}
+	return *export.Path, nil
+}
+
+func (d *FSSControllerDriver) getOrCreateFileSystem(ctx context.Context, log *zap.SugaredLogger, name, availabilityDomain string, volumeParams FSSVolumeParameters) (*fss.FileSystem, error) {
+	summary, err := d.client.FSS().GetFileSystemSummaryByDisplayName(ctx, volumeParams.compartmentID, availabilityDomain, name)
+	if err != nil && !client.IsNotFound(err) {
+		return nil, err
+	}
+	if summary != nil {
+		log.With("fileSystemID", *summary.Id).Info("File system already exists.")
+		return d.client.FSS().AwaitFileSystemActive(ctx, log, *summary.Id)
+	}
+
+	details := fss.CreateFileSystemDetails{
+		CompartmentId:      &volumeParams.compartmentID,
+		AvailabilityDomain: &availabilityDomain,
+		DisplayName:        &name,
+	}
+	if volumeParams.kmsKeyID != "" {
+		details.KmsKeyId = &volumeParams.kmsKeyID
+	}
+	fileSystem, err := d.client.FSS().CreateFileSystem(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+	log.With("fileSystemID", *fileSystem.Id).Info("Created file system.")
+	return d.client.FSS().AwaitFileSystemActive(ctx, log, *fileSystem.Id)
+}
+
+func (d *FSSControllerDriver) getOrCreateExport(ctx context.Context, log *zap.SugaredLogger, name, fileSystemID, exportSetID, compartmentID string) (*fss.Export, error) {
+	summary, err := d.client.FSS().FindExport(ctx, compartmentID, fileSystemID, exportSetID)
+	if err != nil && !client.IsNotFound(err) {
+		return nil, err
+	}
+	if summary != nil {
+		log.With("exportID", *summary.Id).Info("Export already exists.")
+		return d.client.FSS().AwaitExportActive(ctx, log, *summary.Id)
+	}
+
+	path := "/" + name
+	export, err := d.client.FSS().CreateExport(ctx, fss.CreateExportDetails{
+		ExportSetId:  &exportSetID,
+		FileSystemId: &fileSystemID,
+		Path:         &path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.With("exportID", *export.Id).Info("Created export.")
+	return d.client.FSS().AwaitExportActive(ctx, log, *export.Id)
+}
+
+// DeleteVolume deletes the exports of the file system and then the file
+// system itself. The volumes that are subdirectories of a shared file system
+// only have their subdirectory removed or archived.
+func (d *FSSControllerDriver) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
+	startTime := time.Now()
+	log := d.logger.With("volumeID", req.VolumeId)
+	var errorType string
+	var csiMetricDimension string
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "DeleteVolume Volume ID must be provided")
+	}
+
+	fileSystemID := fileSystemIDFromVolumeID(req.VolumeId)
+	if fileSystemID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Invalid Volume ID provided")
+	}
+	log = log.With("fileSystemID", fileSystemID)
+
+	dimensionsMap := make(map[string]string)
+	dimensionsMap[metrics.ResourceOCIDDimension] = fileSystemID
+
+	if mountTargetIP, path, subdirectory, archive, ok := subdirectoryFromVolumeID(req.VolumeId); ok {
+		log = log.With("mountTargetIP", mountTargetIP, "exportPath", path, "subdirectory", subdirectory)
+		if err := d.subdirectories.Delete(ctx, mountTargetIP, path, subdirectory, archive); err != nil {
+			log.With(zap.Error(err)).Error("Failed to delete subdirectory.")
+			csiMetricDimension = util.GetMetricDimensionForComponent(util.GetError(err), util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, status.Errorf(codes.Internal, "failed to delete subdirectory: %v", err)
+		}
+		log.With("archived", archive).Info("Subdirectory of shared file system is deleted.")
+		csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	fileSystem, err := d.client.FSS().GetFileSystem(ctx, fileSystemID)
+	if err != nil {
+		if client.IsNotFound(err) {
+			log.Info("File system not found, it is already deleted.")
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		log.With(zap.Error(err)).Error("Failed to get file system.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to get file system %s: %v", fileSystemID, err)
+	}
+	if fileSystem.LifecycleState == fss.FileSystemLifecycleStateDeleted {
+		log.Info("File system is already deleted.")
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	exports, err := d.client.FSS().ListExportsForFileSystem(ctx, *fileSystem.CompartmentId, fileSystemID)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to list exports of file system.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to list exports of file system %s: %v", fileSystemID, err)
+	}
+
+	for _, export := range exports {
+		log.With("exportID", *export.Id).Info("Deleting export.")
+		if err := d.client.FSS().DeleteExport(ctx, *export.Id); err != nil && !client.IsNotFound(err) {
+			log.With(zap.Error(err)).With("exportID", *export.Id).Error("Failed to delete export.")
+			errorType = util.GetError(err)
+			csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+			dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+			metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+			return nil, status.Errorf(codes.Internal, "failed to delete export %s: %v", *export.Id, err)
+		}
+	}
+
+	log.Info("Deleting file system.")
+	if err := d.client.FSS().DeleteFileSystem(ctx, fileSystemID); err != nil && !client.IsNotFound(err) {
+		log.With(zap.Error(err)).Error("Failed to delete file system.")
+		errorType = util.GetError(err)
+		csiMetricDimension = util.GetMetricDimensionForComponent(errorType, util.CSIStorageType)
+		dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+		metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+		return nil, status.Errorf(codes.Internal, "failed to delete file system %s: %v", fileSystemID, err)
+	}
+
+	log.Info("File system is deleted.")
+	csiMetricDimension = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	dimensionsMap[metrics.ComponentDimension] = csiMetricDimension
+	metrics.SendMetricData(d.metricPusher, metrics.FSSDelete, time.Since(startTime).Seconds(), dimensionsMap)
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// ControllerPublishVolume is not needed, FSS volumes are mounted over NFS by
+// the node driver.
+func (d *FSSControllerDriver) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ControllerPublishVolume is not supported by FSS")
+}
+
+// ControllerUnpublishVolume is not needed, FSS volumes are unmounted by the
+// node driver.
+func (d *FSSControllerDriver) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ControllerUnpublishVolume is not supported by FSS")
+}
+
+// ValidateVolumeCapabilities checks whether the volume capabilities requested
+// are supported.
+func (d *FSSControllerDriver) ValidateVolumeCapabilities(ctx context.Context, req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
+	}
+
+	if req.VolumeCapabilities == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume Capabilities must be provided")
+	}
+
+	fileSystemID := fileSystemIDFromVolumeID(req.VolumeId)
+	if fileSystemID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Invalid Volume ID provided")
+	}
+
+	if _, err := d.client.FSS().GetFileSystem(ctx, fileSystemID); err != nil {
+		d.logger.With(zap.Error(err)).With("volumeID", req.VolumeId).Error("File system not found.")
+		return nil, status.Errorf(codes.NotFound, "File system %s not found.", fileSystemID)
+	}
+
+	if !d.validateCapabilities(req.VolumeCapabilities) {
+		return &csi.ValidateVolumeCapabilitiesResponse{
+			Message: "requested volume capabilities are not supported",
+		}, nil
+	}
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeCapabilities: req.VolumeCapabilities,
+		},
+	}, nil
+}
+
+// validateCapabilities accepts every access mode of a filesystem volume; an
+// NFS export can be mounted by any number of nodes.
+func (d *FSSControllerDriver) validateCapabilities(caps []*csi.VolumeCapability) bool {
+	for _, cap := range caps {
+		if cap.GetBlock() != nil {
+			d.logger.Errorf("The VolumeCapability isn't supported: raw block access type")
+			return false
+		}
+		if cap.GetAccessMode().GetMode() == csi.VolumeCapability_AccessMode_UNKNOWN {
+			d.logger.Errorf("The VolumeCapability isn't supported: %s", cap.AccessMode)
+			return false
+		}
+	}
+	return true
+}
+
+// ListVolumes returns a list of all requested volumes
+func (d *FSSControllerDriver) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ListVolumes is not supported by FSS")
+}
+
+// GetCapacity returns the capacity of the storage pool
+func (d *FSSControllerDriver) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "GetCapacity is not supported by FSS")
+}
+
+// ControllerGetCapabilities returns the capabilities of the controller service.
+func (d *FSSControllerDriver) ControllerGetCapabilities(ctx context.Context, req *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
+	newCap := func(cap csi.ControllerServiceCapability_RPC_Type) *csi.ControllerServiceCapability {
+		return &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: cap,
+				},
+			},
+		}
+	}
+
+	var caps []*csi.ControllerServiceCapability
+	for _, cap := range []csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+	} {
+		caps = append(caps, newCap(cap))
+	}
+
+	return &csi.ControllerGetCapabilitiesResponse{
+		Capabilities: caps,
+	}, nil
+}
+
+// CreateSnapshot is not supported by FSS
+func (d *FSSControllerDriver) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "CreateSnapshot is not supported by FSS")
+}
+
+// DeleteSnapshot is not supported by FSS
+func (d *FSSControllerDriver) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "DeleteSnapshot is not supported by FSS")
+}
+
+// ListSnapshots is not supported by FSS
+func (d *FSSControllerDriver) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ListSnapshots is not supported by FSS")
+}
+
+// ControllerExpandVolume is not needed, FSS doesn't enforce a quota.
+func (d *FSSControllerDriver) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ControllerExpandVolume is not supported by FSS")
+}
+
+// ControllerGetVolume is not supported by FSS
+func (d *FSSControllerDriver) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ControllerGetVolume is not supported by FSS")
+}
@@ -0,0 +1,362 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"go.uber.org/zap"
+
+	providercfg "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+)
+
+var multiNodeMultiWriterCapability = &csi.VolumeCapability{
+	AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+	AccessMode: &csi.VolumeCapability_AccessMode{
+		Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+	},
+}
+
+// fakeSubdirectories records the subdirectories deleted, keyed by
+// server:exportPath/name, with whether they were archived.
+type fakeSubdirectories struct {
+	deleted map[string]bool
+}
+
+func (s *fakeSubdirectories) Create(ctx context.Context, server, exportPath, name string, mode os.FileMode, uid, gid int) error {
+	if name == "failing-pvc" {
+		return fmt.Errorf("failed to create subdirectory %s", name)
+	}
+	return nil
+}
+
+func (s *fakeSubdirectories) Delete(ctx context.Context, server, exportPath, name string, archive bool) error {
+	s.deleted[fmt.Sprintf("%s:%s/%s", server, exportPath, name)] = archive
+	return nil
+}
+
+func newFSSControllerDriver() *FSSControllerDriver {
+	return &FSSControllerDriver{
+		ControllerDriver: &ControllerDriver{
+			KubeClient: MockKubeClient{},
+			logger:     zap.S(),
+			config:     &providercfg.Config{CompartmentID: "compartment_id"},
+			client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+			util:       &csi_util.Util{Logger: zap.S()},
+		},
+		subdirectories: &fakeSubdirectories{deleted: map[string]bool{}},
+	}
+}
+
+func TestFSSControllerDriver_CreateVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *csi.CreateVolumeRequest
+		want    *csi.CreateVolumeResponse
+		wantErr error
+	}{
+		{
+			name:    "Error for name not provided",
+			req:     &csi.CreateVolumeRequest{},
+			wantErr: errors.New("CreateVolume Name must be provided"),
+		},
+		{
+			name: "Error for raw block volume capability",
+			req: &csi.CreateVolumeRequest{
+				Name: "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{{
+					AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+					AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+				}},
+				Parameters: map[string]string{mountTargetOcid: "mount_target_id"},
+			},
+			wantErr: errors.New("Raw block volumes are not supported by FSS"),
+		},
+		{
+			name: "Error for mount target not provided",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+			},
+			wantErr: errors.New("mountTargetOcid must be provided in the storage class parameters"),
+		},
+		{
+			name: "Error for invalid encryptInTransit parameter",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", encryptInTransit: "maybe"},
+			},
+			wantErr: errors.New("encryptInTransit must be a boolean value"),
+		},
+		{
+			name: "Error for unsupported mount options",
+			req: &csi.CreateVolumeRequest{
+				Name: "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{MountFlags: []string{"proto=udp"}},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+				}},
+				Parameters: map[string]string{mountTargetOcid: "mount_target_id"},
+			},
+			wantErr: errors.New("invalid mount options"),
+		},
+		{
+			name: "Error for unknown mount target",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "unknown_mount_target_id"},
+			},
+			wantErr: errors.New("failed to get active mount target"),
+		},
+		{
+			name: "Error for mount target without private IP",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_without_ip_id"},
+			},
+			wantErr: errors.New("has no private IP or export set associated with it"),
+		},
+		{
+			name: "Create file system and export",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				CapacityRange:      &csi.CapacityRange{RequiredBytes: testMinimumVolumeSizeInBytes},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", encryptInTransit: "true"},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "fs_id:10.0.10.1:/export-path",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					VolumeContext: map[string]string{encryptInTransit: "true"},
+				},
+			},
+		},
+		{
+			name: "Reuse existing file system with the same name",
+			req: &csi.CreateVolumeRequest{
+				Name:               "existing-fs-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id"},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "existing_fs_id:10.0.10.1:/export-path",
+					VolumeContext: map[string]string{},
+				},
+			},
+		},
+		{
+			name: "Error for subdirectory parameter without shared file system",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", archiveOnDelete: "true"},
+			},
+			wantErr: errors.New("archiveOnDelete can only be used with fileSystemOcid"),
+		},
+		{
+			name: "Error for invalid directory mode",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", fileSystemOcid: "shared_fs_id", directoryMode: "0999"},
+			},
+			wantErr: errors.New("directoryMode: invalid permission mode \"0999\""),
+		},
+		{
+			name: "Error for shared file system in another availability domain",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", fileSystemOcid: "other_ad_fs_id"},
+			},
+			wantErr: errors.New("file system is in availability domain US-ASHBURN-AD-2"),
+		},
+		{
+			name: "Error for shared file system not exported on the mount target",
+			req: &csi.CreateVolumeRequest{
+				Name:               "ut-volume",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", fileSystemOcid: "fs_id"},
+			},
+			wantErr: errors.New("file system isn't exported on mount target mount_target_id"),
+		},
+		{
+			name: "Error for failed subdirectory creation",
+			req: &csi.CreateVolumeRequest{
+				Name:               "failing-pvc",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", fileSystemOcid: "shared_fs_id"},
+			},
+			wantErr: errors.New("failed to create subdirectory"),
+		},
+		{
+			name: "Create subdirectory of shared file system",
+			req: &csi.CreateVolumeRequest{
+				Name:               "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				CapacityRange:      &csi.CapacityRange{RequiredBytes: testMinimumVolumeSizeInBytes},
+				Parameters: map[string]string{
+					mountTargetOcid: "mount_target_id",
+					fileSystemOcid:  "shared_fs_id",
+					archiveOnDelete: "true",
+					directoryMode:   "0770",
+					directoryUid:    "1000",
+					directoryGid:    "1000",
+				},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "shared_fs_id:10.0.10.1:/shared:pvc-1:archive",
+					CapacityBytes: testMinimumVolumeSizeInBytes,
+					VolumeContext: map[string]string{},
+				},
+			},
+		},
+		{
+			name: "Create subdirectory of shared file system with export path",
+			req: &csi.CreateVolumeRequest{
+				Name:               "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{multiNodeMultiWriterCapability},
+				Parameters:         map[string]string{mountTargetOcid: "mount_target_id", fileSystemOcid: "fs_id", exportPath: "/apps"},
+			},
+			want: &csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      "fs_id:10.0.10.1:/apps:pvc-1",
+					VolumeContext: map[string]string{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newFSSControllerDriver()
+			got, err := d.CreateVolume(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FSSControllerDriver.CreateVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSSControllerDriver_DeleteVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *csi.DeleteVolumeRequest
+		want    *csi.DeleteVolumeResponse
+		wantErr error
+	}{
+		{
+			name:    "Error for volume ID not provided",
+			req:     &csi.DeleteVolumeRequest{},
+			wantErr: errors.New("DeleteVolume Volume ID must be provided"),
+		},
+		{
+			name:    "Error for malformed volume ID",
+			req:     &csi.DeleteVolumeRequest{VolumeId: "fs_id"},
+			wantErr: errors.New("Invalid Volume ID provided"),
+		},
+		{
+			name:    "Error for failed file system lookup",
+			req:     &csi.DeleteVolumeRequest{VolumeId: "unknown_fs_id:10.0.10.1:/export-path"},
+			wantErr: errors.New("failed to get file system"),
+		},
+		{
+			name:    "Error for failed file system deletion",
+			req:     &csi.DeleteVolumeRequest{VolumeId: "undeletable_fs_id:10.0.10.1:/export-path"},
+			wantErr: errors.New("failed to delete file system"),
+		},
+		{
+			name: "Already deleted file system",
+			req:  &csi.DeleteVolumeRequest{VolumeId: "deleted_fs_id:10.0.10.1:/export-path"},
+			want: &csi.DeleteVolumeResponse{},
+		},
+		{
+			name: "Delete export and file system",
+			req:  &csi.DeleteVolumeRequest{VolumeId: "fs_id:10.0.10.1:/export-path"},
+			want: &csi.DeleteVolumeResponse{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newFSSControllerDriver()
+			got, err := d.DeleteVolume(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FSSControllerDriver.DeleteVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSSControllerDriver_DeleteSubdirectoryVolume(t *testing.T) {
+	tests := map[string]struct {
+		volumeID string
+		deleted  map[string]bool
+	}{
+		"subdirectory is archived": {
+			volumeID: "shared_fs_id:10.0.10.1:/shared:pvc-1:archive",
+			deleted:  map[string]bool{"10.0.10.1:/shared/pvc-1": true},
+		},
+		"subdirectory is removed": {
+			volumeID: "shared_fs_id:10.0.10.1:/shared:pvc-1",
+			deleted:  map[string]bool{"10.0.10.1:/shared/pvc-1": false},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := newFSSControllerDriver()
+			if _, err := d.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: tt.volumeID}); err != nil {
+				t.Fatalf("FSSControllerDriver.DeleteVolume() => unexpected error: %v", err)
+			}
+			if deleted := d.subdirectories.(*fakeSubdirectories).deleted; !reflect.DeepEqual(deleted, tt.deleted) {
+				t.Errorf("FSSControllerDriver.DeleteVolume() deleted %v, want %v", deleted, tt.deleted)
+			}
+		})
+	}
+}
+
+func TestValidateVolumeId(t *testing.T) {
+	tests := map[string]struct {
+		mountTargetIP string
+		exportPath    string
+	}{
+		"fs_id:10.0.10.1:/export-path":                {"10.0.10.1", "/export-path"},
+		"fs_id:10.0.10.1:/shared:pvc-1":               {"10.0.10.1", "/shared/pvc-1"},
+		"fs_id:10.0.10.1:/shared:pvc-1:archive":       {"10.0.10.1", "/shared/pvc-1"},
+		"fs_id:10.0.10.1:/:pvc-1":                     {"10.0.10.1", "/pvc-1"},
+		"fs_id:10.0.10.1:/shared:..":                  {"", ""},
+		"fs_id:mount-target:/export-path":             {"", ""},
+		"fs_id:10.0.10.1:/shared:pvc-1:extra":         {"", ""},
+		"fs_id:10.0.10.1:/shared:pvc-1:archive:extra": {"", ""},
+	}
+	for volumeID, tt := range tests {
+		mountTargetIP, exportPath := validateVolumeId(volumeID)
+		if mountTargetIP != tt.mountTargetIP || exportPath != tt.exportPath {
+			t.Errorf("validateVolumeId(%s) = %q, %q, want %q, %q", volumeID, mountTargetIP, exportPath, tt.mountTargetIP, tt.exportPath)
+		}
+	}
+}
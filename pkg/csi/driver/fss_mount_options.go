@@ -0,0 +1,91 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fssConflictingMountOptions pairs the NFS mount options that contradict each
+// other.
+var fssConflictingMountOptions = map[string]string{
+	"hard":     "soft",
+	"ro":       "rw",
+	"sync":     "async",
+	"ac":       "noac",
+	"lock":     "nolock",
+	"resvport": "noresvport",
+	"cto":      "nocto",
+}
+
+// fssRejectedMountOptions are the mount options that can't be used with FSS
+// or that would break the way the driver stages and publishes the volume.
+var fssRejectedMountOptions = map[string]string{
+	"bind":    "the driver bind mounts the staged volume itself",
+	"rbind":   "the driver bind mounts the staged volume itself",
+	"remount": "remounting is not supported",
+	"udp":     "FSS only supports NFS over TCP",
+	"fips":    "it is set by the driver when in-transit encryption is enabled on a FIPS node",
+}
+
+// validateFSSMountOptions rejects the mount options that FSS doesn't support,
+// that conflict with each other or that the driver manages itself.
+func validateFSSMountOptions(options []string) error {
+	values := make(map[string]string)
+	for _, option := range options {
+		key, value := splitMountOption(option)
+		if key == "" {
+			return fmt.Errorf("invalid empty mount option")
+		}
+		if reason, ok := fssRejectedMountOptions[key]; ok {
+			return fmt.Errorf("mount option %q is not supported: %s", option, reason)
+		}
+
+		switch key {
+		case "nfsvers", "vers":
+			if value != "3" {
+				return fmt.Errorf("mount option %q is not supported: FSS only supports NFS version 3", option)
+			}
+		case "proto", "mountproto":
+			if value != "tcp" {
+				return fmt.Errorf("mount option %q is not supported: FSS only supports NFS over TCP", option)
+			}
+		}
+
+		if previous, ok := values[key]; ok && previous != value {
+			return fmt.Errorf("mount option %q conflicts with %s=%s", option, key, previous)
+		}
+		values[key] = value
+	}
+
+	for option, opposite := range fssConflictingMountOptions {
+		_, hasOption := values[option]
+		_, hasOpposite := values[opposite]
+		if hasOption && hasOpposite {
+			return fmt.Errorf("mount option %q conflicts with %q", option, opposite)
+		}
+	}
+	return nil
+}
+
+// mergeMountOptions appends the user's mount options to the driver's own
+// options, dropping duplicates.
+func mergeMountOptions(driverOptions, userOptions []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, option := range append(append([]string{}, driverOptions...), userOptions...) {
+		if seen[option] {
+			continue
+		}
+		seen[option] = true
+		merged = append(merged, option)
+	}
+	return merged
+}
+
+func splitMountOption(option string) (string, string) {
+	option = strings.TrimSpace(option)
+	if i := strings.Index(option, "="); i >= 0 {
+		return option[:i], option[i+1:]
+	}
+	return option, ""
+}
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+)
+
+func TestValidateFSSMountOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []string
+		wantErr bool
+	}{
+		{
+			name: "No mount options",
+		},
+		{
+			name:    "Supported NFS mount options",
+			options: []string{"nfsvers=3", "rsize=1048576", "wsize=1048576", "nconnect=4", "hard", "timeo=600", "noresvport"},
+		},
+		{
+			name:    "Repeated mount option",
+			options: []string{"hard", "hard", "vers=3", "vers=3"},
+		},
+		{
+			name:    "Error for unsupported NFS version",
+			options: []string{"nfsvers=4.1"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for UDP",
+			options: []string{"proto=udp"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for conflicting mount options",
+			options: []string{"hard", "soft"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for conflicting mount option values",
+			options: []string{"timeo=600", "timeo=10"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for mount option managed by the driver",
+			options: []string{"fips"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for bind mount option",
+			options: []string{"bind"},
+			wantErr: true,
+		},
+		{
+			name:    "Error for empty mount option",
+			options: []string{""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFSSMountOptions(tt.options); (err != nil) != tt.wantErr {
+				t.Errorf("validateFSSMountOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeMountOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		driverOptions []string
+		userOptions   []string
+		want          []string
+	}{
+		{
+			name: "No mount options",
+		},
+		{
+			name:        "User mount options only",
+			userOptions: []string{"nfsvers=3", "hard"},
+			want:        []string{"nfsvers=3", "hard"},
+		},
+		{
+			name:          "Driver and user mount options",
+			driverOptions: []string{"fips"},
+			userOptions:   []string{"nconnect=4", "nconnect=4"},
+			want:          []string{"fips", "nconnect=4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeMountOptions(tt.driverOptions, tt.userOptions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeMountOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSSNodeDriver_NodeStageVolumeInvalidMountOptions(t *testing.T) {
+	d := FSSNodeDriver{NodeDriver: NodeDriver{
+		logger:      zap.S(),
+		util:        &csi_util.Util{Logger: zap.S()},
+		volumeLocks: csi_util.NewVolumeLocks(),
+	}}
+	_, err := d.NodeStageVolume(context.Background(), &csi.NodeStageVolumeRequest{
+		VolumeId:          "fs_id:10.0.10.1:/export-path",
+		StagingTargetPath: "/var/lib/kubelet/plugins/fss/staging",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{MountFlags: []string{"nfsvers=4"}},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("NodeStageVolume() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/nfs"
 )
 
 const (
@@ -48,6 +50,11 @@ func (d FSSNodeDriver) NodeStageVolume(ctx context.Context, req *csi.NodeStageVo
 	if accessType != nil && accessType.FsType != "" {
 		fsType = accessType.FsType
 	}
+	mountFlags := accessType.GetMountFlags()
+	if err := validateFSSMountOptions(mountFlags); err != nil {
+		logger.With(zap.Error(err)).Error("Invalid mount options.")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	encryptInTransit, err := isInTransitEncryptionEnabled(req.VolumeContext)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "EncryptInTransit must be a boolean value")
@@ -80,6 +87,10 @@ func (d FSSNodeDriver) NodeStageVolume(ctx context.Context, req *csi.NodeStageVo
 		}
 	}
 
+	options = mergeMountOptions(options, mountFlags)
+	logger = logger.With("fsType", fsType, "mountOptions", options)
+	logger.Info("Effective mount options for staging the volume.")
+
 	if acquired := d.volumeLocks.TryAcquire(req.VolumeId); !acquired {
 		logger.Error("Could not acquire lock for NodeStageVolume.")
 		return nil, status.Errorf(codes.Aborted, volumeOperationAlreadyExistsFmt, req.VolumeId)
@@ -116,7 +127,7 @@ func (d FSSNodeDriver) NodeStageVolume(ctx context.Context, req *csi.NodeStageVo
 	err = mounter.Mount(source, targetPath, fsType, options)
 	if err != nil {
 		logger.With(zap.Error(err)).Error("failed to mount volume to staging target path.")
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Errorf(codes.Internal, "failed to mount %s with options %v: %v", source, options, err)
 	}
 	logger.With("mountTarget", mountTargetIP, "exportPath", exportPath, "StagingTargetPath", targetPath).
 		Info("Mounting the volume to staging target path is completed.")
@@ -141,18 +152,38 @@ func isMountPoint(mounter mount.Interface, path string) (bool, error) {
 	return !ok, nil
 }
 
+// validateVolumeId returns the mount target IP and the path to mount from a
+// volume ID. The volumes that are subdirectories of a shared file system have
+// the subdirectory as fourth part of their ID, and are mounted from it. The
+// subdirectories that are archived on delete have archiveOnDeleteVolumeIDSuffix
+// as fifth part.
 func validateVolumeId(id string) (string, string) {
 	volumeHandler := strings.Split(id, ":")
 	const numOfParamsFromVolumeHandle = 3
+	const numOfParamsFromSubdirectoryVolumeHandle = 4
+	const numOfParamsFromArchivedSubdirectoryVolumeHandle = 5
 	const mountTargetIPAddress = 1
 	const fsExportPath = 2
+	const fsSubdirectory = 3
+	const fsArchiveOnDelete = 4
+	switch len(volumeHandler) {
+	case numOfParamsFromVolumeHandle, numOfParamsFromSubdirectoryVolumeHandle, numOfParamsFromArchivedSubdirectoryVolumeHandle:
+	default:
+		return "", ""
+	}
+	if net.ParseIP(volumeHandler[mountTargetIPAddress]) == nil {
+		return "", ""
+	}
 	if len(volumeHandler) == numOfParamsFromVolumeHandle {
-		if net.ParseIP(volumeHandler[mountTargetIPAddress]) != nil {
-			return volumeHandler[mountTargetIPAddress], volumeHandler[fsExportPath]
-		}
+		return volumeHandler[mountTargetIPAddress], volumeHandler[fsExportPath]
+	}
+	if len(volumeHandler) == numOfParamsFromArchivedSubdirectoryVolumeHandle && volumeHandler[fsArchiveOnDelete] != archiveOnDeleteVolumeIDSuffix {
 		return "", ""
 	}
-	return "", ""
+	if volumeHandler[fsExportPath] == "" || nfs.ValidateName(volumeHandler[fsSubdirectory]) != nil {
+		return "", ""
+	}
+	return volumeHandler[mountTargetIPAddress], path.Join(volumeHandler[fsExportPath], volumeHandler[fsSubdirectory])
 }
 
 // NodePublishVolume mounts the volume to the target path
@@ -354,18 +385,25 @@ func (d FSSNodeDriver) unmountAndCleanup(logger *zap.SugaredLogger, targetPath s
 
 // NodeGetCapabilities returns the supported capabilities of the node server
 func (d FSSNodeDriver) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	nscap := &csi.NodeServiceCapability{
-		Type: &csi.NodeServiceCapability_Rpc{
-			Rpc: &csi.NodeServiceCapability_RPC{
-				Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+	var nscaps []*csi.NodeServiceCapability
+	nodeCaps := []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+	}
+	for _, nodeCap := range nodeCaps {
+		c := &csi.NodeServiceCapability{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: nodeCap,
+				},
 			},
-		},
+		}
+		nscaps = append(nscaps, c)
 	}
 
 	return &csi.NodeGetCapabilitiesResponse{
-		Capabilities: []*csi.NodeServiceCapability{
-			nscap,
-		},
+		Capabilities: nscaps,
 	}, nil
 }
 
@@ -392,7 +430,16 @@ func (d FSSNodeDriver) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequ
 
 // NodeGetVolumeStats return the stats of the volume
 func (d FSSNodeDriver) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "NodeGetVolumeStats is not supported yet")
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeGetVolumeStats Volume ID must be provided")
+	}
+
+	if req.VolumePath == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeGetVolumeStats Volume Path must be provided")
+	}
+
+	logger := d.logger.With("volumeID", req.VolumeId, "volumePath", req.VolumePath)
+	return getVolumeStats(logger, req.VolumePath)
 }
 
 //NodeExpandVolume returns the expand of the volume
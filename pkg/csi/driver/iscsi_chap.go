@@ -0,0 +1,196 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v50/core"
+	kubeAPI "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
+)
+
+const (
+	// iscsiChap is the StorageClass parameter that requires CHAP
+	// authentication for the iSCSI attachments of the volume.
+	iscsiChap = "iscsi-chap"
+
+	// iscsiChapSecretName is the volume context key of the name of the secret
+	// the CHAP credentials of the attachments of the volume are stored in.
+	iscsiChapSecretName = "iscsi-chap-secret-name"
+
+	// iscsiChapSecretNamespace is the namespace of the CHAP secrets. It has to
+	// be set as the node-stage-secret-namespace of the StorageClass.
+	iscsiChapSecretNamespace = "kube-system"
+
+	// iscsiChapSecretSuffix is appended to the PV name to get the name of its
+	// CHAP secret, i.e. the node-stage-secret-name of the StorageClass is
+	// ${pv.name}-iscsi-chap.
+	iscsiChapSecretSuffix = "-iscsi-chap"
+
+	// iscsiChapSecretLabel marks the CHAP secrets created by the driver, only
+	// those are deleted with their volume.
+	iscsiChapSecretLabel = "app.kubernetes.io/managed-by"
+)
+
+// getISCSIChapSecretName returns the name of the CHAP secret of the volume.
+func getISCSIChapSecretName(volumeName string) string {
+	return volumeName + iscsiChapSecretSuffix
+}
+
+// chapUsernameKey and chapPasswordKey return the keys of the CHAP credentials
+// of the node's attachment in the secret. A shareable volume has an attachment,
+// and credentials, per node.
+func chapUsernameKey(nodeID string) string {
+	return nodeID + ".username"
+}
+
+func chapPasswordKey(nodeID string) string {
+	return nodeID + ".password"
+}
+
+// storeISCSIChapCredentials writes the CHAP credentials of the node's iSCSI
+// attachment into the named secret, creating the secret if needed.
+func storeISCSIChapCredentials(ctx context.Context, kubeClient kubernetes.Interface, secretName, nodeID string, attachment core.VolumeAttachment) error {
+	iSCSIAttachment, ok := attachment.(core.IScsiVolumeAttachment)
+	if !ok {
+		return fmt.Errorf("CHAP authentication is only supported for iSCSI attachments")
+	}
+	if iSCSIAttachment.ChapUsername == nil || iSCSIAttachment.ChapSecret == nil {
+		return fmt.Errorf("attachment %s has no CHAP credentials, it was not created with CHAP authentication", *iSCSIAttachment.Id)
+	}
+	if secretName == "" {
+		return fmt.Errorf("volume context has no %s", iscsiChapSecretName)
+	}
+
+	secrets := kubeClient.CoreV1().Secrets(iscsiChapSecretNamespace)
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret = &kubeAPI.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: iscsiChapSecretNamespace,
+				Labels:    map[string]string{iscsiChapSecretLabel: BlockVolumeDriverName},
+			},
+			Type: kubeAPI.SecretTypeOpaque,
+			Data: map[string][]byte{
+				chapUsernameKey(nodeID): []byte(*iSCSIAttachment.ChapUsername),
+				chapPasswordKey(nodeID): []byte(*iSCSIAttachment.ChapSecret),
+			},
+		}
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[chapUsernameKey(nodeID)] = []byte(*iSCSIAttachment.ChapUsername)
+	secret.Data[chapPasswordKey(nodeID)] = []byte(*iSCSIAttachment.ChapSecret)
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// removeISCSIChapCredentials removes the CHAP credentials of the node's
+// attachment from the named secret, if any.
+func removeISCSIChapCredentials(ctx context.Context, kubeClient kubernetes.Interface, secretName, nodeID string) error {
+	secrets := kubeClient.CoreV1().Secrets(iscsiChapSecretNamespace)
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, hasUsername := secret.Data[chapUsernameKey(nodeID)]
+	_, hasPassword := secret.Data[chapPasswordKey(nodeID)]
+	if !hasUsername && !hasPassword {
+		return nil
+	}
+	delete(secret.Data, chapUsernameKey(nodeID))
+	delete(secret.Data, chapPasswordKey(nodeID))
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// deleteISCSIChapSecret deletes the named CHAP secret if it was created by the
+// driver.
+func deleteISCSIChapSecret(ctx context.Context, kubeClient kubernetes.Interface, secretName string) error {
+	secrets := kubeClient.CoreV1().Secrets(iscsiChapSecretNamespace)
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if secret.Labels[iscsiChapSecretLabel] != BlockVolumeDriverName {
+		return nil
+	}
+
+	err = secrets.Delete(ctx, secretName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// getVolumeISCSIChapSecretName returns the name of the CHAP secret of the
+// volume. The secret is named after the PV the volume was created for, which
+// is the display name of the volume. It returns "" if the volume is gone.
+func (d *ControllerDriver) getVolumeISCSIChapSecretName(ctx context.Context, volumeID string) (string, error) {
+	volume, err := d.client.BlockStorage().GetVolume(ctx, volumeID)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if volume == nil || volume.DisplayName == nil {
+		return "", nil
+	}
+	return getISCSIChapSecretName(*volume.DisplayName), nil
+}
+
+// removeNodeISCSIChapCredentials removes the CHAP credentials of the node
+// from the secret of the volume once the node's attachment is detached.
+func (d *ControllerDriver) removeNodeISCSIChapCredentials(ctx context.Context, volumeID, nodeID string, attachment core.VolumeAttachment) error {
+	iSCSIAttachment, ok := attachment.(core.IScsiVolumeAttachment)
+	if !ok || iSCSIAttachment.ChapUsername == nil {
+		return nil
+	}
+	secretName, err := d.getVolumeISCSIChapSecretName(ctx, volumeID)
+	if err != nil || secretName == "" {
+		return err
+	}
+	return removeISCSIChapCredentials(ctx, d.KubeClient, secretName, nodeID)
+}
+
+// deleteVolumeISCSIChapSecret deletes the CHAP secret of the volume. It has
+// to be called before the volume is deleted, while its name can be looked up.
+func (d *ControllerDriver) deleteVolumeISCSIChapSecret(ctx context.Context, volumeID string) error {
+	secretName, err := d.getVolumeISCSIChapSecretName(ctx, volumeID)
+	if err != nil || secretName == "" {
+		return err
+	}
+	return deleteISCSIChapSecret(ctx, d.KubeClient, secretName)
+}
+
+// getISCSIChapCredentials returns the CHAP credentials of the node from the
+// node stage secrets.
+func getISCSIChapCredentials(secrets map[string]string, nodeID string) (string, string, error) {
+	username, password := secrets[chapUsernameKey(nodeID)], secrets[chapPasswordKey(nodeID)]
+	if username == "" || password == "" {
+		return "", "", fmt.Errorf("the node stage secrets have no CHAP credentials for node %s, "+
+			"check that csi.storage.k8s.io/node-stage-secret-name and csi.storage.k8s.io/node-stage-secret-namespace "+
+			"are set to ${pv.name}%s and %s in the StorageClass", nodeID, iscsiChapSecretSuffix, iscsiChapSecretNamespace)
+	}
+	return username, password, nil
+}
@@ -0,0 +1,267 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v50/common"
+	"github.com/oracle/oci-go-sdk/v50/core"
+	"go.uber.org/zap"
+	kubeAPI "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// mockSecretsKubeClient keeps the secrets it is given in memory.
+type mockSecretsKubeClient struct {
+	kubernetes.Interface
+	secrets map[string]*kubeAPI.Secret
+}
+
+type mockSecretsCoreV1 struct {
+	corev1client.CoreV1Interface
+	client *mockSecretsKubeClient
+}
+
+type mockSecrets struct {
+	corev1client.SecretInterface
+	client *mockSecretsKubeClient
+}
+
+func (c *mockSecretsKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return mockSecretsCoreV1{client: c}
+}
+
+func (c mockSecretsCoreV1) Secrets(namespace string) corev1client.SecretInterface {
+	return mockSecrets{client: c.client}
+}
+
+func (s mockSecrets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*kubeAPI.Secret, error) {
+	secret, ok := s.client.secrets[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return secret.DeepCopy(), nil
+}
+
+func (s mockSecrets) Create(ctx context.Context, secret *kubeAPI.Secret, opts metav1.CreateOptions) (*kubeAPI.Secret, error) {
+	s.client.secrets[secret.Name] = secret.DeepCopy()
+	return secret, nil
+}
+
+func (s mockSecrets) Update(ctx context.Context, secret *kubeAPI.Secret, opts metav1.UpdateOptions) (*kubeAPI.Secret, error) {
+	s.client.secrets[secret.Name] = secret.DeepCopy()
+	return secret, nil
+}
+
+func (s mockSecrets) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if _, ok := s.client.secrets[name]; !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	delete(s.client.secrets, name)
+	return nil
+}
+
+// chapSecret returns a CHAP secret created by the driver with the credentials
+// of the given nodes.
+func chapSecret(name string, nodeIDs ...string) *kubeAPI.Secret {
+	secret := &kubeAPI.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: iscsiChapSecretNamespace,
+			Labels:    map[string]string{iscsiChapSecretLabel: BlockVolumeDriverName},
+		},
+		Data: map[string][]byte{},
+	}
+	for _, nodeID := range nodeIDs {
+		secret.Data[chapUsernameKey(nodeID)] = []byte(nodeID + "-user")
+		secret.Data[chapPasswordKey(nodeID)] = []byte(nodeID + "-secret")
+	}
+	return secret
+}
+
+func TestStoreISCSIChapCredentials(t *testing.T) {
+	kubeClient := &mockSecretsKubeClient{secrets: map[string]*kubeAPI.Secret{}}
+	attachment := func(username, secret string) core.VolumeAttachment {
+		return core.IScsiVolumeAttachment{
+			Id:           common.String("attachment_id"),
+			ChapUsername: common.String(username),
+			ChapSecret:   common.String(secret),
+		}
+	}
+
+	if err := storeISCSIChapCredentials(context.Background(), kubeClient, "pv-iscsi-chap", "node1", attachment("user1", "secret1")); err != nil {
+		t.Fatalf("storeISCSIChapCredentials() => unexpected error: %v", err)
+	}
+	if err := storeISCSIChapCredentials(context.Background(), kubeClient, "pv-iscsi-chap", "node2", attachment("user2", "secret2")); err != nil {
+		t.Fatalf("storeISCSIChapCredentials() => unexpected error: %v", err)
+	}
+
+	secret, ok := kubeClient.secrets["pv-iscsi-chap"]
+	if !ok {
+		t.Fatalf("expected secret pv-iscsi-chap to be created")
+	}
+	if secret.Namespace != iscsiChapSecretNamespace {
+		t.Errorf("expected secret in namespace %s, got %s", iscsiChapSecretNamespace, secret.Namespace)
+	}
+	if secret.Labels[iscsiChapSecretLabel] != BlockVolumeDriverName {
+		t.Errorf("expected secret labelled %s=%s, got %v", iscsiChapSecretLabel, BlockVolumeDriverName, secret.Labels)
+	}
+	expected := map[string][]byte{
+		"node1.username": []byte("user1"),
+		"node1.password": []byte("secret1"),
+		"node2.username": []byte("user2"),
+		"node2.password": []byte("secret2"),
+	}
+	if !reflect.DeepEqual(secret.Data, expected) {
+		t.Errorf("storeISCSIChapCredentials() stored %q, expected %q", secret.Data, expected)
+	}
+
+	withoutChap := core.IScsiVolumeAttachment{Id: common.String("attachment_id")}
+	if err := storeISCSIChapCredentials(context.Background(), kubeClient, "pv-iscsi-chap", "node1", withoutChap); err == nil {
+		t.Errorf("storeISCSIChapCredentials() => expected an error for an attachment without CHAP credentials")
+	}
+}
+
+func TestRemoveISCSIChapCredentials(t *testing.T) {
+	tests := map[string]struct {
+		secrets map[string]*kubeAPI.Secret
+		nodeID  string
+		want    map[string][]byte
+	}{
+		"Credentials of the node are removed": {
+			secrets: map[string]*kubeAPI.Secret{"pv-iscsi-chap": chapSecret("pv-iscsi-chap", "node1", "node2")},
+			nodeID:  "node1",
+			want:    chapSecret("pv-iscsi-chap", "node2").Data,
+		},
+		"Node without credentials": {
+			secrets: map[string]*kubeAPI.Secret{"pv-iscsi-chap": chapSecret("pv-iscsi-chap", "node2")},
+			nodeID:  "node1",
+			want:    chapSecret("pv-iscsi-chap", "node2").Data,
+		},
+		"Missing secret": {
+			secrets: map[string]*kubeAPI.Secret{},
+			nodeID:  "node1",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := &mockSecretsKubeClient{secrets: tt.secrets}
+			if err := removeISCSIChapCredentials(context.Background(), kubeClient, "pv-iscsi-chap", tt.nodeID); err != nil {
+				t.Fatalf("removeISCSIChapCredentials() => unexpected error: %v", err)
+			}
+			var got map[string][]byte
+			if secret, ok := kubeClient.secrets["pv-iscsi-chap"]; ok {
+				got = secret.Data
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removeISCSIChapCredentials() left %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteISCSIChapSecret(t *testing.T) {
+	userSecret := chapSecret("pv-iscsi-chap", "node1")
+	userSecret.Labels = nil
+
+	tests := map[string]struct {
+		secret      *kubeAPI.Secret
+		wantDeleted bool
+	}{
+		"Secret created by the driver": {
+			secret:      chapSecret("pv-iscsi-chap", "node1"),
+			wantDeleted: true,
+		},
+		"Secret not created by the driver": {
+			secret:      userSecret,
+			wantDeleted: false,
+		},
+		"Missing secret": {
+			wantDeleted: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := &mockSecretsKubeClient{secrets: map[string]*kubeAPI.Secret{}}
+			if tt.secret != nil {
+				kubeClient.secrets[tt.secret.Name] = tt.secret
+			}
+			if err := deleteISCSIChapSecret(context.Background(), kubeClient, "pv-iscsi-chap"); err != nil {
+				t.Fatalf("deleteISCSIChapSecret() => unexpected error: %v", err)
+			}
+			if _, ok := kubeClient.secrets["pv-iscsi-chap"]; ok == tt.wantDeleted {
+				t.Errorf("deleteISCSIChapSecret() deleted = %t, expected %t", !ok, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_removeNodeISCSIChapCredentials(t *testing.T) {
+	tests := map[string]struct {
+		volumeID   string
+		attachment core.VolumeAttachment
+		want       map[string][]byte
+	}{
+		"CHAP attachment": {
+			volumeID: "chap_volume_id",
+			attachment: core.IScsiVolumeAttachment{
+				Id:           common.String("attachment_id"),
+				ChapUsername: common.String("node1-user"),
+				ChapSecret:   common.String("node1-secret"),
+			},
+			want: chapSecret("chap-pv-iscsi-chap", "node2").Data,
+		},
+		"Attachment without CHAP": {
+			volumeID:   "chap_volume_id",
+			attachment: core.IScsiVolumeAttachment{Id: common.String("attachment_id")},
+			want:       chapSecret("chap-pv-iscsi-chap", "node1", "node2").Data,
+		},
+		"Paravirtualized attachment": {
+			volumeID:   "chap_volume_id",
+			attachment: core.ParavirtualizedVolumeAttachment{Id: common.String("attachment_id")},
+			want:       chapSecret("chap-pv-iscsi-chap", "node1", "node2").Data,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := &mockSecretsKubeClient{secrets: map[string]*kubeAPI.Secret{
+				"chap-pv-iscsi-chap": chapSecret("chap-pv-iscsi-chap", "node1", "node2"),
+			}}
+			d := &ControllerDriver{
+				KubeClient: kubeClient,
+				logger:     zap.S(),
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+			}
+			if err := d.removeNodeISCSIChapCredentials(context.Background(), tt.volumeID, "node1", tt.attachment); err != nil {
+				t.Fatalf("removeNodeISCSIChapCredentials() => unexpected error: %v", err)
+			}
+			if got := kubeClient.secrets["chap-pv-iscsi-chap"].Data; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removeNodeISCSIChapCredentials() left %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetISCSIChapCredentials(t *testing.T) {
+	secrets := map[string]string{
+		"node1.username": "user1",
+		"node1.password": "secret1",
+	}
+
+	username, password, err := getISCSIChapCredentials(secrets, "node1")
+	if err != nil {
+		t.Fatalf("getISCSIChapCredentials() => unexpected error: %v", err)
+	}
+	if username != "user1" || password != "secret1" {
+		t.Errorf("getISCSIChapCredentials() => %s, %s, expected user1, secret1", username, password)
+	}
+
+	if _, _, err := getISCSIChapCredentials(secrets, "node2"); err == nil {
+		t.Errorf("getISCSIChapCredentials() => expected an error for a node without credentials")
+	}
+}
@@ -0,0 +1,70 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// controllerLeaseName is the lease electing the replica of the block
+	// volume controller driver that runs its background tasks.
+	controllerLeaseName = "blockvolume-csi-oraclecloud-com-controller"
+
+	// defaultLeaseNamespace is used when the POD_NAMESPACE environment
+	// variable isn't set, it matches the leader election namespace of the
+	// CSI sidecars.
+	defaultLeaseNamespace = "kube-system"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 5 * time.Second
+)
+
+// runWithLeaderElection blocks until this replica of the controller driver is
+// elected leader and then calls run. The process exits when the leadership is
+// lost, so that the tasks never run in two replicas at once.
+func (d *ControllerDriver) runWithLeaderElection(run func(stopCh <-chan struct{})) {
+	namespace, ok := os.LookupEnv("POD_NAMESPACE")
+	if !ok {
+		namespace = defaultLeaseNamespace
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		d.logger.With("error", err).Fatal("Failed to get hostname for leader election.")
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      controllerLeaseName,
+			Namespace: namespace,
+		},
+		Client: d.KubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	d.logger.With("lease", namespace+"/"+controllerLeaseName, "identity", identity).Info("Starting leader election.")
+	leaderelection.RunOrDie(context.Background(), leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				d.logger.With("identity", identity).Info("Became leader, starting controller tasks.")
+				run(ctx.Done())
+			},
+			OnStoppedLeading: func() {
+				d.logger.With("identity", identity).Fatal("Lost leader election, exiting.")
+			},
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// luksEncryption is the StorageClass parameter, and volume context key,
+	// that encrypts the volume on the nodes with LUKS.
+	luksEncryption = "luks-encryption"
+
+	// luksPassphraseKey is the key of the LUKS passphrase in the node stage
+	// secret.
+	luksPassphraseKey = "passphrase"
+
+	luksMapperPrefix = "luks-"
+)
+
+// luksMapperName returns the name of the LUKS mapper device of the volume, made
+// of the unique part of its OCID.
+func luksMapperName(volumeID string) string {
+	return luksMapperPrefix + volumeID[strings.LastIndex(volumeID, ".")+1:]
+}
+
+// getLUKSPassphrase returns the LUKS passphrase from the node stage secrets.
+func getLUKSPassphrase(secrets map[string]string) (string, error) {
+	passphrase := secrets[luksPassphraseKey]
+	if passphrase == "" {
+		return "", fmt.Errorf("the node stage secrets have no %s for the LUKS encryption of the volume, "+
+			"check that csi.storage.k8s.io/node-stage-secret-name and csi.storage.k8s.io/node-stage-secret-namespace "+
+			"are set in the StorageClass", luksPassphraseKey)
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,24 @@
+package driver
+
+import "testing"
+
+func TestLUKSMapperName(t *testing.T) {
+	name := luksMapperName("ocid1.volume.oc1.iad.abuwcljrexample")
+	if name != "luks-abuwcljrexample" {
+		t.Errorf("luksMapperName() => %s, expected luks-abuwcljrexample", name)
+	}
+}
+
+func TestGetLUKSPassphrase(t *testing.T) {
+	passphrase, err := getLUKSPassphrase(map[string]string{luksPassphraseKey: "secret"})
+	if err != nil {
+		t.Fatalf("getLUKSPassphrase() => unexpected error: %v", err)
+	}
+	if passphrase != "secret" {
+		t.Errorf("getLUKSPassphrase() => %s, expected secret", passphrase)
+	}
+
+	if _, err := getLUKSPassphrase(map[string]string{}); err == nil {
+		t.Errorf("getLUKSPassphrase() => expected an error for secrets without a passphrase")
+	}
+}
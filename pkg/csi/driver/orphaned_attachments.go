@@ -0,0 +1,218 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v50/core"
+	"go.uber.org/zap"
+	kubeAPI "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
+)
+
+const (
+	orphanedAttachmentReconcileInterval = time.Minute
+
+	orphanedAttachmentReason = "OrphanedVolumeAttachment"
+	orphanedDetachReason     = "VolumeDetachedFromDeletedNode"
+	orphanedDetachFailReason = "FailedDetachFromDeletedNode"
+)
+
+// orphanedAttachmentReconciler detaches the block volumes of the cluster from
+// instances that are no longer nodes of the cluster. The attachments are left
+// behind when a node is deleted or its instance terminated before Kubernetes
+// unpublished the volumes, and keep the volumes from being attached to other
+// nodes.
+type orphanedAttachmentReconciler struct {
+	driver      *ControllerDriver
+	gracePeriod time.Duration
+	recorder    record.EventRecorder
+
+	// orphanedSince is when each orphaned attachment, keyed by OCID, was
+	// first seen.
+	orphanedSince map[string]time.Time
+	now           func() time.Time
+}
+
+func newOrphanedAttachmentReconciler(d *ControllerDriver, gracePeriod time.Duration, recorder record.EventRecorder) *orphanedAttachmentReconciler {
+	return &orphanedAttachmentReconciler{
+		driver:        d,
+		gracePeriod:   gracePeriod,
+		recorder:      recorder,
+		orphanedSince: make(map[string]time.Time),
+		now:           time.Now,
+	}
+}
+
+// runOrphanedAttachmentReconciler periodically detaches the volumes attached
+// to deleted or terminated nodes until stopCh is closed.
+func (d *ControllerDriver) runOrphanedAttachmentReconciler(gracePeriod time.Duration, stopCh <-chan struct{}) {
+	r := newOrphanedAttachmentReconciler(d, gracePeriod, d.newEventRecorder())
+	d.logger.With("interval", orphanedAttachmentReconcileInterval, "gracePeriod", gracePeriod).Info("Starting orphaned volume attachment reconciler.")
+	wait.Until(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), orphanedAttachmentReconcileInterval)
+		defer cancel()
+		if err := r.reconcile(ctx); err != nil {
+			d.logger.With(zap.Error(err)).Error("Failed to reconcile orphaned volume attachments.")
+		}
+	}, orphanedAttachmentReconcileInterval, stopCh)
+}
+
+// reconcile looks up the OCI attachments of the block volume PVs and detaches
+// the ones that have been orphaned for longer than the grace period.
+func (r *orphanedAttachmentReconciler) reconcile(ctx context.Context) error {
+	d := r.driver
+
+	instanceNodes, compartmentIDs, err := d.getClusterNodes(ctx)
+	if err != nil {
+		return err
+	}
+	nodeNames := make(map[string]bool, len(instanceNodes))
+	for _, nodeName := range instanceNodes {
+		nodeNames[nodeName] = true
+	}
+
+	pvs, err := d.KubeClient.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	volumePVs := make(map[string]*kubeAPI.PersistentVolume)
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == BlockVolumeDriverName {
+			volumePVs[pv.Spec.CSI.VolumeHandle] = pv
+		}
+	}
+
+	// The deleted nodes, by PV, that Kubernetes still has the PV attached to.
+	vas, err := d.KubeClient.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	deletedNodes := make(map[string]map[string]bool)
+	for _, va := range vas.Items {
+		if va.Spec.Attacher != BlockVolumeDriverName || va.Spec.Source.PersistentVolumeName == nil {
+			continue
+		}
+		if nodeNames[va.Spec.NodeName] {
+			continue
+		}
+		pvName := *va.Spec.Source.PersistentVolumeName
+		if deletedNodes[pvName] == nil {
+			deletedNodes[pvName] = make(map[string]bool)
+		}
+		deletedNodes[pvName][va.Spec.NodeName] = true
+	}
+
+	orphaned := make(map[string]bool)
+	for _, compartmentID := range compartmentIDs {
+		attachments, err := d.client.Compute().ListVolumeAttachments(ctx, compartmentID, "")
+		if err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			if attachment.GetLifecycleState() != core.VolumeAttachmentLifecycleStateAttached {
+				continue
+			}
+			pv, ok := volumePVs[*attachment.GetVolumeId()]
+			if !ok {
+				continue
+			}
+			instanceID := *attachment.GetInstanceId()
+			if _, ok := instanceNodes[instanceID]; ok {
+				continue
+			}
+			log := d.logger.With("volumeID", *attachment.GetVolumeId(), "instanceID", instanceID, "attachmentID", *attachment.GetId())
+
+			instance, err := d.client.Compute().GetInstance(ctx, instanceID)
+			if err != nil && !client.IsNotFound(err) {
+				log.With(zap.Error(err)).Error("Failed to get instance of volume attachment.")
+				continue
+			}
+			// A running instance that isn't a node may be using the volume
+			// outside of the cluster, unless it is the instance of a deleted
+			// node Kubernetes attached the volume to.
+			if err == nil && !client.IsInstanceInTerminalState(instance) {
+				deletedNode, err := r.isInstanceOfDeletedNode(ctx, instance, deletedNodes[pv.Name])
+				if err != nil {
+					log.With(zap.Error(err)).Error("Failed to get primary VNIC of instance of volume attachment.")
+					continue
+				}
+				if !deletedNode {
+					continue
+				}
+			}
+
+			orphaned[*attachment.GetId()] = true
+			r.handleOrphanedAttachment(ctx, log, pv, attachment)
+		}
+	}
+
+	for attachmentID := range r.orphanedSince {
+		if !orphaned[attachmentID] {
+			delete(r.orphanedSince, attachmentID)
+		}
+	}
+	return nil
+}
+
+// isInstanceOfDeletedNode returns whether the running instance is the
+// instance of one of the deleted nodes, i.e. the node name is the display
+// name, the hostname or the private IP address of the instance.
+func (r *orphanedAttachmentReconciler) isInstanceOfDeletedNode(ctx context.Context, instance *core.Instance, deletedNodes map[string]bool) (bool, error) {
+	if len(deletedNodes) == 0 {
+		return false, nil
+	}
+	if instance.DisplayName != nil && deletedNodes[*instance.DisplayName] {
+		return true, nil
+	}
+	if instance.CompartmentId == nil || instance.Id == nil {
+		return false, nil
+	}
+
+	vnic, err := r.driver.client.Compute().GetPrimaryVNICForInstance(ctx, *instance.CompartmentId, *instance.Id)
+	if err != nil {
+		return false, err
+	}
+	if vnic == nil {
+		return false, nil
+	}
+	if vnic.HostnameLabel != nil && deletedNodes[*vnic.HostnameLabel] {
+		return true, nil
+	}
+	return vnic.PrivateIp != nil && deletedNodes[*vnic.PrivateIp], nil
+}
+
+// handleOrphanedAttachment records an event on the PV the first time the
+// orphaned attachment is seen, and detaches the volume once the grace period
+// has passed.
+func (r *orphanedAttachmentReconciler) handleOrphanedAttachment(ctx context.Context, log *zap.SugaredLogger, pv *kubeAPI.PersistentVolume, attachment core.VolumeAttachment) {
+	attachmentID := *attachment.GetId()
+	since, ok := r.orphanedSince[attachmentID]
+	if !ok {
+		r.orphanedSince[attachmentID] = r.now()
+		log.With("gracePeriod", r.gracePeriod).Warn("Volume is attached to an instance that is no longer a node of the cluster.")
+		r.recorder.Eventf(pv, kubeAPI.EventTypeWarning, orphanedAttachmentReason,
+			"Volume is attached to instance %s which is no longer a node of the cluster, it will be detached in %s",
+			*attachment.GetInstanceId(), r.gracePeriod)
+		return
+	}
+	if r.now().Sub(since) < r.gracePeriod {
+		return
+	}
+
+	log.Info("Detaching volume from instance that is no longer a node of the cluster.")
+	if err := r.driver.client.Compute().DetachVolume(ctx, attachmentID); err != nil {
+		log.With(zap.Error(err)).Error("Failed to detach orphaned volume attachment.")
+		r.recorder.Eventf(pv, kubeAPI.EventTypeWarning, orphanedDetachFailReason,
+			"Failed to detach volume from instance %s: %v", *attachment.GetInstanceId(), err)
+		return
+	}
+	delete(r.orphanedSince, attachmentID)
+	r.recorder.Eventf(pv, kubeAPI.EventTypeNormal, orphanedDetachReason,
+		"Detached volume from instance %s which is no longer a node of the cluster", *attachment.GetInstanceId())
+}
@@ -0,0 +1,86 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/record"
+
+	providercfg "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+)
+
+func TestReconcileOrphanedAttachments(t *testing.T) {
+	d := &ControllerDriver{
+		KubeClient: MockKubeClient{},
+		logger:     zap.S(),
+		config:     &providercfg.Config{CompartmentID: "compartment_id"},
+		client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+		util:       &csi_util.Util{Logger: zap.S()},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := newOrphanedAttachmentReconciler(d, 5*time.Minute, recorder)
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	expectEvents := func(expected ...string) {
+		t.Helper()
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		sort.Strings(events)
+		if !reflect.DeepEqual(events, expected) {
+			t.Errorf("reconcile() recorded events %q, expected %q", events, expected)
+		}
+	}
+
+	// The attachments of the terminated instance and of the running instances
+	// of deleted nodes, found by display name and by hostname, are orphaned.
+	// The attachment of the shared volume to a running instance that never
+	// was a node is not, even though Kubernetes has the volume attached to a
+	// deleted node too.
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() => unexpected error: %v", err)
+	}
+	expected := map[string]time.Time{
+		"terminated_instance_attachment_id": now,
+		"deleted_node_attachment_id":        now,
+		"renamed_node_attachment_id":        now,
+	}
+	if !reflect.DeepEqual(r.orphanedSince, expected) {
+		t.Errorf("reconcile() => orphaned attachments %v, expected %v", r.orphanedSince, expected)
+	}
+	expectEvents(
+		"Warning OrphanedVolumeAttachment Volume is attached to instance deleted_node_instance_id which is no longer a node of the cluster, it will be detached in 5m0s",
+		"Warning OrphanedVolumeAttachment Volume is attached to instance renamed_node_instance_id which is no longer a node of the cluster, it will be detached in 5m0s",
+		"Warning OrphanedVolumeAttachment Volume is attached to instance terminated_instance_id which is no longer a node of the cluster, it will be detached in 5m0s",
+	)
+
+	// Nothing is detached within the grace period.
+	now = now.Add(time.Minute)
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() => unexpected error: %v", err)
+	}
+	if len(r.orphanedSince) != 3 {
+		t.Errorf("reconcile() => orphaned attachments %v, expected 3", r.orphanedSince)
+	}
+	expectEvents()
+
+	now = now.Add(5 * time.Minute)
+	if err := r.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() => unexpected error: %v", err)
+	}
+	if len(r.orphanedSince) != 0 {
+		t.Errorf("reconcile() => orphaned attachments %v, expected none after detaching", r.orphanedSince)
+	}
+	expectEvents(
+		"Normal VolumeDetachedFromDeletedNode Detached volume from instance deleted_node_instance_id which is no longer a node of the cluster",
+		"Normal VolumeDetachedFromDeletedNode Detached volume from instance renamed_node_instance_id which is no longer a node of the cluster",
+		"Normal VolumeDetachedFromDeletedNode Detached volume from instance terminated_instance_id which is no longer a node of the cluster",
+	)
+}
@@ -0,0 +1,168 @@
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
+)
+
+// queueDepthController tunes the iSCSI queue depth of the block volumes that
+// are staged on the node when the volume performance controller raises their
+// performance level in the attachment metadata of their volume attachments.
+// Volumes staged after the change are tuned by NodeStageVolume, which gets
+// the attachment metadata as publish context. Lowering the performance level
+// keeps the deeper queue until the volume is staged again.
+type queueDepthController struct {
+	nodeID    string
+	logger    *zap.SugaredLogger
+	vaLister  storagelisters.VolumeAttachmentLister
+	hasSynced cache.InformerSynced
+	queue     workqueue.RateLimitingInterface
+
+	// hasSession and newMounter are replaced in tests.
+	hasSession func(iqn string) (bool, error)
+	newMounter func(logger *zap.SugaredLogger, scsiInfo *disk.Disk, multipathDisks []*disk.Disk) disk.Interface
+}
+
+// newQueueDepthController creates a queueDepthController on the volume
+// attachment informer of the factory. The factory must be started after.
+func newQueueDepthController(d NodeDriver, factory informers.SharedInformerFactory) *queueDepthController {
+	vaInformer := factory.Storage().V1().VolumeAttachments()
+	c := &queueDepthController{
+		nodeID:     d.nodeID,
+		logger:     d.logger,
+		vaLister:   vaInformer.Lister(),
+		hasSynced:  vaInformer.Informer().HasSynced,
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "queue-depth"),
+		hasSession: disk.HasISCSISession,
+		newMounter: newISCSIMounter,
+	}
+
+	vaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueue(newObj)
+		},
+	})
+
+	return c
+}
+
+// newISCSIMounter returns the mount handler of a single or multipath iSCSI
+// attachment.
+func newISCSIMounter(logger *zap.SugaredLogger, scsiInfo *disk.Disk, multipathDisks []*disk.Disk) disk.Interface {
+	if len(multipathDisks) > 0 {
+		return disk.NewFromMultipathISCSIDisks(logger, multipathDisks)
+	}
+	return disk.NewFromISCSIDisk(logger, scsiInfo)
+}
+
+func (c *queueDepthController) enqueue(obj interface{}) {
+	va, ok := obj.(*storagev1.VolumeAttachment)
+	if !ok || va.Spec.NodeName != c.nodeID || va.Spec.Attacher != BlockVolumeDriverName {
+		return
+	}
+	c.queue.Add(va.Name)
+}
+
+// Run tunes the volumes of the node until stopCh is closed.
+func (c *queueDepthController) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	c.logger.Info("Starting iSCSI queue depth controller.")
+	if !cache.WaitForCacheSync(stopCh, c.hasSynced) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		return
+	}
+
+	go wait.Until(c.runWorker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (c *queueDepthController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *queueDepthController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(key.(string)); err != nil {
+		c.logger.With(zap.Error(err), "volumeAttachment", key).Error("Failed to update the iSCSI queue depth, will retry.")
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync updates the queue depth of the iSCSI sessions of the volume attachment
+// if its performance level is the higher performance option and the node is
+// logged in to the volume.
+func (c *queueDepthController) sync(name string) error {
+	va, err := c.vaLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if va.Spec.NodeName != c.nodeID || va.Spec.Attacher != BlockVolumeDriverName || !va.Status.Attached {
+		return nil
+	}
+
+	metadata := va.Status.AttachmentMetadata
+	if t, ok := metadata[attachmentType]; ok && t != attachmentTypeISCSI {
+		return nil
+	}
+	v, ok := metadata[csi_util.VpusPerGB]
+	if !ok {
+		return nil
+	}
+	vpusPerGB, err := csi_util.ExtractBlockVolumePerformanceLevel(v)
+	if err != nil || vpusPerGB != csi_util.HigherPerformanceOption {
+		return nil
+	}
+
+	logger := c.logger.With("volumeAttachment", va.Name)
+	scsiInfo, err := csi_util.ExtractISCSIInformation(metadata)
+	if err != nil {
+		// Retrying doesn't help until the attachment metadata changes.
+		logger.With(zap.Error(err)).Error("Failed to get SCSI info from the attachment metadata.")
+		return nil
+	}
+	multipathDisks, err := csi_util.ExtractMultipathISCSIInformation(metadata)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("Failed to get the multipath SCSI info from the attachment metadata.")
+		return nil
+	}
+
+	// The volume isn't staged yet, NodeStageVolume tunes it.
+	loggedIn, err := c.hasSession(scsiInfo.IQN)
+	if err != nil {
+		return err
+	}
+	if !loggedIn {
+		return nil
+	}
+
+	logger.With("IQN", scsiInfo.IQN).Info("Updating the iSCSI queue depth of the staged volume.")
+	return c.newMounter(c.logger, scsiInfo, multipathDisks).UpdateQueueDepth()
+}
@@ -0,0 +1,165 @@
+package driver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
+)
+
+// queueDepthMounter records the targets whose queue depth is updated.
+type queueDepthMounter struct {
+	disk.Interface
+	targets *[]string
+	paths   []*disk.Disk
+}
+
+func (m queueDepthMounter) UpdateQueueDepth() error {
+	for _, path := range m.paths {
+		*m.targets = append(*m.targets, path.Target())
+	}
+	return nil
+}
+
+func queueDepthVolumeAttachment(name, nodeName string, attached bool, metadata map[string]string) *storagev1.VolumeAttachment {
+	return &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: BlockVolumeDriverName,
+			NodeName: nodeName,
+		},
+		Status: storagev1.VolumeAttachmentStatus{Attached: attached, AttachmentMetadata: metadata},
+	}
+}
+
+func TestQueueDepthController_sync(t *testing.T) {
+	iscsiMetadata := func(vpusPerGB string) map[string]string {
+		return map[string]string{
+			attachmentType:     attachmentTypeISCSI,
+			disk.ISCSIIQN:      "iqn.2015-12.com.oracleiaas:volume",
+			disk.ISCSIIP:       "169.254.2.2",
+			disk.ISCSIPORT:     "3260",
+			csi_util.VpusPerGB: vpusPerGB,
+		}
+	}
+	multipathMetadata := iscsiMetadata("20")
+	multipathMetadata[disk.ISCSIMULTIPATHDEVICES] = "169.254.2.2:3260-iqn.2015-12.com.oracleiaas:volume,169.254.2.3:3260-iqn.2015-12.com.oracleiaas:volume"
+	vas := []*storagev1.VolumeAttachment{
+		queueDepthVolumeAttachment("higher-performance", "node1", true, iscsiMetadata("20")),
+		queueDepthVolumeAttachment("balanced", "node1", true, iscsiMetadata("10")),
+		queueDepthVolumeAttachment("multipath", "node1", true, multipathMetadata),
+		queueDepthVolumeAttachment("other-node", "node2", true, iscsiMetadata("20")),
+		queueDepthVolumeAttachment("detached", "node1", false, iscsiMetadata("20")),
+		queueDepthVolumeAttachment("paravirtualized", "node1", true, map[string]string{
+			attachmentType:     attachmentTypeParavirtualized,
+			csi_util.VpusPerGB: "20",
+		}),
+		queueDepthVolumeAttachment("invalid", "node1", true, map[string]string{csi_util.VpusPerGB: "20"}),
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		loggedIn    bool
+		sessionErr  error
+		wantErr     error
+		wantTargets []string
+	}{
+		{
+			name:        "Higher performance volume is tuned",
+			key:         "higher-performance",
+			loggedIn:    true,
+			wantTargets: []string{"169.254.2.2:3260"},
+		},
+		{
+			name:        "Every path of a multipath volume is tuned",
+			key:         "multipath",
+			loggedIn:    true,
+			wantTargets: []string{"169.254.2.2:3260", "169.254.2.3:3260"},
+		},
+		{
+			name:     "Balanced volume isn't tuned",
+			key:      "balanced",
+			loggedIn: true,
+		},
+		{
+			name: "Volume that isn't staged yet",
+			key:  "higher-performance",
+		},
+		{
+			name:       "Failed session lookup is retried",
+			key:        "higher-performance",
+			sessionErr: errors.New("permission denied"),
+			wantErr:    errors.New("permission denied"),
+		},
+		{
+			name:     "Volume attached to another node",
+			key:      "other-node",
+			loggedIn: true,
+		},
+		{
+			name:     "Volume that isn't attached",
+			key:      "detached",
+			loggedIn: true,
+		},
+		{
+			name:     "Paravirtualized volume",
+			key:      "paravirtualized",
+			loggedIn: true,
+		},
+		{
+			name:     "Attachment metadata without iSCSI info isn't retried",
+			key:      "invalid",
+			loggedIn: true,
+		},
+		{
+			name: "Deleted volume attachment",
+			key:  "deleted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, va := range vas {
+				indexer.Add(va)
+			}
+			var targets []string
+			c := &queueDepthController{
+				nodeID:   "node1",
+				logger:   zap.S(),
+				vaLister: storagelisters.NewVolumeAttachmentLister(indexer),
+				queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+				hasSession: func(iqn string) (bool, error) {
+					return tt.loggedIn, tt.sessionErr
+				},
+				newMounter: func(logger *zap.SugaredLogger, scsiInfo *disk.Disk, multipathDisks []*disk.Disk) disk.Interface {
+					paths := multipathDisks
+					if len(paths) == 0 {
+						paths = []*disk.Disk{scsiInfo}
+					}
+					return queueDepthMounter{targets: &targets, paths: paths}
+				},
+			}
+
+			err := c.sync(tt.key)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if strings.Join(targets, ",") != strings.Join(tt.wantTargets, ",") {
+				t.Errorf("got queue depth updated for %q, want %q", targets, tt.wantTargets)
+			}
+		})
+	}
+}
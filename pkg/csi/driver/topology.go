@@ -0,0 +1,58 @@
+package driver
+
+import (
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	kubeAPI "k8s.io/api/core/v1"
+)
+
+// BlockVolumeTopologyKey is the driver specific topology key the availability
+// domain of block volumes and nodes is published under.
+const BlockVolumeTopologyKey = "topology." + BlockVolumeDriverName + "/zone"
+
+// blockVolumeTopologyKeys are the topology keys an availability domain is
+// published under and looked up by, in order of preference. The legacy
+// failure-domain key is kept for volumes provisioned by earlier releases.
+var blockVolumeTopologyKeys = []string{
+	kubeAPI.LabelTopologyZone,
+	BlockVolumeTopologyKey,
+	kubeAPI.LabelZoneFailureDomain,
+}
+
+// newBlockVolumeTopology returns the topology of the given availability domain
+// short name under all the block volume topology keys.
+func newBlockVolumeTopology(availableDomainShortName string) *csi.Topology {
+	segments := make(map[string]string, len(blockVolumeTopologyKeys))
+	for _, key := range blockVolumeTopologyKeys {
+		segments[key] = availableDomainShortName
+	}
+	return &csi.Topology{Segments: segments}
+}
+
+// getTopologyAD returns the availability domain short name of the topology,
+// or an empty string if it has none of the block volume topology keys.
+func getTopologyAD(topology *csi.Topology) string {
+	for _, key := range blockVolumeTopologyKeys {
+		if ad := topology.GetSegments()[key]; ad != "" {
+			return ad
+		}
+	}
+	return ""
+}
+
+// getTopologyADs returns the availability domain short names of the
+// topology requirement, the preferred ones first and in order.
+func getTopologyADs(requirement *csi.TopologyRequirement) []string {
+	var ads []string
+	seen := make(map[string]bool)
+	for _, topology := range append(requirement.GetPreferred(), requirement.GetRequisite()...) {
+		ad := getTopologyAD(topology)
+		if ad == "" || seen[strings.ToUpper(ad)] {
+			continue
+		}
+		seen[strings.ToUpper(ad)] = true
+		ads = append(ads, ad)
+	}
+	return ads
+}
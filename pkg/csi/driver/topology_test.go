@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	kubeAPI "k8s.io/api/core/v1"
+)
+
+func TestGetTopologyADs(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement *csi.TopologyRequirement
+		want        []string
+	}{
+		{
+			name: "No topology requirement",
+		},
+		{
+			name: "Preferred before requisite topologies",
+			requirement: &csi.TopologyRequirement{
+				Requisite: []*csi.Topology{
+					{Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-1"}},
+					{Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-2"}},
+					{Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-3"}},
+				},
+				Preferred: []*csi.Topology{
+					{Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-2"}},
+					{Segments: map[string]string{kubeAPI.LabelTopologyZone: "US-ASHBURN-AD-3"}},
+				},
+			},
+			want: []string{"US-ASHBURN-AD-2", "US-ASHBURN-AD-3", "US-ASHBURN-AD-1"},
+		},
+		{
+			name: "Driver and legacy topology keys",
+			requirement: &csi.TopologyRequirement{
+				Requisite: []*csi.Topology{
+					{Segments: map[string]string{BlockVolumeTopologyKey: "US-ASHBURN-AD-1"}},
+					{Segments: map[string]string{kubeAPI.LabelZoneFailureDomain: "US-ASHBURN-AD-2"}},
+					{Segments: map[string]string{"x": "ad1"}},
+				},
+			},
+			want: []string{"US-ASHBURN-AD-1", "US-ASHBURN-AD-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTopologyADs(tt.requirement); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getTopologyADs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
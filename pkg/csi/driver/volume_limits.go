@@ -0,0 +1,156 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/disk"
+)
+
+const (
+	// bootVolumeAttachments is the number of attachments taken by the boot
+	// volume of the instance.
+	bootVolumeAttachments = 1
+)
+
+// getMaxVolumesPerNode returns the number of block volumes Kubernetes can
+// attach to the node. Unless it is overridden, the attachment limit of the
+// instance shape is reduced by the boot volume and the volumes attached
+// outside of Kubernetes, counted from the disks in the sysfs block directory.
+func (d BlockVolumeNodeDriver) getMaxVolumesPerNode(ctx context.Context, sysBlock string) int64 {
+	if d.maxVolumesPerNode > 0 {
+		return d.maxVolumesPerNode
+	}
+	logger := d.logger.With("nodeId", d.nodeID)
+
+	instanceLimit := d.getInstanceVolumeLimit()
+
+	disks, err := countAttachedDisks(sysBlock)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("Failed to count the disks attached to the node.")
+		return instanceLimit
+	}
+
+	managed, err := d.countManagedAttachments(ctx)
+	if err != nil {
+		logger.With(zap.Error(err)).Warn("Failed to count the volumes attached to the node by Kubernetes.")
+		return instanceLimit
+	}
+
+	maxVolumes := getAvailableVolumeAttachments(instanceLimit, disks, managed)
+	logger.With("instanceLimit", instanceLimit, "attachedDisks", disks, "managedAttachments", managed,
+		"maxVolumesPerNode", maxVolumes).Info("Maximum number of volumes per node identified.")
+	return maxVolumes
+}
+
+// getInstanceVolumeLimit returns the attachment limit of the instance shape,
+// read from the instance metadata, in volumeLimitsPerShape. Shapes that aren't
+// listed take maxVolumesPerNode, the documented maximum of "Block Volume
+// Capabilities and Limits" in
+// https://docs.oracle.com/en-us/iaas/Content/Block/Concepts/overview.htm. The
+// compute API doesn't report a volume attachment limit per shape, core.Shape
+// only has the VNIC attachment limits.
+func (d BlockVolumeNodeDriver) getInstanceVolumeLimit() int64 {
+	if len(d.volumeLimitsPerShape) == 0 || d.metadata == nil {
+		return maxVolumesPerNode
+	}
+	md, err := d.metadata.Get()
+	if err != nil {
+		d.logger.With(zap.Error(err)).Warnf("Failed to get instance metadata, using the default of %d volumes per node.", maxVolumesPerNode)
+		return maxVolumesPerNode
+	}
+	if limit, ok := d.volumeLimitsPerShape[md.Shape]; ok {
+		d.logger.With("shape", md.Shape, "instanceLimit", limit).Info("Using the volume attachment limit of the instance shape.")
+		return limit
+	}
+	return maxVolumesPerNode
+}
+
+// ParseVolumeLimitsPerShape parses the block volume attachment limits of
+// instance shapes from comma separated <shape>=<limit> entries, e.g.
+// "VM.Standard.E2.1.Micro=8,VM.Standard2.1=16".
+func ParseVolumeLimitsPerShape(value string) (map[string]int64, error) {
+	limits := make(map[string]int64)
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid entry %q, expected <shape>=<limit>", entry)
+		}
+		limit, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid volume limit %q of shape %s", parts[1], parts[0])
+		}
+		limits[parts[0]] = limit
+	}
+	return limits, nil
+}
+
+// countManagedAttachments returns the number of block volumes attached to the
+// node through Kubernetes.
+func (d BlockVolumeNodeDriver) countManagedAttachments(ctx context.Context) (int64, error) {
+	vas, err := d.KubeClient.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	var managed int64
+	for _, va := range vas.Items {
+		if va.Spec.Attacher == BlockVolumeDriverName && va.Spec.NodeName == d.nodeID && va.Status.Attached {
+			managed++
+		}
+	}
+	return managed, nil
+}
+
+// getAvailableVolumeAttachments subtracts the attachments Kubernetes doesn't
+// manage, the boot volume and the disks attached by hand, from the instance
+// limit.
+// It doesn't go below one as kubelet reads zero as no limit.
+func getAvailableVolumeAttachments(instanceLimit, disks, managed int64) int64 {
+	unmanaged := disks - managed
+	if unmanaged < bootVolumeAttachments {
+		unmanaged = bootVolumeAttachments
+	}
+	if available := instanceLimit - unmanaged; available > 0 {
+		return available
+	}
+	return 1
+}
+
+// countAttachedDisks returns the number of disks attached to the node from
+// the given sysfs block directory. Both iSCSI and paravirtualized block volume
+// attachments show up as SCSI disks. The disks that are paths of the same
+// dm-multipath device, as used by multipath attachments, are counted once.
+func countAttachedDisks(dir string) (int64, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	disks := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "sd") {
+			continue
+		}
+		multipathDevice, err := disk.FindMultipathHolder(dir, name)
+		if err != nil {
+			return 0, err
+		}
+		if multipathDevice != "" {
+			disks[multipathDevice] = true
+		} else {
+			disks[name] = true
+		}
+	}
+	return int64(len(disks)), nil
+}
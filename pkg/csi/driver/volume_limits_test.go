@@ -0,0 +1,289 @@
+package driver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/instance/metadata"
+)
+
+func TestGetAvailableVolumeAttachments(t *testing.T) {
+	tests := []struct {
+		name          string
+		instanceLimit int64
+		disks         int64
+		managed       int64
+		want          int64
+	}{
+		{
+			name:          "Boot volume only",
+			instanceLimit: 32,
+			disks:         1,
+			want:          31,
+		},
+		{
+			name:          "Volumes attached by Kubernetes",
+			instanceLimit: 32,
+			disks:         3,
+			managed:       2,
+			want:          31,
+		},
+		{
+			name:          "Volumes attached by hand",
+			instanceLimit: 16,
+			disks:         4,
+			managed:       1,
+			want:          13,
+		},
+		{
+			name:          "Boot volume subtracted without disks",
+			instanceLimit: 8,
+			want:          7,
+		},
+		{
+			name:          "Instance limit used up",
+			instanceLimit: 8,
+			disks:         10,
+			want:          1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getAvailableVolumeAttachments(tt.instanceLimit, tt.disks, tt.managed); got != tt.want {
+				t.Errorf("getAvailableVolumeAttachments() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountAttachedDisks(t *testing.T) {
+	tests := map[string]struct {
+		// devices maps the devices of the sysfs block directory to their
+		// holders.
+		devices map[string][]string
+		// dmUUIDs are the device mapper UUIDs of the dm devices.
+		dmUUIDs map[string]string
+		want    int64
+	}{
+		"SCSI disks": {
+			devices: map[string][]string{"sda": nil, "sdb": nil, "sdc": nil, "loop0": nil, "nvme0n1": nil},
+			want:    3,
+		},
+		"Multipath slaves counted once": {
+			devices: map[string][]string{
+				"sda":  nil,
+				"sdb":  {"dm-0"},
+				"sdc":  {"dm-0"},
+				"sdd":  {"dm-0"},
+				"sde":  {"dm-1"},
+				"sdf":  {"dm-1"},
+				"dm-0": nil,
+				"dm-1": nil,
+			},
+			dmUUIDs: map[string]string{
+				"dm-0": "mpath-3600a098038303634722b4d59646c4436",
+				"dm-1": "mpath-3600a098038303634722b4d59646c4437",
+			},
+			want: 3,
+		},
+		"LUKS and LVM holders don't merge disks": {
+			devices: map[string][]string{
+				"sda":  nil,
+				"sdb":  {"dm-0"},
+				"sdc":  {"dm-1"},
+				"sdd":  {"dm-1"},
+				"dm-0": nil,
+				"dm-1": nil,
+			},
+			dmUUIDs: map[string]string{
+				"dm-0": "CRYPT-LUKS2-0f3a0c6ab2a04cbe9e7d2a3bd4e5f6a7-luks",
+				"dm-1": "LVM-h0jFbm1Y0Qx2hHcJ3XhCcx7k1Qfz5Wcd",
+			},
+			want: 4,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "sys-block")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for device, holders := range tt.devices {
+				if err := os.MkdirAll(filepath.Join(tmpDir, device, "holders"), 0750); err != nil {
+					t.Fatalf("failed to create dir: %v", err)
+				}
+				for _, holder := range holders {
+					if err := os.Mkdir(filepath.Join(tmpDir, device, "holders", holder), 0750); err != nil {
+						t.Fatalf("failed to create dir: %v", err)
+					}
+				}
+			}
+			for device, uuid := range tt.dmUUIDs {
+				if err := os.MkdirAll(filepath.Join(tmpDir, device, "dm"), 0750); err != nil {
+					t.Fatalf("failed to create dir: %v", err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(tmpDir, device, "dm", "uuid"), []byte(uuid+"\n"), 0640); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(tmpDir, device, "dm", "name"), []byte(device+"\n"), 0640); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			got, err := countAttachedDisks(tmpDir)
+			if err != nil {
+				t.Fatalf("countAttachedDisks() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("countAttachedDisks() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockVolumeNodeDriver_getMaxVolumesPerNode(t *testing.T) {
+	sysBlock, err := ioutil.TempDir("", "sys-block")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(sysBlock)
+	// The boot volume, the two volumes attached by Kubernetes and one
+	// attached by hand.
+	for _, device := range []string{"sda", "sdb", "sdc", "sdd"} {
+		if err := os.Mkdir(filepath.Join(sysBlock, device), 0750); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	volumeLimitsPerShape := map[string]int64{"VM.Standard.E2.1.Micro": 8}
+	tests := []struct {
+		name              string
+		maxVolumesPerNode int64
+		sysBlock          string
+		metadata          metadata.Interface
+		want              int64
+	}{
+		{
+			name:              "Override",
+			maxVolumesPerNode: 4,
+			sysBlock:          sysBlock,
+			want:              4,
+		},
+		{
+			name:     "Unmanaged disks subtracted from the instance limit",
+			sysBlock: sysBlock,
+			want:     maxVolumesPerNode - 2,
+		},
+		{
+			name:     "Instance limit for failed disk lookup",
+			sysBlock: filepath.Join(sysBlock, "missing"),
+			want:     maxVolumesPerNode,
+		},
+		{
+			name:     "Unmanaged disks subtracted from the shape limit",
+			sysBlock: sysBlock,
+			metadata: metadata.NewMock(&metadata.InstanceMetadata{Shape: "VM.Standard.E2.1.Micro"}),
+			want:     6,
+		},
+		{
+			name:     "Default limit for a shape that isn't listed",
+			sysBlock: sysBlock,
+			metadata: metadata.NewMock(&metadata.InstanceMetadata{Shape: "BM.Standard3.64"}),
+			want:     maxVolumesPerNode - 2,
+		},
+		{
+			name:     "Default limit for failed metadata lookup",
+			sysBlock: sysBlock,
+			metadata: metadata.NewErrorMock(),
+			want:     maxVolumesPerNode - 2,
+		},
+		{
+			name:              "Override of the shape limit",
+			maxVolumesPerNode: 4,
+			sysBlock:          sysBlock,
+			metadata:          metadata.NewMock(&metadata.InstanceMetadata{Shape: "VM.Standard.E2.1.Micro"}),
+			want:              4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := BlockVolumeNodeDriver{NodeDriver: NodeDriver{
+				nodeID:            "node1",
+				maxVolumesPerNode: tt.maxVolumesPerNode,
+				KubeClient:        &MockKubeClient{},
+				logger:            zap.S(),
+				util:              &csi_util.Util{Logger: zap.S()},
+
+				volumeLimitsPerShape: volumeLimitsPerShape,
+				metadata:             tt.metadata,
+			}}
+			if got := d.getMaxVolumesPerNode(context.Background(), tt.sysBlock); got != tt.want {
+				t.Errorf("getMaxVolumesPerNode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockVolumeNodeDriver_countManagedAttachments(t *testing.T) {
+	d := BlockVolumeNodeDriver{NodeDriver: NodeDriver{
+		nodeID:     "node1",
+		KubeClient: &MockKubeClient{},
+		logger:     zap.S(),
+	}}
+	got, err := d.countManagedAttachments(context.Background())
+	if err != nil {
+		t.Fatalf("countManagedAttachments() unexpected error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("countManagedAttachments() = %d, want 2", got)
+	}
+}
+
+func TestParseVolumeLimitsPerShape(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    map[string]int64
+		wantErr bool
+	}{
+		"Empty": {
+			value: "",
+			want:  map[string]int64{},
+		},
+		"Shapes": {
+			value: "VM.Standard.E2.1.Micro=8, VM.Standard2.1=16",
+			want:  map[string]int64{"VM.Standard.E2.1.Micro": 8, "VM.Standard2.1": 16},
+		},
+		"Missing limit": {
+			value:   "VM.Standard2.1",
+			wantErr: true,
+		},
+		"Missing shape": {
+			value:   "=16",
+			wantErr: true,
+		},
+		"Invalid limit": {
+			value:   "VM.Standard2.1=0",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseVolumeLimitsPerShape(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVolumeLimitsPerShape() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVolumeLimitsPerShape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
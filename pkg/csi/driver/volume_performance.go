@@ -0,0 +1,309 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v50/core"
+	"go.uber.org/zap"
+	kubeAPI "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/metrics"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util"
+)
+
+const (
+	// VolumePerformanceAnnotation on a bound PVC requests a new performance
+	// level (vpusPerGB) for its block volume. The volume is updated online.
+	VolumePerformanceAnnotation = "oci.oraclecloud.com/vpus-per-gb"
+
+	// volumePerformanceResyncPeriod is how often the annotated claims are
+	// checked again, which reverts performance changes made outside of
+	// Kubernetes.
+	volumePerformanceResyncPeriod = 10 * time.Minute
+
+	// volumePerformanceUpdateTimeout bounds the update of a single volume,
+	// including the wait for the volume to be available again.
+	volumePerformanceUpdateTimeout = 5 * time.Minute
+
+	volumePerformanceWorkers = 4
+
+	// volumeHandleIndex indexes the persistent volumes by CSI volume handle.
+	volumeHandleIndex = "volumeHandle"
+
+	volumePerformanceUpdatedReason = "VolumePerformanceUpdated"
+	volumePerformanceFailedReason  = "VolumePerformanceUpdateFailed"
+	volumePerformanceInvalidReason = "InvalidVolumePerformance"
+)
+
+// volumePerformanceController applies the performance level requested
+// through VolumePerformanceAnnotation to the block volumes of the claims.
+type volumePerformanceController struct {
+	driver    *ControllerDriver
+	pvcLister corelisters.PersistentVolumeClaimLister
+	pvLister  corelisters.PersistentVolumeLister
+	pvIndexer cache.Indexer
+	vaLister  storagelisters.VolumeAttachmentLister
+	hasSynced []cache.InformerSynced
+	queue     workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+}
+
+// newVolumePerformanceController creates a volumePerformanceController on the
+// PVC and PV informers of the factory. The factory must be started after.
+func newVolumePerformanceController(d *ControllerDriver, factory informers.SharedInformerFactory, recorder record.EventRecorder) *volumePerformanceController {
+	pvcInformer := factory.Core().V1().PersistentVolumeClaims()
+	pvInformer := factory.Core().V1().PersistentVolumes()
+	vaInformer := factory.Storage().V1().VolumeAttachments()
+	if err := pvInformer.Informer().AddIndexers(cache.Indexers{volumeHandleIndex: pvVolumeHandleIndexFunc}); err != nil {
+		d.logger.With(zap.Error(err)).Fatal("Failed to index persistent volumes by volume handle.")
+	}
+
+	c := &volumePerformanceController{
+		driver:    d,
+		pvcLister: pvcInformer.Lister(),
+		pvLister:  pvInformer.Lister(),
+		pvIndexer: pvInformer.Informer().GetIndexer(),
+		vaLister:  vaInformer.Lister(),
+		hasSynced: []cache.InformerSynced{pvcInformer.Informer().HasSynced, pvInformer.Informer().HasSynced, vaInformer.Informer().HasSynced},
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "volume-performance"),
+		recorder:  recorder,
+	}
+
+	pvcInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueue(newObj)
+		},
+	}, volumePerformanceResyncPeriod)
+
+	return c
+}
+
+// pvVolumeHandleIndexFunc indexes the block volume PVs by volume handle.
+func pvVolumeHandleIndexFunc(obj interface{}) ([]string, error) {
+	pv, ok := obj.(*kubeAPI.PersistentVolume)
+	if !ok || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != BlockVolumeDriverName {
+		return nil, nil
+	}
+	return []string{pv.Spec.CSI.VolumeHandle}, nil
+}
+
+func (c *volumePerformanceController) enqueue(obj interface{}) {
+	pvc, ok := obj.(*kubeAPI.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	if _, ok := pvc.Annotations[VolumePerformanceAnnotation]; !ok || pvc.Spec.VolumeName == "" {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(pvc)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run processes the annotated claims with the given number of workers until
+// stopCh is closed.
+func (c *volumePerformanceController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	c.driver.logger.With("workers", workers).Info("Starting volume performance controller.")
+	if !cache.WaitForCacheSync(stopCh, c.hasSynced...) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (c *volumePerformanceController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *volumePerformanceController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), volumePerformanceUpdateTimeout)
+	defer cancel()
+	if err := c.sync(ctx, key.(string)); err != nil {
+		c.driver.logger.With(zap.Error(err), "pvc", key).Error("Failed to update volume performance, will retry.")
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync updates the block volume of the claim if its VolumePerformanceAnnotation
+// differs from the volume's performance level, and passes the level on to the
+// nodes the volume is attached to.
+func (c *volumePerformanceController) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pvc, err := c.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	value, ok := pvc.Annotations[VolumePerformanceAnnotation]
+	if !ok || pvc.Status.Phase != kubeAPI.ClaimBound || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+
+	vpusPerGB, err := csi_util.ExtractBlockVolumePerformanceLevel(value)
+	if err != nil {
+		// Retrying doesn't help until the annotation is fixed, which
+		// requeues the claim.
+		c.recorder.Eventf(pvc, kubeAPI.EventTypeWarning, volumePerformanceInvalidReason,
+			"Invalid %s annotation: %v", VolumePerformanceAnnotation, err)
+		return nil
+	}
+
+	pv, err := c.pvLister.Get(pvc.Spec.VolumeName)
+	if err != nil {
+		return err
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != BlockVolumeDriverName {
+		return nil
+	}
+
+	updated, err := c.driver.updateVolumePerformance(ctx, pv.Spec.CSI.VolumeHandle, vpusPerGB)
+	if err != nil {
+		c.recorder.Eventf(pvc, kubeAPI.EventTypeWarning, volumePerformanceFailedReason,
+			"Failed to update the performance of volume %s to %d VPUs/GB: %v", pv.Spec.CSI.VolumeHandle, vpusPerGB, err)
+		return err
+	}
+	if updated {
+		c.recorder.Eventf(pvc, kubeAPI.EventTypeNormal, volumePerformanceUpdatedReason,
+			"Updated the performance of volume %s to %d VPUs/GB", pv.Spec.CSI.VolumeHandle, vpusPerGB)
+	}
+	return c.updateAttachmentPerformance(ctx, pv.Name, vpusPerGB)
+}
+
+// updateAttachmentPerformance sets the performance level in the attachment
+// metadata of the volume attachments of the persistent volume. The node
+// drivers watch it to tune the iSCSI queue depth of the staged volume.
+func (c *volumePerformanceController) updateAttachmentPerformance(ctx context.Context, pvName string, vpusPerGB int64) error {
+	vas, err := c.vaLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	value := strconv.FormatInt(vpusPerGB, 10)
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"attachmentMetadata": map[string]string{csi_util.VpusPerGB: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, va := range vas {
+		if va.Spec.Attacher != BlockVolumeDriverName || va.Spec.Source.PersistentVolumeName == nil ||
+			*va.Spec.Source.PersistentVolumeName != pvName || !va.Status.Attached ||
+			va.Status.AttachmentMetadata[csi_util.VpusPerGB] == value {
+			continue
+		}
+		c.driver.logger.With("volumeAttachment", va.Name, "vpusPerGB", value).Info("Updating the performance level of the volume attachment.")
+		_, err := c.driver.KubeClient.StorageV1().VolumeAttachments().Patch(ctx, va.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to update the performance level of volume attachment %s: %v", va.Name, err)
+		}
+	}
+	return nil
+}
+
+// performanceRequested returns whether the claim of the volume has the
+// VolumePerformanceAnnotation, in which case its performance level may differ
+// from the one it was created with. It returns true until the caches are
+// synced.
+func (c *volumePerformanceController) performanceRequested(volumeID string) bool {
+	for _, synced := range c.hasSynced {
+		if !synced() {
+			return true
+		}
+	}
+	objs, err := c.pvIndexer.ByIndex(volumeHandleIndex, volumeID)
+	if err != nil {
+		return true
+	}
+	for _, obj := range objs {
+		pv := obj.(*kubeAPI.PersistentVolume)
+		if pv.Spec.ClaimRef == nil {
+			continue
+		}
+		pvc, err := c.pvcLister.PersistentVolumeClaims(pv.Spec.ClaimRef.Namespace).Get(pv.Spec.ClaimRef.Name)
+		if err != nil {
+			continue
+		}
+		if _, ok := pvc.Annotations[VolumePerformanceAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// updateVolumePerformance changes the performance level of the volume to
+// vpusPerGB and waits for the volume to be available again. It returns false
+// if the volume already has the performance level.
+func (d *ControllerDriver) updateVolumePerformance(ctx context.Context, volumeID string, vpusPerGB int64) (bool, error) {
+	startTime := time.Now()
+	log := d.logger.With("volumeID", volumeID, "vpusPerGB", vpusPerGB)
+	dimensionsMap := make(map[string]string)
+	dimensionsMap[metrics.ResourceOCIDDimension] = volumeID
+
+	volume, err := d.client.BlockStorage().GetVolume(ctx, volumeID)
+	if err != nil {
+		return false, err
+	}
+	if volume.VpusPerGB != nil && *volume.VpusPerGB == vpusPerGB {
+		return false, nil
+	}
+
+	log.Info("Updating volume performance.")
+	_, err = d.client.BlockStorage().UpdateVolume(ctx, volumeID, core.UpdateVolumeDetails{VpusPerGB: &vpusPerGB})
+	if err == nil {
+		_, err = d.client.BlockStorage().AwaitVolumeAvailableORTimeout(ctx, volumeID)
+	}
+	if err != nil {
+		dimensionsMap[metrics.ComponentDimension] = util.GetMetricDimensionForComponent(util.GetError(err), util.CSIStorageType)
+		metrics.SendMetricData(d.metricPusher, metrics.PVUpdate, time.Since(startTime).Seconds(), dimensionsMap)
+		return false, err
+	}
+
+	log.With("previousVpusPerGB", volume.VpusPerGB).Info("Volume performance is updated.")
+	dimensionsMap[metrics.ComponentDimension] = util.GetMetricDimensionForComponent(util.Success, util.CSIStorageType)
+	metrics.SendMetricData(d.metricPusher, metrics.PVUpdate, time.Since(startTime).Seconds(), dimensionsMap)
+	return true, nil
+}
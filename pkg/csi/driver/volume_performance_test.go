@@ -0,0 +1,324 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v50/common"
+	"go.uber.org/zap"
+	kubeAPI "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	storagev1client "k8s.io/client-go/kubernetes/typed/storage/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	providercfg "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
+)
+
+// patchRecordingKubeClient records the status patches of volume attachments.
+type patchRecordingKubeClient struct {
+	kubernetes.Interface
+	patches *[]string
+}
+
+type patchRecordingStorageV1 struct {
+	storagev1client.StorageV1Interface
+	patches *[]string
+}
+
+type patchRecordingVolumeAttachments struct {
+	storagev1client.VolumeAttachmentInterface
+	patches *[]string
+}
+
+func (c patchRecordingKubeClient) StorageV1() storagev1client.StorageV1Interface {
+	return patchRecordingStorageV1{patches: c.patches}
+}
+
+func (c patchRecordingStorageV1) VolumeAttachments() storagev1client.VolumeAttachmentInterface {
+	return patchRecordingVolumeAttachments{patches: c.patches}
+}
+
+func (c patchRecordingVolumeAttachments) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*storagev1.VolumeAttachment, error) {
+	*c.patches = append(*c.patches, name+" "+strings.Join(subresources, "/")+" "+string(data))
+	return &storagev1.VolumeAttachment{}, nil
+}
+
+func newTestVolumePerformanceController(pvcs []*kubeAPI.PersistentVolumeClaim, pvs []*kubeAPI.PersistentVolume, vas []*storagev1.VolumeAttachment) (*volumePerformanceController, *record.FakeRecorder, *[]string) {
+	patches := &[]string{}
+	d := &ControllerDriver{
+		KubeClient: patchRecordingKubeClient{patches: patches},
+		logger:     zap.S(),
+		config:     &providercfg.Config{CompartmentID: "compartment_id"},
+		client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+		util:       &csi_util.Util{Logger: zap.S()},
+	}
+	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pvc := range pvcs {
+		pvcIndexer.Add(pvc)
+	}
+	pvIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{volumeHandleIndex: pvVolumeHandleIndexFunc})
+	for _, pv := range pvs {
+		pvIndexer.Add(pv)
+	}
+	vaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, va := range vas {
+		vaIndexer.Add(va)
+	}
+	recorder := record.NewFakeRecorder(10)
+	return &volumePerformanceController{
+		driver:    d,
+		pvcLister: corelisters.NewPersistentVolumeClaimLister(pvcIndexer),
+		pvLister:  corelisters.NewPersistentVolumeLister(pvIndexer),
+		pvIndexer: pvIndexer,
+		vaLister:  storagelisters.NewVolumeAttachmentLister(vaIndexer),
+		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		recorder:  recorder,
+	}, recorder, patches
+}
+
+func performancePVC(name, volumeName, vpusPerGB string) *kubeAPI.PersistentVolumeClaim {
+	pvc := &kubeAPI.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       kubeAPI.PersistentVolumeClaimSpec{VolumeName: volumeName},
+		Status:     kubeAPI.PersistentVolumeClaimStatus{Phase: kubeAPI.ClaimBound},
+	}
+	if vpusPerGB != "" {
+		pvc.Annotations = map[string]string{VolumePerformanceAnnotation: vpusPerGB}
+	}
+	return pvc
+}
+
+func performancePV(name, volumeHandle, claimName string) *kubeAPI.PersistentVolume {
+	return &kubeAPI.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kubeAPI.PersistentVolumeSpec{
+			PersistentVolumeSource: kubeAPI.PersistentVolumeSource{
+				CSI: &kubeAPI.CSIPersistentVolumeSource{Driver: BlockVolumeDriverName, VolumeHandle: volumeHandle},
+			},
+			ClaimRef: &kubeAPI.ObjectReference{Namespace: "default", Name: claimName},
+		},
+	}
+}
+
+func performanceVolumeAttachment(name, attacher, pvName string, attached bool, vpusPerGB string) *storagev1.VolumeAttachment {
+	va := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: attacher,
+			NodeName: "node1",
+			Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: common.String(pvName)},
+		},
+		Status: storagev1.VolumeAttachmentStatus{Attached: attached},
+	}
+	if vpusPerGB != "" {
+		va.Status.AttachmentMetadata = map[string]string{csi_util.VpusPerGB: vpusPerGB}
+	}
+	return va
+}
+
+func TestVolumePerformanceController_sync(t *testing.T) {
+	pvcs := []*kubeAPI.PersistentVolumeClaim{
+		performancePVC("unchanged", "unchanged-pv", "10"),
+		performancePVC("changed", "changed-pv", "20"),
+		performancePVC("invalid", "changed-pv", "fast"),
+		performancePVC("failing", "failing-pv", "20"),
+		performancePVC("unannotated", "changed-pv", ""),
+		performancePVC("unbound", "", "20"),
+	}
+	pvs := []*kubeAPI.PersistentVolume{
+		performancePV("unchanged-pv", "attached_volume_id", "unchanged"),
+		performancePV("changed-pv", "attached_volume_id", "changed"),
+		performancePV("failing-pv", "valid_volume_id_valid_old_size_fail", "failing"),
+	}
+	vas := []*storagev1.VolumeAttachment{
+		performanceVolumeAttachment("unchanged-va", BlockVolumeDriverName, "unchanged-pv", true, "10"),
+		performanceVolumeAttachment("changed-va", BlockVolumeDriverName, "changed-pv", true, "10"),
+		performanceVolumeAttachment("detached-va", BlockVolumeDriverName, "changed-pv", false, "10"),
+		performanceVolumeAttachment("other-driver-va", FSSDriverName, "changed-pv", true, ""),
+		performanceVolumeAttachment("stale-va", BlockVolumeDriverName, "unchanged-pv", true, ""),
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		wantErr     error
+		wantEvent   string
+		wantPatches []string
+	}{
+		{
+			name:        "Volume already has the performance level",
+			key:         "default/unchanged",
+			wantPatches: []string{`stale-va status {"status":{"attachmentMetadata":{"vpusPerGB":"10"}}}`},
+		},
+		{
+			name:        "Update performance level",
+			key:         "default/changed",
+			wantEvent:   "Normal " + volumePerformanceUpdatedReason,
+			wantPatches: []string{`changed-va status {"status":{"attachmentMetadata":{"vpusPerGB":"20"}}}`},
+		},
+		{
+			name:      "Invalid annotation is reported and not retried",
+			key:       "default/invalid",
+			wantEvent: "Warning " + volumePerformanceInvalidReason,
+		},
+		{
+			name:      "Failed update is reported and retried",
+			key:       "default/failing",
+			wantErr:   errors.New("Update volume failed"),
+			wantEvent: "Warning " + volumePerformanceFailedReason,
+		},
+		{
+			name: "Claim without annotation",
+			key:  "default/unannotated",
+		},
+		{
+			name: "Claim that isn't bound",
+			key:  "default/unbound",
+		},
+		{
+			name: "Deleted claim",
+			key:  "default/deleted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder, patches := newTestVolumePerformanceController(pvcs, pvs, vas)
+			err := c.sync(context.Background(), tt.key)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+
+			var event string
+			select {
+			case event = <-recorder.Events:
+			default:
+			}
+			if tt.wantEvent == "" && event != "" {
+				t.Errorf("got event %q, want none", event)
+			}
+			if tt.wantEvent != "" && !strings.HasPrefix(event, tt.wantEvent) {
+				t.Errorf("got event %q, want %q", event, tt.wantEvent)
+			}
+			if !reflect.DeepEqual(*patches, tt.wantPatches) && (len(*patches) != 0 || len(tt.wantPatches) != 0) {
+				t.Errorf("got volume attachment patches %q, want %q", *patches, tt.wantPatches)
+			}
+		})
+	}
+}
+
+func TestVolumePerformanceController_performanceRequested(t *testing.T) {
+	pvcs := []*kubeAPI.PersistentVolumeClaim{
+		performancePVC("annotated", "annotated-pv", "20"),
+		performancePVC("unannotated", "unannotated-pv", ""),
+	}
+	pvs := []*kubeAPI.PersistentVolume{
+		performancePV("annotated-pv", "annotated_volume_id", "annotated"),
+		performancePV("unannotated-pv", "unannotated_volume_id", "unannotated"),
+	}
+	c, _, _ := newTestVolumePerformanceController(pvcs, pvs, nil)
+
+	tests := map[string]struct {
+		volumeID string
+		synced   bool
+		want     bool
+	}{
+		"Claim with annotation": {
+			volumeID: "annotated_volume_id",
+			synced:   true,
+			want:     true,
+		},
+		"Claim without annotation": {
+			volumeID: "unannotated_volume_id",
+			synced:   true,
+			want:     false,
+		},
+		"Volume without persistent volume": {
+			volumeID: "unknown_volume_id",
+			synced:   true,
+			want:     false,
+		},
+		"Caches not synced": {
+			volumeID: "unannotated_volume_id",
+			synced:   false,
+			want:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			synced := tt.synced
+			c.hasSynced = []cache.InformerSynced{func() bool { return synced }}
+			if got := c.performanceRequested(tt.volumeID); got != tt.want {
+				t.Errorf("performanceRequested(%q) = %t, want %t", tt.volumeID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDriver_updateVolumePerformance(t *testing.T) {
+	tests := []struct {
+		name        string
+		volumeID    string
+		vpusPerGB   int64
+		wantUpdated bool
+		wantErr     error
+	}{
+		{
+			name:      "Volume already has the performance level",
+			volumeID:  "attached_volume_id",
+			vpusPerGB: 10,
+		},
+		{
+			name:        "Update performance level",
+			volumeID:    "attached_volume_id",
+			vpusPerGB:   20,
+			wantUpdated: true,
+		},
+		{
+			name:      "Error for failed volume lookup",
+			volumeID:  "invalid_volume_id",
+			vpusPerGB: 20,
+			wantErr:   errors.New("failed to find existence of volume"),
+		},
+		{
+			name:      "Error for failed volume update",
+			volumeID:  "valid_volume_id_valid_old_size_fail",
+			vpusPerGB: 20,
+			wantErr:   errors.New("Update volume failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ControllerDriver{
+				KubeClient: nil,
+				logger:     zap.S(),
+				config:     &providercfg.Config{CompartmentID: "compartment_id"},
+				client:     NewClientProvisioner(nil, &MockBlockStorageClient{}),
+				util:       &csi_util.Util{Logger: zap.S()},
+			}
+			updated, err := d.updateVolumePerformance(context.Background(), tt.volumeID, tt.vpusPerGB)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("got error %q, want none", err)
+			}
+			if tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("want error %q to include %q", err, tt.wantErr)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("updated = %t, want %t", updated, tt.wantUpdated)
+			}
+		})
+	}
+}
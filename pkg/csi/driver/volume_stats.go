@@ -0,0 +1,106 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
+)
+
+// getVolumeStats reports the usage of the volume published at volumePath.
+// Filesystem volumes report byte and inode usage, raw block volumes report the
+// capacity of the device. A stale mount or a missing device is reported as an
+// abnormal volume condition rather than an error so kubelet can surface it.
+func getVolumeStats(logger *zap.SugaredLogger, volumePath string) (*csi.NodeGetVolumeStatsResponse, error) {
+	fi, err := os.Stat(volumePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "volume path %s does not exist", volumePath)
+		}
+		if mount.IsCorruptedMnt(err) {
+			logger.With(zap.Error(err)).Warn("Volume path is a corrupted mount point.")
+			return abnormalVolumeStats(fmt.Sprintf("volume path %s is a stale mount: %v", volumePath, err)), nil
+		}
+		logger.With(zap.Error(err)).Error("Failed to stat volume path.")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if fi.Mode()&os.ModeDevice != 0 {
+		size, err := mount.GetBlockDeviceSizeBytes(volumePath)
+		if err != nil {
+			logger.With(zap.Error(err)).Warn("Failed to get the size of the block device.")
+			return abnormalVolumeStats(fmt.Sprintf("failed to get the size of block device %s: %v", volumePath, err)), nil
+		}
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage: []*csi.VolumeUsage{
+				{
+					Unit:  csi.VolumeUsage_BYTES,
+					Total: size,
+				},
+			},
+			VolumeCondition: &csi.VolumeCondition{Message: "volume is healthy"},
+		}, nil
+	}
+
+	// A raw block volume is published as a bind mount of the device on a
+	// regular file, the file is left behind when the device goes away.
+	if !fi.IsDir() {
+		return abnormalVolumeStats(fmt.Sprintf("block device for volume path %s is missing", volumePath)), nil
+	}
+
+	mounter := mount.New(logger, mountPath)
+	// Use mount.IsNotMountPoint because mounter.IsLikelyNotMountPoint can't detect bind mounts
+	isNotMountPoint, err := mount.IsNotMountPoint(mounter, volumePath)
+	if err != nil {
+		if mount.IsCorruptedMnt(err) {
+			return abnormalVolumeStats(fmt.Sprintf("volume path %s is a stale mount: %v", volumePath, err)), nil
+		}
+		logger.With(zap.Error(err)).Error("Failed to check if volume path is a mount point.")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if isNotMountPoint {
+		return abnormalVolumeStats(fmt.Sprintf("volume path %s is not mounted", volumePath)), nil
+	}
+
+	stats, err := mount.GetFilesystemStats(volumePath)
+	if err != nil {
+		if mount.IsCorruptedMnt(err) {
+			logger.With(zap.Error(err)).Warn("Volume path is a corrupted mount point.")
+			return abnormalVolumeStats(fmt.Sprintf("volume path %s is a stale mount: %v", volumePath, err)), nil
+		}
+		logger.With(zap.Error(err)).Error("Failed to get filesystem stats.")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			{
+				Unit:      csi.VolumeUsage_BYTES,
+				Total:     stats.TotalBytes,
+				Available: stats.AvailableBytes,
+				Used:      stats.UsedBytes,
+			},
+			{
+				Unit:      csi.VolumeUsage_INODES,
+				Total:     stats.TotalInodes,
+				Available: stats.FreeInodes,
+				Used:      stats.UsedInodes,
+			},
+		},
+		VolumeCondition: &csi.VolumeCondition{Message: "volume is healthy"},
+	}, nil
+}
+
+func abnormalVolumeStats(message string) *csi.NodeGetVolumeStatsResponse {
+	return &csi.NodeGetVolumeStatsResponse{
+		VolumeCondition: &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  message,
+		},
+	}
+}
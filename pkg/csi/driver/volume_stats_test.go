@@ -0,0 +1,72 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetVolumeStats(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "volume-stats")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	unmountedDir := filepath.Join(tmpDir, "unmounted")
+	if err := os.Mkdir(unmountedDir, 0750); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	staleBlockTarget := filepath.Join(tmpDir, "block")
+	if err := ioutil.WriteFile(staleBlockTarget, nil, 0640); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		volumePath   string
+		wantCode     codes.Code
+		wantAbnormal bool
+	}{
+		{
+			name:       "Error for missing volume path",
+			volumePath: filepath.Join(tmpDir, "missing"),
+			wantCode:   codes.NotFound,
+		},
+		{
+			name:         "Abnormal condition for unmounted volume path",
+			volumePath:   unmountedDir,
+			wantAbnormal: true,
+		},
+		{
+			name:         "Abnormal condition for missing block device",
+			volumePath:   staleBlockTarget,
+			wantAbnormal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getVolumeStats(zap.S(), tt.volumePath)
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("getVolumeStats() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getVolumeStats() unexpected error = %v", err)
+			}
+			if got.VolumeCondition.Abnormal != tt.wantAbnormal {
+				t.Errorf("getVolumeStats() abnormal = %v, want %v", got.VolumeCondition.Abnormal, tt.wantAbnormal)
+			}
+			if tt.wantAbnormal && len(got.Usage) != 0 {
+				t.Errorf("getVolumeStats() usage = %v, want none for abnormal volume", got.Usage)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 
 	ociprovider "github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/cloudprovider/providers/oci/config"
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/flexvolume"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/metrics"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
@@ -286,7 +287,7 @@ func (d OCIFlexvolumeDriver) Attach(logger *zap.SugaredLogger, opts flexvolume.O
 	}
 	// volume not attached to any instance, proceed with volume attachment
 	logger.With("volumeID", volumeOCID, "instanceID", *instance.Id).Info("Attaching volume to instance")
-	attachment, err = c.Compute().AttachVolume(ctx, *instance.Id, volumeOCID)
+	attachment, err = c.Compute().AttachVolume(ctx, *instance.Id, volumeOCID, false, false, false)
 	if err != nil {
 		errorType = util.GetError(err)
 		fvdMetricDimension = util.GetMetricDimensionForComponent(errorType, util.FVDStorageType)
@@ -449,6 +450,11 @@ func (d OCIFlexvolumeDriver) IsAttached(logger *zap.SugaredLogger, opts flexvolu
 // MountDevice connects the iSCSI target on the k8s worker node before mounting
 // and (if necessary) formatting the disk.
 func (d OCIFlexvolumeDriver) MountDevice(logger *zap.SugaredLogger, mountDir, mountDevice string, opts flexvolume.Options) flexvolume.DriverStatus {
+	fsType, err := csi_util.ValidateFsType(opts[flexvolume.OptionFSType])
+	if err != nil {
+		return flexvolume.Fail(logger, err)
+	}
+
 	iSCSIMounter, err := disk.NewFromDevicePath(logger, mountDevice)
 	if err != nil {
 		return flexvolume.Fail(logger, err)
@@ -478,7 +484,7 @@ func (d OCIFlexvolumeDriver) MountDevice(logger *zap.SugaredLogger, mountDir, mo
 	if opts[flexvolume.OptionReadWrite] == "ro" {
 		options = []string{"ro"}
 	}
-	err = iSCSIMounter.FormatAndMount(mountDevice, mountDir, opts[flexvolume.OptionFSType], options)
+	err = iSCSIMounter.FormatAndMount(mountDevice, mountDir, fsType, options)
 	if err != nil {
 		return flexvolume.Fail(logger, err)
 	}
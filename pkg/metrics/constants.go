@@ -25,6 +25,16 @@ const (
 	PVDelete= "PV_DELETE"
 	// PVExpand is the OCI metric suffix for PV Expand
 	PVExpand = "PV_EXPAND"
+	// PVUpdate is the OCI metric suffix for PV performance updates
+	PVUpdate = "PV_UPDATE"
+	// BackupCreate is the OCI metric suffix for volume backup creation
+	BackupCreate = "BACKUP_CREATE"
+	// BackupDelete is the OCI metric suffix for volume backup deletion
+	BackupDelete = "BACKUP_DELETE"
+	// FSSProvision is the OCI metric suffix for FSS provision
+	FSSProvision = "FSS_PROVISION"
+	// FSSDelete is the OCI metric suffix for FSS delete
+	FSSDelete = "FSS_DELETE"
 
 	ResourceOCIDDimension     = "resourceOCID"
 	ComponentDimension        = "component"
@@ -54,7 +54,14 @@ type BlockStorageInterface interface {
 	DeleteVolume(ctx context.Context, id string) error
 	GetVolume(ctx context.Context, id string) (*core.Volume, error)
 	GetVolumesByName(ctx context.Context, volumeName, compartmentID string) ([]core.Volume, error)
+	ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error)
 	UpdateVolume(ctx context.Context, volumeId string, details core.UpdateVolumeDetails) (*core.Volume, error)
+
+	CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error)
+	GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error)
+	GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error)
+	ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error)
+	DeleteVolumeBackup(ctx context.Context, id string) error
 }
 
 func (c *client) GetVolume(ctx context.Context, id string) (*core.Volume, error) {
@@ -208,3 +215,170 @@ func (c *client) GetVolumesByName(ctx context.Context, volumeName, compartmentID
 
 	return volumeList, nil
 }
+
+// ListVolumes returns a single page of the volumes in the compartment along
+// with the token of the next page (nil on the last page).
+func (c *client) ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error) {
+	if !c.rateLimiter.Reader.TryAccept() {
+		return nil, nil, RateLimitError(false, "ListVolumes")
+	}
+
+	request := core.ListVolumesRequest{
+		CompartmentId:   &compartmentID,
+		Page:            page,
+		SortBy:          core.ListVolumesSortByTimecreated,
+		SortOrder:       core.ListVolumesSortOrderAsc,
+		RequestMetadata: c.requestMetadata,
+	}
+	if limit > 0 {
+		request.Limit = &limit
+	}
+
+	resp, err := c.bs.ListVolumes(ctx, request)
+	incRequestCounter(err, listVerb, volumeResource)
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return resp.Items, resp.OpcNextPage, nil
+}
+
+func (c *client) CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error) {
+	if !c.rateLimiter.Writer.TryAccept() {
+		return nil, RateLimitError(true, "CreateVolumeBackup")
+	}
+
+	resp, err := c.bs.CreateVolumeBackup(ctx, core.CreateVolumeBackupRequest{
+		CreateVolumeBackupDetails: details,
+		RequestMetadata:           c.requestMetadata,
+	})
+	incRequestCounter(err, createVerb, volumeBackupResource)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	logger := c.logger.With("volumeId", *(details.VolumeId), "backupName", *(details.DisplayName),
+		"OpcRequestId", *(resp.OpcRequestId))
+	logger.Info("OPC Request ID recorded while creating volume backup.")
+
+	return &resp.VolumeBackup, nil
+}
+
+func (c *client) GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error) {
+	if !c.rateLimiter.Reader.TryAccept() {
+		return nil, RateLimitError(false, "GetVolumeBackup")
+	}
+
+	resp, err := c.bs.GetVolumeBackup(ctx, core.GetVolumeBackupRequest{
+		VolumeBackupId:  &id,
+		RequestMetadata: c.requestMetadata,
+	})
+	incRequestCounter(err, getVerb, volumeBackupResource)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &resp.VolumeBackup, nil
+}
+
+// GetVolumeBackupsByName returns the backups with the given display name that
+// are not being (or have not been) terminated.
+func (c *client) GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error) {
+	var page *string
+	backups := make([]core.VolumeBackup, 0)
+	for {
+		if !c.rateLimiter.Reader.TryAccept() {
+			return nil, RateLimitError(false, "ListVolumeBackups")
+		}
+
+		resp, err := c.bs.ListVolumeBackups(ctx, core.ListVolumeBackupsRequest{
+			CompartmentId:   &compartmentID,
+			DisplayName:     &snapshotName,
+			Page:            page,
+			RequestMetadata: c.requestMetadata,
+		})
+		incRequestCounter(err, listVerb, volumeBackupResource)
+
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, backup := range resp.Items {
+			if !IsVolumeBackupTerminated(&backup) {
+				backups = append(backups, backup)
+			}
+		}
+
+		if page = resp.OpcNextPage; page == nil {
+			break
+		}
+	}
+
+	return backups, nil
+}
+
+// ListVolumeBackups returns a single page of the volume backups in the
+// compartment, optionally restricted to the backups of a single volume, along
+// with the token of the next page (nil on the last page).
+func (c *client) ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error) {
+	if !c.rateLimiter.Reader.TryAccept() {
+		return nil, nil, RateLimitError(false, "ListVolumeBackups")
+	}
+
+	request := core.ListVolumeBackupsRequest{
+		CompartmentId:   &compartmentID,
+		Page:            page,
+		SortBy:          core.ListVolumeBackupsSortByTimecreated,
+		SortOrder:       core.ListVolumeBackupsSortOrderAsc,
+		RequestMetadata: c.requestMetadata,
+	}
+	if volumeID != "" {
+		request.VolumeId = &volumeID
+	}
+	if limit > 0 {
+		request.Limit = &limit
+	}
+
+	resp, err := c.bs.ListVolumeBackups(ctx, request)
+	incRequestCounter(err, listVerb, volumeBackupResource)
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return resp.Items, resp.OpcNextPage, nil
+}
+
+func (c *client) DeleteVolumeBackup(ctx context.Context, id string) error {
+	if !c.rateLimiter.Writer.TryAccept() {
+		return RateLimitError(true, "DeleteVolumeBackup")
+	}
+
+	_, err := c.bs.DeleteVolumeBackup(ctx, core.DeleteVolumeBackupRequest{
+		VolumeBackupId:  &id,
+		RequestMetadata: c.requestMetadata,
+	})
+	incRequestCounter(err, deleteVerb, volumeBackupResource)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// IsVolumeTerminated returns true if the volume is being or has been deleted.
+func IsVolumeTerminated(volume *core.Volume) bool {
+	return volume.LifecycleState == core.VolumeLifecycleStateTerminating ||
+		volume.LifecycleState == core.VolumeLifecycleStateTerminated
+}
+
+// IsVolumeBackupTerminated returns true if the backup is being or has been
+// deleted.
+func IsVolumeBackupTerminated(backup *core.VolumeBackup) bool {
+	return backup.LifecycleState == core.VolumeBackupLifecycleStateTerminating ||
+		backup.LifecycleState == core.VolumeBackupLifecycleStateTerminated
+}
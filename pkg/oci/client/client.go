@@ -29,6 +29,7 @@ import (
 	"github.com/oracle/oci-go-sdk/v50/core"
 	"github.com/oracle/oci-go-sdk/v50/filestorage"
 	"github.com/oracle/oci-go-sdk/v50/identity"
+	"github.com/oracle/oci-go-sdk/v50/limits"
 	"github.com/oracle/oci-go-sdk/v50/loadbalancer"
 	"github.com/oracle/oci-go-sdk/v50/networkloadbalancer"
 	"github.com/pkg/errors"
@@ -49,6 +50,7 @@ type Interface interface {
 	BlockStorage() BlockStorageInterface
 	FSS() FileStorageInterface
 	Identity() IdentityInterface
+	Limits() LimitsInterface
 }
 
 // RateLimiter reader and writer.
@@ -87,6 +89,9 @@ type loadBalancerClient interface {
 	DeleteLoadBalancer(ctx context.Context, request loadbalancer.DeleteLoadBalancerRequest) (response loadbalancer.DeleteLoadBalancerResponse, err error)
 	ListCertificates(ctx context.Context, request loadbalancer.ListCertificatesRequest) (response loadbalancer.ListCertificatesResponse, err error)
 	CreateCertificate(ctx context.Context, request loadbalancer.CreateCertificateRequest) (response loadbalancer.CreateCertificateResponse, err error)
+	DeleteCertificate(ctx context.Context, request loadbalancer.DeleteCertificateRequest) (response loadbalancer.DeleteCertificateResponse, err error)
+	CreateSSLCipherSuite(ctx context.Context, request loadbalancer.CreateSSLCipherSuiteRequest) (response loadbalancer.CreateSSLCipherSuiteResponse, err error)
+	UpdateSSLCipherSuite(ctx context.Context, request loadbalancer.UpdateSSLCipherSuiteRequest) (response loadbalancer.UpdateSSLCipherSuiteResponse, err error)
 	GetWorkRequest(ctx context.Context, request loadbalancer.GetWorkRequestRequest) (response loadbalancer.GetWorkRequestResponse, err error)
 	CreateBackendSet(ctx context.Context, request loadbalancer.CreateBackendSetRequest) (response loadbalancer.CreateBackendSetResponse, err error)
 	UpdateBackendSet(ctx context.Context, request loadbalancer.UpdateBackendSetRequest) (response loadbalancer.UpdateBackendSetResponse, err error)
@@ -133,12 +138,21 @@ type blockstorageClient interface {
 	DeleteVolume(ctx context.Context, request core.DeleteVolumeRequest) (response core.DeleteVolumeResponse, err error)
 	ListVolumes(ctx context.Context, request core.ListVolumesRequest) (response core.ListVolumesResponse, err error)
 	UpdateVolume(ctx context.Context, request core.UpdateVolumeRequest) (response core.UpdateVolumeResponse, err error)
+
+	CreateVolumeBackup(ctx context.Context, request core.CreateVolumeBackupRequest) (response core.CreateVolumeBackupResponse, err error)
+	GetVolumeBackup(ctx context.Context, request core.GetVolumeBackupRequest) (response core.GetVolumeBackupResponse, err error)
+	ListVolumeBackups(ctx context.Context, request core.ListVolumeBackupsRequest) (response core.ListVolumeBackupsResponse, err error)
+	DeleteVolumeBackup(ctx context.Context, request core.DeleteVolumeBackupRequest) (response core.DeleteVolumeBackupResponse, err error)
 }
 
 type identityClient interface {
 	ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error)
 }
 
+type limitsClient interface {
+	GetResourceAvailability(ctx context.Context, request limits.GetResourceAvailabilityRequest) (response limits.GetResourceAvailabilityResponse, err error)
+}
+
 type client struct {
 	compute             computeClient
 	network             virtualNetworkClient
@@ -147,6 +161,7 @@ type client struct {
 	filestorage         filestorageClient
 	bs                  blockstorageClient
 	identity            identityClient
+	limits              limitsClient
 
 	requestMetadata common.RequestMetadata
 	rateLimiter     RateLimiter
@@ -207,6 +222,16 @@ func New(logger *zap.SugaredLogger, cp common.ConfigurationProvider, opRateLimit
 		return nil, errors.Wrap(err, "configuring identity service client custom transport")
 	}
 
+	limits, err := limits.NewLimitsClientWithConfigurationProvider(cp)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewLimitsClientWithConfigurationProvider")
+	}
+
+	err = configureCustomTransport(logger, &limits.BaseClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "configuring limits service client custom transport")
+	}
+
 	bs, err := core.NewBlockstorageClientWithConfigurationProvider(cp)
 	if err != nil {
 		return nil, errors.Wrap(err, "NewBlockstorageClientWithConfigurationProvider")
@@ -246,6 +271,7 @@ func New(logger *zap.SugaredLogger, cp common.ConfigurationProvider, opRateLimit
 		compute:             &compute,
 		network:             &network,
 		identity:            &identity,
+		limits:              &limits,
 		loadbalancer:        &loadbalancer,
 		networkloadbalancer: &networkloadbalancer,
 		bs:                  &bs,
@@ -283,6 +309,10 @@ func (c *client) Identity() IdentityInterface {
 	return c
 }
 
+func (c *client) Limits() LimitsInterface {
+	return c
+}
+
 func (c *client) BlockStorage() BlockStorageInterface {
 	return c
 }
@@ -27,6 +27,8 @@ var errNotFound = errors.New("not found")
 
 // HTTP Error Types
 const (
+	HTTP400LimitExceededCode                          = "LimitExceeded"
+	HTTP400QuotaExceededCode                          = "QuotaExceeded"
 	HTTP400RelatedResourceNotAuthorizedOrNotFoundCode = "RelatedResourceNotAuthorizedOrNotFound"
 	HTTP401NotAuthenticatedCode                       = "NotAuthenticated"
 	HTTP404NotAuthorizedOrNotFoundCode                = "NotAuthorizedOrNotFound"
@@ -34,6 +36,7 @@ const (
 	HTTP409NotAuthorizedOrResourceAlreadyExistsCode   = "NotAuthorizedOrResourceAlreadyExists"
 	HTTP429TooManyRequestsCode                        = "TooManyRequests"
 	HTTP500InternalServerErrorCode                    = "InternalServerError"
+	HTTP500OutOfCapacityCode                          = "OutOfCapacity"
 )
 
 // IsNotFound returns true if the given error indicates that a resource could
@@ -52,6 +55,35 @@ func IsNotFound(err error) bool {
 	return ok && serviceErr.GetHTTPStatusCode() == http.StatusNotFound
 }
 
+// IsBadRequest returns true if the given error is a service error with HTTP
+// status code 400, e.g. because of an invalid page token.
+func IsBadRequest(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	serviceErr, ok := common.IsServiceError(errors.Cause(err))
+	return ok && serviceErr.GetHTTPStatusCode() == http.StatusBadRequest
+}
+
+// IsOutOfCapacity returns true if the given error indicates that a resource
+// could not be created because a service limit or quota is used up or the
+// service is out of capacity.
+func IsOutOfCapacity(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	serviceErr, ok := errors.Cause(err).(common.ServiceError)
+	return ok && isOutOfCapacityServiceError(serviceErr)
+}
+
+func isOutOfCapacityServiceError(serviceErr common.ServiceError) bool {
+	return ((serviceErr.GetHTTPStatusCode() == http.StatusBadRequest) && (serviceErr.GetCode() == HTTP400LimitExceededCode)) ||
+		((serviceErr.GetHTTPStatusCode() == http.StatusBadRequest) && (serviceErr.GetCode() == HTTP400QuotaExceededCode)) ||
+		((serviceErr.GetHTTPStatusCode() == http.StatusInternalServerError) && (serviceErr.GetCode() == HTTP500OutOfCapacityCode))
+}
+
 //IsRetryable returns true if the given error is retriable.
 func IsRetryable(err error) bool {
 	if err == nil {
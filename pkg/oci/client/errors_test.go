@@ -103,3 +103,48 @@ func TestIsRetryableServiceError(t *testing.T) {
 	}
 
 }
+
+func TestIsOutOfCapacityServiceError(t *testing.T) {
+	testCases := map[string]struct {
+		error    common.ServiceError
+		expected bool
+	}{
+		"HTTP400LimitExceeded": {
+			error: mockServiceError{
+				StatusCode: http.StatusBadRequest,
+				Code:       HTTP400LimitExceededCode,
+			},
+			expected: true,
+		},
+		"HTTP400QuotaExceeded": {
+			error: mockServiceError{
+				StatusCode: http.StatusBadRequest,
+				Code:       HTTP400QuotaExceededCode,
+			},
+			expected: true,
+		},
+		"HTTP500OutOfCapacity": {
+			error: mockServiceError{
+				StatusCode: http.StatusInternalServerError,
+				Code:       HTTP500OutOfCapacityCode,
+			},
+			expected: true,
+		},
+		"HTTP500InternalServerError": {
+			error: mockServiceError{
+				StatusCode: http.StatusInternalServerError,
+				Code:       HTTP500InternalServerErrorCode,
+			},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := isOutOfCapacityServiceError(tc.error)
+			if result != tc.expected {
+				t.Errorf("isOutOfCapacityServiceError(%v) = %v ; wanted %v", tc.error, result, tc.expected)
+			}
+		})
+	}
+}
@@ -43,6 +43,7 @@ type FileStorageInterface interface {
 
 	CreateExport(ctx context.Context, details fss.CreateExportDetails) (*fss.Export, error)
 	FindExport(ctx context.Context, compartmentID, fsID, exportSetID string) (*fss.ExportSummary, error)
+	ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]fss.ExportSummary, error)
 	AwaitExportActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*fss.Export, error)
 	DeleteExport(ctx context.Context, id string) error
 }
@@ -278,6 +279,39 @@ func (c *client) FindExport(ctx context.Context, compartmentID, fsID, exportSetI
 	return nil, errors.WithStack(errNotFound)
 }
 
+// ListExportsForFileSystem returns the CREATING or ACTIVE exports of the file
+// system across all export sets in the compartment.
+func (c *client) ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]fss.ExportSummary, error) {
+	var page *string
+	exports := []fss.ExportSummary{}
+	for {
+		if !c.rateLimiter.Reader.TryAccept() {
+			return nil, RateLimitError(false, "ListExports")
+		}
+		resp, err := c.filestorage.ListExports(ctx, fss.ListExportsRequest{
+			CompartmentId:   &compartmentID,
+			FileSystemId:    &fsID,
+			Page:            page,
+			RequestMetadata: c.requestMetadata,
+		})
+		incRequestCounter(err, listVerb, exportResource)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, export := range resp.Items {
+			if export.LifecycleState == fss.ExportSummaryLifecycleStateCreating ||
+				export.LifecycleState == fss.ExportSummaryLifecycleStateActive {
+				exports = append(exports, export)
+			}
+		}
+		if page = resp.OpcNextPage; resp.OpcNextPage == nil {
+			break
+		}
+	}
+
+	return exports, nil
+}
+
 func (c *client) AwaitExportActive(ctx context.Context, logger *zap.SugaredLogger, id string) (*fss.Export, error) {
 	logger.Info("Waiting for Export to be in lifecycle state ACTIVE")
 
@@ -7,6 +7,8 @@ type GenericBackendSetDetails struct {
 	Backends                        []GenericBackend
 	SessionPersistenceConfiguration *GenericSessionPersistenceConfiguration
 	// Only needed for LB
+	LbCookieSessionPersistenceConfiguration *GenericLbCookieSessionPersistenceConfiguration
+	// Only needed for LB
 	SslConfiguration *GenericSslConfigurationDetails
 	// Only needed for NLB
 	IsPreserveSource *bool
@@ -17,6 +19,16 @@ type GenericSessionPersistenceConfiguration struct {
 	DisableFallback *bool
 }
 
+type GenericLbCookieSessionPersistenceConfiguration struct {
+	CookieName      *string
+	DisableFallback *bool
+	Domain          *string
+	Path            *string
+	MaxAgeInSeconds *int
+	IsSecure        *bool
+	IsHttpOnly      *bool
+}
+
 type GenericHealthChecker struct {
 	Protocol          string
 	Port              *int
@@ -83,7 +95,8 @@ type GenericCreateLoadBalancerDetails struct {
 	DefinedTags                 map[string]map[string]interface{}
 
 	// Only needed for LB
-	Certificates map[string]GenericCertificate
+	Certificates    map[string]GenericCertificate
+	SslCipherSuites map[string]GenericSslCipherSuite
 }
 
 type GenericShapeDetails struct {
@@ -99,6 +112,11 @@ type GenericCertificate struct {
 	CaCertificate     *string
 }
 
+type GenericSslCipherSuite struct {
+	Name    *string
+	Ciphers []string
+}
+
 type GenericReservedIp struct {
 	Id *string
 }
@@ -127,6 +145,7 @@ type GenericLoadBalancer struct {
 	NetworkSecurityGroupIds []string
 	Listeners               map[string]GenericListener
 	Certificates            map[string]GenericCertificate
+	SslCipherSuites         map[string]GenericSslCipherSuite
 	BackendSets             map[string]GenericBackendSetDetails
 
 	FreeformTags map[string]string
@@ -0,0 +1,66 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v50/limits"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlockStorageServiceName is the name of the block volume service in the
+	// OCI limits service.
+	BlockStorageServiceName = "block-storage"
+	// TotalStorageGBLimit is the availability domain scoped limit on the total
+	// size of the block volumes, in GB.
+	TotalStorageGBLimit = "total-storage-gb"
+	// VolumeCountLimit is the availability domain scoped limit on the number
+	// of block volumes.
+	VolumeCountLimit = "volume-count"
+)
+
+// LimitsInterface defines the interface to the OCI limits service.
+type LimitsInterface interface {
+	// GetResourceAvailability returns the usage and the remaining availability
+	// of a service limit in the compartment, taking quotas into account. The
+	// availability domain is required for availability domain scoped limits.
+	GetResourceAvailability(ctx context.Context, serviceName, limitName, compartmentID, availabilityDomain string) (*limits.ResourceAvailability, error)
+}
+
+func (c *client) GetResourceAvailability(ctx context.Context, serviceName, limitName, compartmentID, availabilityDomain string) (*limits.ResourceAvailability, error) {
+	if !c.rateLimiter.Reader.TryAccept() {
+		return nil, RateLimitError(false, "GetResourceAvailability")
+	}
+
+	request := limits.GetResourceAvailabilityRequest{
+		ServiceName:     &serviceName,
+		LimitName:       &limitName,
+		CompartmentId:   &compartmentID,
+		RequestMetadata: c.requestMetadata,
+	}
+	if availabilityDomain != "" {
+		request.AvailabilityDomain = &availabilityDomain
+	}
+
+	resp, err := c.limits.GetResourceAvailability(ctx, request)
+	incRequestCounter(err, getVerb, resourceAvailabilityResource)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &resp.ResourceAvailability, nil
+}
@@ -44,6 +44,10 @@ type GenericLoadBalancerInterface interface {
 
 	GetCertificateByName(ctx context.Context, lbID, name string) (*GenericCertificate, error)
 	CreateCertificate(ctx context.Context, lbID string, cert *GenericCertificate) (string, error)
+	DeleteCertificate(ctx context.Context, lbID, name string) (string, error)
+
+	CreateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error)
+	UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error)
 
 	CreateBackendSet(ctx context.Context, lbID, name string, details *GenericBackendSetDetails) (string, error)
 	UpdateBackendSet(ctx context.Context, lbID, name string, details *GenericBackendSetDetails) (string, error)
@@ -121,6 +125,7 @@ func (c *loadbalancerClientStruct) CreateLoadBalancer(ctx context.Context, detai
 			ShapeDetails:            c.genericShapeDetailsToShapeDetails(details.ShapeDetails),
 			ReservedIps:             c.genericReservedIpToReservedIps(details.ReservedIps),
 			Certificates:            c.genericCertificatesToCertificates(details.Certificates),
+			SslCipherSuites:         genericSslCipherSuitesToSslCipherSuites(details.SslCipherSuites),
 			IsPrivate:               details.IsPrivate,
 			NetworkSecurityGroupIds: details.NetworkSecurityGroupIds,
 			Listeners:               c.genericListenerDetailsToListenerDetails(details.Listeners),
@@ -205,6 +210,69 @@ func (c *loadbalancerClientStruct) CreateCertificate(ctx context.Context, lbID s
 	return *resp.OpcWorkRequestId, nil
 }
 
+func (c *loadbalancerClientStruct) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	if !c.rateLimiter.Writer.TryAccept() {
+		return "", RateLimitError(true, "DeleteCertificate")
+	}
+
+	resp, err := c.loadbalancer.DeleteCertificate(ctx, loadbalancer.DeleteCertificateRequest{
+		LoadBalancerId:  &lbID,
+		CertificateName: &name,
+		RequestMetadata: c.requestMetadata,
+	})
+	incRequestCounter(err, deleteVerb, certificateResource)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return *resp.OpcWorkRequestId, nil
+}
+
+func (c *loadbalancerClientStruct) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error) {
+	if !c.rateLimiter.Writer.TryAccept() {
+		return "", RateLimitError(true, "CreateSSLCipherSuite")
+	}
+
+	resp, err := c.loadbalancer.CreateSSLCipherSuite(ctx, loadbalancer.CreateSSLCipherSuiteRequest{
+		LoadBalancerId: &lbID,
+		CreateSslCipherSuiteDetails: loadbalancer.CreateSslCipherSuiteDetails{
+			Name:    suite.Name,
+			Ciphers: suite.Ciphers,
+		},
+		RequestMetadata: c.requestMetadata,
+	})
+	incRequestCounter(err, createVerb, sslCipherSuiteResource)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return *resp.OpcWorkRequestId, nil
+}
+
+func (c *loadbalancerClientStruct) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error) {
+	if !c.rateLimiter.Writer.TryAccept() {
+		return "", RateLimitError(true, "UpdateSSLCipherSuite")
+	}
+
+	resp, err := c.loadbalancer.UpdateSSLCipherSuite(ctx, loadbalancer.UpdateSSLCipherSuiteRequest{
+		LoadBalancerId: &lbID,
+		Name:           suite.Name,
+		UpdateSslCipherSuiteDetails: loadbalancer.UpdateSslCipherSuiteDetails{
+			Ciphers: suite.Ciphers,
+		},
+		RequestMetadata: c.requestMetadata,
+	})
+	incRequestCounter(err, updateVerb, sslCipherSuiteResource)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return *resp.OpcWorkRequestId, nil
+}
+
 func (c *loadbalancerClientStruct) GetWorkRequest(ctx context.Context, id string) (*loadbalancer.WorkRequest, error) {
 	if !c.rateLimiter.Reader.TryAccept() {
 		return nil, RateLimitError(false, "GetWorkRequest")
@@ -241,8 +309,9 @@ func (c *loadbalancerClientStruct) CreateBackendSet(ctx context.Context, lbID st
 				TimeoutInMillis:  details.HealthChecker.TimeoutInMillis,
 				IntervalInMillis: details.HealthChecker.IntervalInMillis,
 			},
-			Policy:                          details.Policy,
-			SessionPersistenceConfiguration: getSessionPersistenceConfiguration(details.SessionPersistenceConfiguration),
+			Policy:                                  details.Policy,
+			SessionPersistenceConfiguration:         getSessionPersistenceConfiguration(details.SessionPersistenceConfiguration),
+			LbCookieSessionPersistenceConfiguration: getLbCookieSessionPersistenceConfiguration(details.LbCookieSessionPersistenceConfiguration),
 		},
 		RequestMetadata: c.requestMetadata,
 	}
@@ -279,8 +348,9 @@ func (c *loadbalancerClientStruct) UpdateBackendSet(ctx context.Context, lbID st
 				TimeoutInMillis:  details.HealthChecker.TimeoutInMillis,
 				IntervalInMillis: details.HealthChecker.IntervalInMillis,
 			},
-			Policy:                          details.Policy,
-			SessionPersistenceConfiguration: getSessionPersistenceConfiguration(details.SessionPersistenceConfiguration),
+			Policy:                                  details.Policy,
+			SessionPersistenceConfiguration:         getSessionPersistenceConfiguration(details.SessionPersistenceConfiguration),
+			LbCookieSessionPersistenceConfiguration: getLbCookieSessionPersistenceConfiguration(details.LbCookieSessionPersistenceConfiguration),
 		},
 		RequestMetadata: c.requestMetadata,
 	}
@@ -487,6 +557,7 @@ func (c *loadbalancerClientStruct) loadbalancerToGenericLoadbalancer(lb *loadbal
 		NetworkSecurityGroupIds: lb.NetworkSecurityGroupIds,
 		Listeners:               c.listenersToGenericListenerDetails(lb.Listeners),
 		Certificates:            c.certificateToGenericCertificateDetails(lb.Certificates),
+		SslCipherSuites:         sslCipherSuitesToGenericSslCipherSuites(lb.SslCipherSuites),
 		BackendSets:             c.backendSetsToGenericBackendSetDetails(lb.BackendSets),
 		FreeformTags:            lb.FreeformTags,
 		DefinedTags:             lb.DefinedTags,
@@ -627,6 +698,10 @@ func (c *loadbalancerClientStruct) backendSetsToGenericBackendSetDetails(backend
 		if v.SessionPersistenceConfiguration != nil {
 			backendDetailsStruct.SessionPersistenceConfiguration = getGenericSessionPersistenceConfiguration(v.SessionPersistenceConfiguration)
 		}
+
+		if v.LbCookieSessionPersistenceConfiguration != nil {
+			backendDetailsStruct.LbCookieSessionPersistenceConfiguration = getGenericLbCookieSessionPersistenceConfiguration(v.LbCookieSessionPersistenceConfiguration)
+		}
 		genericBackendSetDetails[k] = backendDetailsStruct
 	}
 
@@ -658,6 +733,10 @@ func (c *loadbalancerClientStruct) genericBackendSetDetailsToBackendSets(backend
 		if v.SessionPersistenceConfiguration != nil {
 			backendSetDetailsStruct.SessionPersistenceConfiguration = getSessionPersistenceConfiguration(v.SessionPersistenceConfiguration)
 		}
+
+		if v.LbCookieSessionPersistenceConfiguration != nil {
+			backendSetDetailsStruct.LbCookieSessionPersistenceConfiguration = getLbCookieSessionPersistenceConfiguration(v.LbCookieSessionPersistenceConfiguration)
+		}
 		backendSetDetails[k] = backendSetDetailsStruct
 	}
 	return backendSetDetails
@@ -708,6 +787,28 @@ func (c *loadbalancerClientStruct) genericCertificatesToCertificates(genericCert
 	return certificates
 }
 
+func sslCipherSuitesToGenericSslCipherSuites(suites map[string]loadbalancer.SslCipherSuite) map[string]GenericSslCipherSuite {
+	genericSuites := make(map[string]GenericSslCipherSuite)
+	for k, v := range suites {
+		genericSuites[k] = GenericSslCipherSuite{
+			Name:    v.Name,
+			Ciphers: v.Ciphers,
+		}
+	}
+	return genericSuites
+}
+
+func genericSslCipherSuitesToSslCipherSuites(genericSuites map[string]GenericSslCipherSuite) map[string]loadbalancer.SslCipherSuiteDetails {
+	suites := make(map[string]loadbalancer.SslCipherSuiteDetails)
+	for k, v := range genericSuites {
+		suites[k] = loadbalancer.SslCipherSuiteDetails{
+			Name:    v.Name,
+			Ciphers: v.Ciphers,
+		}
+	}
+	return suites
+}
+
 func (c *loadbalancerClientStruct) genericShapeDetailsToShapeDetails(details *GenericShapeDetails) *loadbalancer.ShapeDetails {
 	if details == nil {
 		return nil
@@ -807,6 +908,37 @@ func getGenericSessionPersistenceConfiguration(details *loadbalancer.SessionPers
 	}
 }
 
+func getLbCookieSessionPersistenceConfiguration(details *GenericLbCookieSessionPersistenceConfiguration) *loadbalancer.LbCookieSessionPersistenceConfigurationDetails {
+	if details == nil {
+		return nil
+	}
+	return &loadbalancer.LbCookieSessionPersistenceConfigurationDetails{
+		CookieName:      details.CookieName,
+		DisableFallback: details.DisableFallback,
+		Domain:          details.Domain,
+		Path:            details.Path,
+		MaxAgeInSeconds: details.MaxAgeInSeconds,
+		IsSecure:        details.IsSecure,
+		IsHttpOnly:      details.IsHttpOnly,
+	}
+}
+
+func getGenericLbCookieSessionPersistenceConfiguration(details *loadbalancer.LbCookieSessionPersistenceConfigurationDetails) *GenericLbCookieSessionPersistenceConfiguration {
+	if details == nil {
+		return nil
+	}
+
+	return &GenericLbCookieSessionPersistenceConfiguration{
+		CookieName:      details.CookieName,
+		DisableFallback: details.DisableFallback,
+		Domain:          details.Domain,
+		Path:            details.Path,
+		MaxAgeInSeconds: details.MaxAgeInSeconds,
+		IsSecure:        details.IsSecure,
+		IsHttpOnly:      details.IsHttpOnly,
+	}
+}
+
 func getListenerConnectionConfiguration(details *GenericConnectionConfiguration) *loadbalancer.ConnectionConfiguration {
 	var connectionConfiguration *loadbalancer.ConnectionConfiguration
 
@@ -134,6 +134,18 @@ func (c *networkLoadbalancer) CreateCertificate(ctx context.Context, lbID string
 	return "", nil
 }
 
+func (c *networkLoadbalancer) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	return "", nil
+}
+
+func (c *networkLoadbalancer) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
+func (c *networkLoadbalancer) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
 func (c *networkLoadbalancer) GetWorkRequest(ctx context.Context, id string) (*networkloadbalancer.WorkRequest, error) {
 	if !c.rateLimiter.Reader.TryAccept() {
 		return nil, RateLimitError(false, "GetWorkRequest")
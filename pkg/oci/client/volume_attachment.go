@@ -32,11 +32,25 @@ type VolumeAttachmentInterface interface {
 	// ATTACHING or ATTACHED and returns the first volume attachment found.
 	FindVolumeAttachment(ctx context.Context, compartmentID, volumeID string) (core.VolumeAttachment, error)
 
+	// FindVolumeAttachmentForInstance is like FindVolumeAttachment but only
+	// considers the attachments of the volume to the given instance. A shareable
+	// volume can be attached to several instances at once.
+	FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error)
+
+	// ListVolumeAttachments returns the attachments in the state ATTACHING or
+	// ATTACHED in the compartment, restricted to a single volume if volumeID is
+	// not empty.
+	ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error)
+
 	// AttachVolume attaches a block storage volume to the specified instance.
+	// A shareable attachment allows the volume to be attached to other instances
+	// as well; a read-only attachment prevents the instance from writing to it.
+	// With useChap the attachment requires CHAP authentication, its
+	// credentials are returned on the attachment.
 	// See https://docs.us-phoenix-1.oraclecloud.com/api/#/en/iaas/20160918/VolumeAttachment/AttachVolume
-	AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error)
+	AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error)
 
-	AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error)
+	AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error)
 
 	// WaitForVolumeAttached polls waiting for a OCI block volume to be in the
 	// ATTACHED state.
@@ -51,23 +65,40 @@ type VolumeAttachmentInterface interface {
 	WaitForVolumeDetached(ctx context.Context, attachmentID string) error
 
 	FindActiveVolumeAttachment(ctx context.Context, compartmentID, volumeID string) (core.VolumeAttachment, error)
+
+	// FindActiveVolumeAttachmentForInstance is like FindActiveVolumeAttachment
+	// but only considers the attachments of the volume to the given instance.
+	FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error)
 }
 
 var _ VolumeAttachmentInterface = &client{}
 
 func (c *client) FindVolumeAttachment(ctx context.Context, compartmentID, volumeID string) (core.VolumeAttachment, error) {
+	return c.findVolumeAttachment(ctx, compartmentID, volumeID, nil, isAttachingOrAttached)
+}
+
+func (c *client) FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return c.findVolumeAttachment(ctx, compartmentID, volumeID, &instanceID, isAttachingOrAttached)
+}
+
+func (c *client) ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error) {
 	var page *string
+	var attachments []core.VolumeAttachment
 	for {
 		if !c.rateLimiter.Reader.TryAccept() {
 			return nil, RateLimitError(false, "ListVolumeAttachments")
 		}
 
-		resp, err := c.compute.ListVolumeAttachments(ctx, core.ListVolumeAttachmentsRequest{
+		request := core.ListVolumeAttachmentsRequest{
 			CompartmentId:   &compartmentID,
-			VolumeId:        &volumeID,
 			Page:            page,
 			RequestMetadata: c.requestMetadata,
-		})
+		}
+		if volumeID != "" {
+			request.VolumeId = &volumeID
+		}
+
+		resp, err := c.compute.ListVolumeAttachments(ctx, request)
 		incRequestCounter(err, listVerb, volumeAttachmentResource)
 
 		if err != nil {
@@ -78,10 +109,67 @@ func (c *client) FindVolumeAttachment(ctx context.Context, compartmentID, volume
 			state := attachment.GetLifecycleState()
 			if state == core.VolumeAttachmentLifecycleStateAttaching ||
 				state == core.VolumeAttachmentLifecycleStateAttached {
-				return attachment, nil
+				attachments = append(attachments, attachment)
 			}
-			if state == core.VolumeAttachmentLifecycleStateDetaching {
-				return attachment, errors.WithStack(errNotFound)
+		}
+
+		if page = resp.OpcNextPage; page == nil {
+			break
+		}
+	}
+
+	return attachments, nil
+}
+
+// isAttachingOrAttached matches attachments in the state ATTACHING or ATTACHED.
+// An attachment that is DETACHING ends the search as not found.
+func isAttachingOrAttached(attachment core.VolumeAttachment) (bool, error) {
+	state := attachment.GetLifecycleState()
+	if state == core.VolumeAttachmentLifecycleStateAttaching ||
+		state == core.VolumeAttachmentLifecycleStateAttached {
+		return true, nil
+	}
+	if state == core.VolumeAttachmentLifecycleStateDetaching {
+		return true, errors.WithStack(errNotFound)
+	}
+	return false, nil
+}
+
+// isActive matches attachments in the state ATTACHING, ATTACHED or DETACHING.
+func isActive(attachment core.VolumeAttachment) (bool, error) {
+	state := attachment.GetLifecycleState()
+	return state == core.VolumeAttachmentLifecycleStateAttaching ||
+		state == core.VolumeAttachmentLifecycleStateAttached ||
+		state == core.VolumeAttachmentLifecycleStateDetaching, nil
+}
+
+// findVolumeAttachment pages through the attachments of the volume, restricted
+// to the given instance if instanceID is not nil, and returns the first one
+// matched along with the error returned by match.
+func (c *client) findVolumeAttachment(ctx context.Context, compartmentID, volumeID string, instanceID *string,
+	match func(core.VolumeAttachment) (bool, error)) (core.VolumeAttachment, error) {
+	var page *string
+	for {
+		if !c.rateLimiter.Reader.TryAccept() {
+			return nil, RateLimitError(false, "ListVolumeAttachments")
+		}
+
+		resp, err := c.compute.ListVolumeAttachments(ctx, core.ListVolumeAttachmentsRequest{
+			CompartmentId:   &compartmentID,
+			VolumeId:        &volumeID,
+			InstanceId:      instanceID,
+			Page:            page,
+			RequestMetadata: c.requestMetadata,
+		})
+		incRequestCounter(err, listVerb, volumeAttachmentResource)
+
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, attachment := range resp.Items {
+			if matched, err := match(attachment); matched {
+				return attachment, err
 			}
 		}
 
@@ -110,14 +198,17 @@ func (c *client) GetVolumeAttachment(ctx context.Context, id string) (core.Volum
 	return resp.VolumeAttachment, nil
 }
 
-func (c *client) AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error) {
+func (c *client) AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error) {
 	if !c.rateLimiter.Writer.TryAccept() {
 		return nil, RateLimitError(false, "")
 	}
 	resp, err := c.compute.AttachVolume(ctx, core.AttachVolumeRequest{
 		AttachVolumeDetails: core.AttachIScsiVolumeDetails{
-			InstanceId: &instanceID,
-			VolumeId:   &volumeID,
+			InstanceId:  &instanceID,
+			VolumeId:    &volumeID,
+			IsShareable: &isShareable,
+			IsReadOnly:  &isReadOnly,
+			UseChap:     &useChap,
 		},
 		RequestMetadata: c.requestMetadata,
 	})
@@ -129,7 +220,7 @@ func (c *client) AttachVolume(ctx context.Context, instanceID, volumeID string)
 	return resp.VolumeAttachment, nil
 }
 
-func (c *client) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error) {
+func (c *client) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error) {
 	if !c.rateLimiter.Writer.TryAccept() {
 		return nil, RateLimitError(false, "")
 	}
@@ -156,6 +247,8 @@ func (c *client) AttachParavirtualizedVolume(ctx context.Context, instanceID, vo
 			VolumeId:                       &volumeID,
 			IsPvEncryptionInTransitEnabled: &isPvEncryptionInTransitEnabled,
 			Device:                         device,
+			IsShareable:                    &isShareable,
+			IsReadOnly:                     &isReadOnly,
 		},
 		RequestMetadata: c.requestMetadata,
 	})
@@ -227,37 +320,9 @@ func (c *client) WaitForVolumeDetached(ctx context.Context, id string) error {
 }
 
 func (c *client) FindActiveVolumeAttachment(ctx context.Context, compartmentID, volumeID string) (core.VolumeAttachment, error) {
-	var page *string
-	for {
-		if !c.rateLimiter.Reader.TryAccept() {
-			return nil, RateLimitError(false, "ListVolumeAttachments")
-		}
-
-		resp, err := c.compute.ListVolumeAttachments(ctx, core.ListVolumeAttachmentsRequest{
-			CompartmentId:   &compartmentID,
-			VolumeId:        &volumeID,
-			Page:            page,
-			RequestMetadata: c.requestMetadata,
-		})
-		incRequestCounter(err, listVerb, volumeAttachmentResource)
-
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-
-		for _, attachment := range resp.Items {
-			state := attachment.GetLifecycleState()
-			if state == core.VolumeAttachmentLifecycleStateAttaching ||
-				state == core.VolumeAttachmentLifecycleStateAttached ||
-				state == core.VolumeAttachmentLifecycleStateDetaching {
-				return attachment, nil
-			}
-		}
-
-		if page = resp.OpcNextPage; page == nil {
-			break
-		}
-	}
+	return c.findVolumeAttachment(ctx, compartmentID, volumeID, nil, isActive)
+}
 
-	return nil, errors.WithStack(errNotFound)
+func (c *client) FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return c.findVolumeAttachment(ctx, compartmentID, volumeID, &instanceID, isActive)
 }
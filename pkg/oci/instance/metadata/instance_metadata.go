@@ -37,6 +37,7 @@ type InstanceMetadata struct {
 	CompartmentID       string `json:"compartmentId"`
 	Region              string `json:"region"`
 	CanonicalRegionName string `json:"canonicalRegionName"`
+	Shape               string `json:"shape"`
 }
 
 // Interface defines how consumers access OCI instance metadata.
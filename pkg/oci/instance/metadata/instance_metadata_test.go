@@ -59,6 +59,7 @@ func TestGetMetadata(t *testing.T) {
 					CompartmentID:       "ocid1.compartment.oc1..abc",
 					Region:              "phx",
 					CanonicalRegionName: "us-phoenix-1",
+					Shape:               "VM.Standard1.1",
 				},
 				err: "",
 			},
@@ -74,6 +75,7 @@ func TestGetMetadata(t *testing.T) {
 					CompartmentID:       "ocid1.compartment.oc1..abc",
 					Region:              "phx",
 					CanonicalRegionName: "us-phoenix-1",
+					Shape:               "VM.Standard1.1",
 				},
 				err: "",
 			},
@@ -104,6 +106,7 @@ func TestGetMetadata(t *testing.T) {
 					CompartmentID:       "ocid1.compartment.oc1..abc",
 					Region:              "phx",
 					CanonicalRegionName: "us-phoenix-1",
+					Shape:               "VM.Standard1.1",
 				},
 				err: "",
 			},
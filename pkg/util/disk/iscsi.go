@@ -17,10 +17,12 @@ package disk
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	"k8s.io/utils/exec"
@@ -40,6 +42,16 @@ const (
 	ISCSIIP = "iscsi_ip"
 	// ISCSIPORT is the map key to get or save iSCSI Port
 	ISCSIPORT = "iscsi_port"
+	// ISCSIMULTIPATHDEVICES is the map key to get or save the iSCSI paths of a
+	// multipath attachment, as comma separated <ip>:<port>-<IQN> entries
+	ISCSIMULTIPATHDEVICES = "iscsi_multipath_devices"
+
+	// sysClassISCSISessionPath lists the iSCSI sessions of the node.
+	sysClassISCSISessionPath = "/sys/class/iscsi_session"
+
+	// higherPerformanceQueueDepth is the queue depth of the iSCSI sessions of
+	// volumes with the higher performance level.
+	higherPerformanceQueueDepth = 128
 )
 
 // ErrMountPointNotFound is returned when a given path does not appear to be
@@ -72,6 +84,11 @@ type Interface interface {
 	// This function doesn't bother for checking the format again.
 	Mount(source string, target string, fstype string, options []string) error
 
+	// BindMountBlockDevice bind mounts the raw block device at devicePath onto
+	// the file at target, creating the file if needed. The device is neither
+	// probed nor formatted.
+	BindMountBlockDevice(devicePath string, target string, options []string) error
+
 	// Login logs into the iSCSI target.
 	Login() error
 
@@ -116,6 +133,11 @@ type Disk struct {
 	IQN  string
 	IPv4 string
 	Port int
+
+	// ChapUsername and ChapPassword are the CHAP credentials of the
+	// attachment. CHAP authentication is only configured when they are set.
+	ChapUsername string
+	ChapPassword string
 }
 
 func (sd *Disk) String() string {
@@ -127,6 +149,11 @@ func (sd *Disk) Target() string {
 	return fmt.Sprintf("%s:%d", sd.IPv4, sd.Port)
 }
 
+// DevicePath returns the /dev/disk/by-path link of the disk.
+func (sd *Disk) DevicePath() string {
+	return fmt.Sprintf("/dev/disk/by-path/ip-%s:%d-iscsi-%s-lun-1", sd.IPv4, sd.Port, sd.IQN)
+}
+
 func newWithMounter(logger *zap.SugaredLogger, mounter mount.Interface, iqn, ipv4 string, port int) Interface {
 	return &iSCSIMounter{
 		disk: &Disk{
@@ -225,7 +252,26 @@ func GetDiskPathFromMountPath(logger *zap.SugaredLogger, mountPath string) ([]st
 	if err != nil {
 		return nil, err
 	}
-	diskByPaths, err := diskByPathsForMountPoint(mountPoint)
+	var diskByPaths []string
+	if isDevice, _ := mounter.PathIsDevice(mountPath); isDevice {
+		// A raw block volume is a bind mount of the device file, which
+		// /proc/mounts reports against devtmpfs rather than the device itself.
+		diskByPaths, err = diskByPathsForDeviceFile(mountPath)
+		if err != nil {
+			// The multipath and LUKS devices have no by-path link, the disks
+			// underneath them have.
+			if device, mErr := deviceMapperDeviceForDeviceFile(mountPath); mErr == nil {
+				diskByPaths, err = diskByPathsForDeviceMapperDevice(device)
+			}
+		}
+	} else {
+		diskByPaths, err = diskByPathsForMountPoint(mountPoint)
+		if err != nil {
+			if paths, mErr := diskByPathsForDeviceMapperDevice(mountPoint.Device); mErr == nil {
+				diskByPaths, err = paths, nil
+			}
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +344,15 @@ func (c *iSCSIMounter) SetAutomaticLogin() error {
 	return nil
 }
 
+// Login logs into the iSCSI target, configuring CHAP authentication first if
+// the disk has CHAP credentials.
 func (c *iSCSIMounter) Login() error {
+	if c.disk.ChapUsername != "" {
+		if err := c.setChapCredentials(); err != nil {
+			return err
+		}
+	}
+
 	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Logging in.")
 
 	_, err := c.iscsiadm(
@@ -315,6 +369,37 @@ func (c *iSCSIMounter) Login() error {
 	return nil
 }
 
+// setChapCredentials sets the CHAP authentication of the iSCSI node record.
+// The credentials are neither logged nor included in the returned errors.
+func (c *iSCSIMounter) setChapCredentials() error {
+	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Configuring CHAP authentication.")
+
+	settings := []struct {
+		name  string
+		value string
+	}{
+		{name: "node.session.auth.authmethod", value: "CHAP"},
+		{name: "node.session.auth.username", value: c.disk.ChapUsername},
+		{name: "node.session.auth.password", value: c.disk.ChapPassword},
+	}
+	for _, setting := range settings {
+		_, err := c.iscsiadm(
+			"-m", "node",
+			"-T", c.disk.IQN,
+			"-p", c.disk.Target(),
+			"-o", "update",
+			"-n", setting.name,
+			"-v", setting.value)
+		if err != nil {
+			return fmt.Errorf("iscsi: error setting %s of target: %v", setting.name, err)
+		}
+	}
+
+	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Configured CHAP authentication.")
+
+	return nil
+}
+
 // Logout logs out the iSCSI target.
 // sudo iscsiadm -m node -T <IQN> -p <ip>:<port>  -u
 func (c *iSCSIMounter) Logout() error {
@@ -333,8 +418,11 @@ func (c *iSCSIMounter) Logout() error {
 	return nil
 }
 
+// UpdateQueueDepth sets the queue depth in the node record of the target,
+// which applies to new sessions, and on the devices of the sessions that are
+// already logged in.
 func (c *iSCSIMounter) UpdateQueueDepth() error {
-	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Updating queue depth to 128.")
+	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Infof("Updating queue depth to %d.", higherPerformanceQueueDepth)
 
 	_, err := c.iscsiadm(
 		"-m", "node",
@@ -342,16 +430,75 @@ func (c *iSCSIMounter) UpdateQueueDepth() error {
 		"-p", c.disk.Target(),
 		"-o", "update",
 		"-n", "node.session.queue_depth",
-		"-v", "128")
+		"-v", strconv.Itoa(higherPerformanceQueueDepth))
 	if err != nil {
 		return fmt.Errorf("iscsi: error updating queue depth in target: %v", err)
 	}
 
-	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Updated queue depth.")
+	devices, err := setSessionQueueDepth(sysClassISCSISessionPath, c.disk.IQN, higherPerformanceQueueDepth)
+	if err != nil {
+		return fmt.Errorf("iscsi: error updating queue depth of the logged in sessions: %v", err)
+	}
+
+	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target(), "devices", devices).Info("Updated queue depth.")
 
 	return nil
 }
 
+// HasISCSISession returns whether the node is logged in to the target with the
+// given IQN.
+func HasISCSISession(iqn string) (bool, error) {
+	sessions, err := findISCSISessions(sysClassISCSISessionPath, iqn)
+	return len(sessions) > 0, err
+}
+
+// findISCSISessions returns the directories of the sessions to the target with
+// the given IQN, below the iscsi_session class directory of sysfs.
+func findISCSISessions(sessionDir, iqn string) ([]string, error) {
+	candidates, err := filepath.Glob(filepath.Join(sessionDir, "session*"))
+	if err != nil {
+		return nil, err
+	}
+	var sessions []string
+	for _, session := range candidates {
+		targetName, err := ioutil.ReadFile(filepath.Join(session, "targetname"))
+		if os.IsNotExist(err) {
+			// The session is being torn down.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(targetName)) == iqn {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// setSessionQueueDepth sets the queue depth of the SCSI devices of the sessions
+// to the target with the given IQN, and returns the number of devices updated.
+func setSessionQueueDepth(sessionDir, iqn string, depth int) (int, error) {
+	sessions, err := findISCSISessions(sessionDir, iqn)
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for _, session := range sessions {
+		devices, err := filepath.Glob(filepath.Join(session, "device", "target*", "*", "queue_depth"))
+		if err != nil {
+			return updated, err
+		}
+		for _, device := range devices {
+			if err := ioutil.WriteFile(device, []byte(strconv.Itoa(depth)), 0644); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
 func (c *iSCSIMounter) RemoveFromDB() error {
 	c.logger.With("IQN", c.disk.IQN, "target", c.disk.Target()).Info("Removing from database.")
 	_, err := c.iscsiadm(
@@ -397,6 +544,38 @@ func (c *iSCSIMounter) Mount(source string, target string, fstype string, option
 func mnt(source string, target string, fstype string, options []string, sm *mount.SafeFormatAndMount) error {
 	return sm.Mount(source, target, fstype, options)
 }
+
+func (c *iSCSIMounter) BindMountBlockDevice(devicePath string, target string, options []string) error {
+	return bindMountBlockDevice(c.logger, c.mounter, devicePath, target, options)
+}
+
+// bindMountBlockDevice creates the target file (and its parent directory) and
+// bind mounts the device onto it. It is a no-op if target is already a mount
+// point.
+func bindMountBlockDevice(logger *zap.SugaredLogger, mounter mount.Interface, devicePath string, target string, options []string) error {
+	logger = logger.With("devicePath", devicePath, "target", target)
+	if _, err := getMountPointForPath(mounter, target); err == nil {
+		logger.Info("Block device is already bind mounted to the target.")
+		return nil
+	} else if err != ErrMountPointNotFound {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return fmt.Errorf("failed to create parent directory of %q: %v", target, err)
+	}
+	file, err := os.OpenFile(target, os.O_CREATE, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to create target file %q: %v", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close target file %q: %v", target, err)
+	}
+
+	options = append(options, "bind")
+	logger.With("options", options).Info("Bind mounting block device.")
+	return mounter.Mount(devicePath, target, "", options)
+}
 func (c *iSCSIMounter) UnmountPath(path string) error {
 	return mount.UnmountPath(c.logger, path, c.mounter)
 }
@@ -484,3 +663,43 @@ func diskByPathsForMountPoint(mountPoint mount.MountPoint) ([]string, error) {
 	}
 	return diskByPaths, nil
 }
+
+// diskByPathsForDeviceFile returns the /dev/disk/by-path links that resolve to
+// the same device node as the given (bind mounted) device file.
+func diskByPathsForDeviceFile(deviceFile string) ([]string, error) {
+	return findDiskByPathsForDeviceFile("/dev/disk/by-path/", deviceFile)
+}
+
+// findDiskByPathsForDeviceFile returns the links in dir that resolve to the
+// same device node as the device file. Dangling links, which are left for a
+// while after another volume is logged out, are skipped.
+func findDiskByPathsForDeviceFile(dir, deviceFile string) ([]string, error) {
+	deviceInfo, err := os.Stat(deviceFile)
+	if err != nil {
+		return nil, err
+	}
+	diskByPaths := []string{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			if path == dir {
+				return walkErr
+			}
+			return nil
+		}
+		targetInfo, err := os.Stat(path)
+		if err != nil {
+			return nil
+		}
+		if os.SameFile(deviceInfo, targetInfo) {
+			diskByPaths = append(diskByPaths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(diskByPaths) == 0 {
+		return nil, errors.New("disk by path link not found")
+	}
+	return diskByPaths, nil
+}
@@ -15,9 +15,17 @@
 package disk
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"go.uber.org/zap"
+	"k8s.io/utils/exec"
+
 	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
 )
 
@@ -29,6 +37,62 @@ func (ml *mockMountLister) List() ([]mount.MountPoint, error) {
 	return ml.mps, nil
 }
 
+// mockMounter records the mounts it is asked to perform.
+type mockMounter struct {
+	mount.Interface
+	mps []mount.MountPoint
+}
+
+func (m *mockMounter) List() ([]mount.MountPoint, error) {
+	return m.mps, nil
+}
+
+func (m *mockMounter) Mount(source string, target string, fstype string, options []string) error {
+	m.mps = append(m.mps, mount.MountPoint{Device: source, Path: target, Type: fstype, Opts: options})
+	return nil
+}
+
+// fakeRunner records the commands it is asked to run and what they are given
+// on stdin. The commands succeed with no output unless they have an output in
+// outputs or an error in errs, keyed by the command line.
+type fakeRunner struct {
+	exec.Interface
+	commands []string
+	stdin    []string
+	outputs  map[string]string
+	errs     map[string]error
+}
+
+func (r *fakeRunner) LookPath(file string) (string, error) {
+	return file, nil
+}
+
+func (r *fakeRunner) Command(cmd string, args ...string) exec.Cmd {
+	command := strings.Join(append([]string{cmd}, args...), " ")
+	r.commands = append(r.commands, command)
+	return &fakeCmd{runner: r, output: []byte(r.outputs[command]), err: r.errs[command]}
+}
+
+type fakeCmd struct {
+	exec.Cmd
+	runner *fakeRunner
+	output []byte
+	err    error
+}
+
+func (c *fakeCmd) SetStdin(in io.Reader) {
+	stdin, _ := ioutil.ReadAll(in)
+	c.runner.stdin = append(c.runner.stdin, string(stdin))
+}
+
+func (c *fakeCmd) Output() ([]byte, error) {
+	return c.output, c.err
+}
+
+func (c *fakeCmd) CombinedOutput() ([]byte, error) {
+	return c.output, c.err
+}
+
 func TestGetMountPointForPath(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -79,3 +143,169 @@ func TestGetMountPointForPath(t *testing.T) {
 		})
 	}
 }
+
+func TestBindMountBlockDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind-mount-block-device")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	target := filepath.Join(dir, "publish", "pod-uid")
+	mounter := &mockMounter{}
+
+	if err := bindMountBlockDevice(zap.S(), mounter, "/dev/sdb", target, []string{"ro"}); err != nil {
+		t.Fatalf("bindMountBlockDevice() => unexpected error: %v", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("expected target file %q to be created: %v", target, err)
+	}
+	if info.IsDir() {
+		t.Errorf("expected target %q to be a file, got a directory", target)
+	}
+	expected := []mount.MountPoint{{Device: "/dev/sdb", Path: target, Opts: []string{"ro", "bind"}}}
+	if !reflect.DeepEqual(mounter.mps, expected) {
+		t.Errorf("bindMountBlockDevice() mounted\n%+v\nExpected: %+v", mounter.mps, expected)
+	}
+
+	// A second call must not mount the device again.
+	if err := bindMountBlockDevice(zap.S(), mounter, "/dev/sdb", target, nil); err != nil {
+		t.Fatalf("bindMountBlockDevice() => unexpected error: %v", err)
+	}
+	if len(mounter.mps) != 1 {
+		t.Errorf("expected a single mount, got %+v", mounter.mps)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	testCases := []struct {
+		name     string
+		disk     *Disk
+		expected []string
+	}{
+		{
+			name: "without CHAP",
+			disk: &Disk{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.2", Port: 3260},
+			expected: []string{
+				"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -l",
+			},
+		},
+		{
+			name: "with CHAP",
+			disk: &Disk{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.2", Port: 3260,
+				ChapUsername: "ocid1.volume.oc1.abc", ChapPassword: "secret"},
+			expected: []string{
+				"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -o update -n node.session.auth.authmethod -v CHAP",
+				"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -o update -n node.session.auth.username -v ocid1.volume.oc1.abc",
+				"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -o update -n node.session.auth.password -v secret",
+				"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -l",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{}
+			mounter := &iSCSIMounter{disk: tt.disk, runner: runner, logger: zap.S()}
+			if err := mounter.Login(); err != nil {
+				t.Fatalf("Login() => unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(runner.commands, tt.expected) {
+				t.Errorf("Login() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestFindDiskByPathsForDeviceFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk-by-path-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	device := filepath.Join(dir, "sdb")
+	if err := ioutil.WriteFile(device, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	byPath := filepath.Join(dir, "by-path")
+	if err := os.Mkdir(byPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(byPath, "ip-169.254.2.2:3260-iscsi-iqn.2015-12.com.oracleiaas:volume-lun-1")
+	if err := os.Symlink(device, link); err != nil {
+		t.Fatal(err)
+	}
+	// The link of a volume that was just logged out.
+	if err := os.Symlink(filepath.Join(dir, "sdc"), filepath.Join(byPath, "ip-169.254.2.3:3260-iscsi-iqn.2015-12.com.oracleiaas:other-lun-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := findDiskByPathsForDeviceFile(byPath, device)
+	if err != nil {
+		t.Fatalf("findDiskByPathsForDeviceFile() => unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{link}) {
+		t.Errorf("findDiskByPathsForDeviceFile() => %v, expected %v", paths, []string{link})
+	}
+
+	if _, err := findDiskByPathsForDeviceFile(filepath.Join(dir, "missing"), device); err == nil {
+		t.Errorf("findDiskByPathsForDeviceFile() => expected an error for a missing directory")
+	}
+}
+
+func TestSetSessionQueueDepth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iscsi-session-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const iqn = "iqn.2015-12.com.oracleiaas:volume"
+	session := func(name, targetName string, luns ...string) {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name, "targetname"), []byte(targetName+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, lun := range luns {
+			device := filepath.Join(dir, name, "device", "target2:0:0", lun)
+			if err := os.MkdirAll(device, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(device, "queue_depth"), []byte("32\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	session("session1", iqn, "2:0:0:1")
+	session("session2", "iqn.2015-12.com.oracleiaas:other", "3:0:0:1")
+	session("session3", iqn, "4:0:0:1")
+
+	updated, err := setSessionQueueDepth(dir, iqn, 128)
+	if err != nil {
+		t.Fatalf("setSessionQueueDepth() => unexpected error: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("setSessionQueueDepth() => updated %d devices, expected 2", updated)
+	}
+	for device, expected := range map[string]string{
+		"session1/device/target2:0:0/2:0:0:1": "128",
+		"session2/device/target2:0:0/3:0:0:1": "32\n",
+		"session3/device/target2:0:0/4:0:0:1": "128",
+	} {
+		depth, err := ioutil.ReadFile(filepath.Join(dir, device, "queue_depth"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(depth) != expected {
+			t.Errorf("queue depth of %s is %q, expected %q", device, depth, expected)
+		}
+	}
+
+	if sessions, err := findISCSISessions(dir, "iqn.2015-12.com.oracleiaas:unknown"); err != nil || len(sessions) != 0 {
+		t.Errorf("findISCSISessions() => %v, %v, expected no sessions", sessions, err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/exec"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
+)
+
+const (
+	cryptsetupCommand = "cryptsetup"
+	blkidCommand      = "blkid"
+
+	// luksUUIDPrefix is the prefix of the device-mapper UUID of dm-crypt LUKS
+	// devices.
+	luksUUIDPrefix = "CRYPT-LUKS"
+
+	mapperPath = "/dev/mapper"
+
+	// cryptsetupDeviceInactive is the exit status of cryptsetup status for a
+	// mapper device that isn't open.
+	cryptsetupDeviceInactive = 4
+)
+
+// LUKS encrypts the devices of block volumes with LUKS. The passphrases are
+// given to cryptsetup on stdin, never on its command line.
+type LUKS interface {
+	// Open opens the LUKS container on the device as the named mapper device
+	// and returns the path of the mapper device. A device without any data is
+	// formatted as a LUKS container first, unless it is opened read-only.
+	Open(devicePath, name, passphrase string, readOnly bool) (string, error)
+
+	// Close closes the LUKS mapper device.
+	Close(mapperDevice string) error
+
+	// Resize grows the LUKS mapper device to the size of the device it is
+	// opened on.
+	Resize(mapperDevice string) error
+}
+
+// luksEncryptor implements LUKS.
+type luksEncryptor struct {
+	runner exec.Interface
+	logger *zap.SugaredLogger
+}
+
+// NewLUKS creates a new LUKS handler.
+func NewLUKS(logger *zap.SugaredLogger) LUKS {
+	return newLUKSWithRunner(logger, exec.New())
+}
+
+func newLUKSWithRunner(logger *zap.SugaredLogger, runner exec.Interface) *luksEncryptor {
+	return &luksEncryptor{
+		runner: runner,
+		logger: logger,
+	}
+}
+
+func (c *luksEncryptor) Open(devicePath, name, passphrase string, readOnly bool) (string, error) {
+	mapperDevice := filepath.Join(mapperPath, name)
+	logger := c.logger.With("devicePath", devicePath, "mapperDevice", mapperDevice)
+	if _, err := c.cryptsetup("", "status", name); err == nil {
+		logger.Info("LUKS device is already open.")
+		return mapperDevice, nil
+	}
+
+	isLUKS, err := c.isLUKS(devicePath)
+	if err != nil {
+		return "", err
+	}
+	if !isLUKS {
+		if readOnly {
+			return "", fmt.Errorf("device %s is not a LUKS container, it can't be formatted read-only", devicePath)
+		}
+		format, err := c.diskFormat(devicePath)
+		if err != nil {
+			return "", err
+		}
+		if format != "" {
+			return "", fmt.Errorf("refusing to format device %s as a LUKS container, it already contains %s", devicePath, format)
+		}
+		logger.Info("Formatting device as a LUKS container.")
+		if output, err := c.cryptsetup(passphrase, "luksFormat", "--type", "luks2", "--batch-mode", "--key-file", "-", devicePath); err != nil {
+			return "", fmt.Errorf("cryptsetup luksFormat of %s failed: %v. cryptsetup output: %s", devicePath, err, output)
+		}
+	}
+
+	// Without the kernel keyring the volume key stays in the device-mapper
+	// table, so that the device can be resized without the passphrase.
+	args := []string{"luksOpen", devicePath, name, "--key-file", "-", "--disable-keyring"}
+	if readOnly {
+		args = append(args, "--readonly")
+	}
+	logger.Info("Opening LUKS device.")
+	if output, err := c.cryptsetup(passphrase, args...); err != nil {
+		return "", fmt.Errorf("cryptsetup luksOpen of %s failed: %v. cryptsetup output: %s", devicePath, err, output)
+	}
+	return mapperDevice, nil
+}
+
+func (c *luksEncryptor) Close(mapperDevice string) error {
+	name := filepath.Base(mapperDevice)
+	if _, err := c.cryptsetup("", "status", name); err != nil {
+		if exitErr, ok := err.(exec.ExitError); ok && exitErr.ExitStatus() == cryptsetupDeviceInactive {
+			c.logger.With("mapperDevice", mapperDevice).Info("LUKS device is already closed.")
+			return nil
+		}
+	}
+
+	c.logger.With("mapperDevice", mapperDevice).Info("Closing LUKS device.")
+	if output, err := c.cryptsetup("", "luksClose", name); err != nil {
+		return fmt.Errorf("cryptsetup luksClose of %s failed: %v. cryptsetup output: %s", mapperDevice, err, output)
+	}
+	return nil
+}
+
+func (c *luksEncryptor) Resize(mapperDevice string) error {
+	c.logger.With("mapperDevice", mapperDevice).Info("Resizing LUKS device.")
+	if output, err := c.cryptsetup("", "resize", filepath.Base(mapperDevice)); err != nil {
+		return fmt.Errorf("cryptsetup resize of %s failed: %v. cryptsetup output: %s", mapperDevice, err, output)
+	}
+	return nil
+}
+
+// isLUKS returns whether the device is a LUKS container.
+func (c *luksEncryptor) isLUKS(devicePath string) (bool, error) {
+	_, err := c.cryptsetup("", "isLuks", devicePath)
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(exec.ExitError); ok && exitErr.ExitStatus() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check if %s is a LUKS container: %v", devicePath, err)
+}
+
+// diskFormat returns the filesystem or partition table type found on the
+// device by blkid, or an empty string if the device has neither.
+func (c *luksEncryptor) diskFormat(devicePath string) (string, error) {
+	output, err := c.runner.Command(blkidCommand, "-p", "-s", "TYPE", "-s", "PTTYPE", "-o", "value", devicePath).CombinedOutput()
+	if err != nil {
+		// blkid exits with 2 if the device has none of the tokens.
+		if exitErr, ok := err.(exec.ExitError); ok && exitErr.ExitStatus() == 2 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to determine the format of %s: %v", devicePath, err)
+	}
+	return strings.Join(strings.Fields(string(output)), ", "), nil
+}
+
+func (c *luksEncryptor) cryptsetup(stdin string, args ...string) (string, error) {
+	cmd := c.runner.Command(cryptsetupCommand, args...)
+	if stdin != "" {
+		cmd.SetStdin(strings.NewReader(stdin))
+	}
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// FindLUKSDevice returns the LUKS mapper device opened on the device, or an
+// empty string if there is none.
+func FindLUKSDevice(devicePath string) (string, error) {
+	device, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return "", err
+	}
+	return findDeviceMapperHolder(SysBlockPath, filepath.Base(device), luksUUIDPrefix)
+}
+
+// luksBackingDevice returns the device the dm-crypt LUKS device is opened on,
+// or an empty string if the device isn't a LUKS device.
+func luksBackingDevice(sysBlock, dmName string) (string, error) {
+	slaves, err := deviceMapperSlaves(sysBlock, dmName, luksUUIDPrefix)
+	if err != nil || len(slaves) == 0 {
+		return "", err
+	}
+	return filepath.Join("/dev", slaves[0]), nil
+}
+
+// diskByPathsForDeviceMapperDevice returns the /dev/disk/by-path links of the
+// disks underneath the LUKS or dm-multipath device.
+func diskByPathsForDeviceMapperDevice(device string) ([]string, error) {
+	target, err := filepath.EvalSymlinks(device)
+	if err != nil {
+		return nil, err
+	}
+	backing, err := luksBackingDevice(SysBlockPath, filepath.Base(target))
+	if err != nil {
+		return nil, err
+	}
+	if backing == "" {
+		return diskByPathsForMultipathDevice(device)
+	}
+	// A LUKS container can be on a multipath device itself.
+	if diskByPaths, err := diskByPathsForMountPoint(mount.MountPoint{Device: backing}); err == nil {
+		return diskByPaths, nil
+	}
+	return diskByPathsForMultipathDevice(backing)
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/exec"
+)
+
+const testDevice = "/dev/disk/by-path/ip-169.254.2.2:3260-iscsi-iqn.2015-12.com.oracleiaas:abc-lun-1"
+
+func exitError(code int) error {
+	return exec.CodeExitError{Err: errors.New("exit status"), Code: code}
+}
+
+func TestLUKSOpen(t *testing.T) {
+	var (
+		status     = "cryptsetup status luks-abc"
+		isLuks     = "cryptsetup isLuks " + testDevice
+		blkid      = "blkid -p -s TYPE -s PTTYPE -o value " + testDevice
+		luksFormat = "cryptsetup luksFormat --type luks2 --batch-mode --key-file - " + testDevice
+		luksOpen   = "cryptsetup luksOpen " + testDevice + " luks-abc --key-file - --disable-keyring"
+	)
+	testCases := map[string]struct {
+		readOnly bool
+		outputs  map[string]string
+		errs     map[string]error
+		commands []string
+		stdin    []string
+		wantErr  bool
+	}{
+		"empty device is formatted and opened": {
+			errs:     map[string]error{status: exitError(4), isLuks: exitError(1), blkid: exitError(2)},
+			commands: []string{status, isLuks, blkid, luksFormat, luksOpen},
+			stdin:    []string{"passphrase", "passphrase"},
+		},
+		"LUKS container is opened": {
+			errs:     map[string]error{status: exitError(4)},
+			commands: []string{status, isLuks, luksOpen},
+			stdin:    []string{"passphrase"},
+		},
+		"open LUKS device is left alone": {
+			commands: []string{status},
+		},
+		"LUKS container is opened read-only": {
+			readOnly: true,
+			errs:     map[string]error{status: exitError(4)},
+			commands: []string{status, isLuks, luksOpen + " --readonly"},
+			stdin:    []string{"passphrase"},
+		},
+		"empty device is not formatted read-only": {
+			readOnly: true,
+			errs:     map[string]error{status: exitError(4), isLuks: exitError(1)},
+			commands: []string{status, isLuks},
+			wantErr:  true,
+		},
+		"device with a filesystem is not formatted": {
+			outputs:  map[string]string{blkid: "ext4\n"},
+			errs:     map[string]error{status: exitError(4), isLuks: exitError(1)},
+			commands: []string{status, isLuks, blkid},
+			wantErr:  true,
+		},
+		"failure to check for a LUKS container": {
+			errs:     map[string]error{status: exitError(4), isLuks: exitError(4)},
+			commands: []string{status, isLuks},
+			wantErr:  true,
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			runner := &fakeRunner{outputs: tt.outputs, errs: tt.errs}
+			luks := newLUKSWithRunner(zap.S(), runner)
+
+			mapperDevice, err := luks.Open(testDevice, "luks-abc", "passphrase", tt.readOnly)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Open() => expected an error")
+				}
+			} else if err != nil {
+				t.Errorf("Open() => unexpected error: %v", err)
+			} else if mapperDevice != "/dev/mapper/luks-abc" {
+				t.Errorf("Open() => %s, expected /dev/mapper/luks-abc", mapperDevice)
+			}
+			if !reflect.DeepEqual(runner.commands, tt.commands) {
+				t.Errorf("Open() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(tt.commands, "\n"))
+			}
+			if !reflect.DeepEqual(runner.stdin, tt.stdin) {
+				t.Errorf("Open() gave cryptsetup %q on stdin, expected %q", runner.stdin, tt.stdin)
+			}
+		})
+	}
+}
+
+func TestLUKSClose(t *testing.T) {
+	testCases := map[string]struct {
+		errs     map[string]error
+		commands []string
+	}{
+		"open device is closed": {
+			commands: []string{"cryptsetup status luks-abc", "cryptsetup luksClose luks-abc"},
+		},
+		"closed device is left alone": {
+			errs:     map[string]error{"cryptsetup status luks-abc": exitError(4)},
+			commands: []string{"cryptsetup status luks-abc"},
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			runner := &fakeRunner{errs: tt.errs}
+			if err := newLUKSWithRunner(zap.S(), runner).Close("/dev/mapper/luks-abc"); err != nil {
+				t.Fatalf("Close() => unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(runner.commands, tt.commands) {
+				t.Errorf("Close() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(tt.commands, "\n"))
+			}
+		})
+	}
+}
+
+func TestLUKSResize(t *testing.T) {
+	runner := &fakeRunner{}
+	if err := newLUKSWithRunner(zap.S(), runner).Resize("/dev/mapper/luks-abc"); err != nil {
+		t.Fatalf("Resize() => unexpected error: %v", err)
+	}
+	if expected := []string{"cryptsetup resize luks-abc"}; !reflect.DeepEqual(runner.commands, expected) {
+		t.Errorf("Resize() ran %v, expected %v", runner.commands, expected)
+	}
+}
+
+func TestFindLUKSDevice(t *testing.T) {
+	sysBlock := newFakeSysBlock(t)
+	defer os.RemoveAll(sysBlock)
+
+	testCases := map[string]string{
+		"sdb": "",
+		"sdd": "/dev/mapper/luks-abc",
+		"sde": "",
+	}
+	for deviceName, expected := range testCases {
+		device, err := findDeviceMapperHolder(sysBlock, deviceName, luksUUIDPrefix)
+		if err != nil {
+			t.Fatalf("findDeviceMapperHolder(%s) => unexpected error: %v", deviceName, err)
+		}
+		if device != expected {
+			t.Errorf("findDeviceMapperHolder(%s) => %q, expected %q", deviceName, device, expected)
+		}
+	}
+}
+
+func TestLUKSBackingDevice(t *testing.T) {
+	sysBlock := newFakeSysBlock(t)
+	defer os.RemoveAll(sysBlock)
+
+	testCases := map[string]string{
+		"dm-0": "",
+		"dm-1": "/dev/sdd",
+	}
+	for dmName, expected := range testCases {
+		device, err := luksBackingDevice(sysBlock, dmName)
+		if err != nil {
+			t.Fatalf("luksBackingDevice(%s) => unexpected error: %v", dmName, err)
+		}
+		if device != expected {
+			t.Errorf("luksBackingDevice(%s) => %q, expected %q", dmName, device, expected)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/exec"
+
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/mount"
+)
+
+const (
+	multipathdCommand = "multipathd"
+
+	// SysBlockPath lists the block devices of the node, their dm-multipath
+	// holders and slaves.
+	SysBlockPath = "/sys/block"
+
+	// multipathUUIDPrefix is the prefix of the device-mapper UUID of
+	// dm-multipath devices, telling them apart from e.g. dm-crypt devices.
+	multipathUUIDPrefix = "mpath-"
+)
+
+// multipathISCSIMounter implements Interface for a volume attached over
+// several iSCSI paths which dm-multipath combines into a single device. The
+// iSCSI operations are run for every path, the mount operations on the
+// multipath device.
+type multipathISCSIMounter struct {
+	*iSCSIMounter
+
+	paths        []*iSCSIMounter
+	sysBlockPath string
+}
+
+// NewFromMultipathISCSIDisks creates a new iSCSI handler for a multipath
+// attachment with a Disk per path.
+func NewFromMultipathISCSIDisks(logger *zap.SugaredLogger, disks []*Disk) Interface {
+	return newMultipathWithRunner(logger, mount.New(logger, mountCommand), exec.New(), disks)
+}
+
+func newMultipathWithRunner(logger *zap.SugaredLogger, mounter mount.Interface, runner exec.Interface, disks []*Disk) *multipathISCSIMounter {
+	paths := make([]*iSCSIMounter, 0, len(disks))
+	for _, sd := range disks {
+		paths = append(paths, &iSCSIMounter{
+			disk:    sd,
+			runner:  runner,
+			mounter: mounter,
+			logger:  logger,
+		})
+	}
+	return &multipathISCSIMounter{
+		iSCSIMounter: paths[0],
+		paths:        paths,
+		sysBlockPath: SysBlockPath,
+	}
+}
+
+func (c *multipathISCSIMounter) AddToDB() error {
+	for _, path := range c.paths {
+		if err := path.AddToDB(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *multipathISCSIMounter) SetAutomaticLogin() error {
+	for _, path := range c.paths {
+		if err := path.SetAutomaticLogin(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *multipathISCSIMounter) UpdateQueueDepth() error {
+	for _, path := range c.paths {
+		if err := path.UpdateQueueDepth(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *multipathISCSIMounter) Login() error {
+	for _, path := range c.paths {
+		if err := path.Login(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Logout removes the multipath device, so that it doesn't queue IO for the
+// paths going away, and logs out of every path. It tries all the paths even
+// if logging out of one fails.
+func (c *multipathISCSIMounter) Logout() error {
+	if device, err := FindMultipathHolder(c.sysBlockPath, c.pathDeviceName()); err != nil {
+		c.logger.With(zap.Error(err)).Warn("Failed to find the multipath device.")
+	} else if device != "" {
+		c.logger.With("device", device).Info("Removing multipath device.")
+		if output, err := c.runner.Command(multipathdCommand, "del", "map", filepath.Base(device)).CombinedOutput(); err != nil {
+			c.logger.With(zap.Error(err), "output", string(output)).Warn("Failed to remove the multipath device.")
+		}
+	}
+
+	var logoutErr error
+	for _, path := range c.paths {
+		if err := path.Logout(); err != nil {
+			c.logger.With(zap.Error(err), "target", path.disk.Target()).Error("Failed to log out of path.")
+			logoutErr = err
+		}
+	}
+	return logoutErr
+}
+
+func (c *multipathISCSIMounter) RemoveFromDB() error {
+	for _, path := range c.paths {
+		if err := path.RemoveFromDB(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rescan rescans every path for the new size of the volume and resizes the
+// multipath device to it.
+func (c *multipathISCSIMounter) Rescan(devicePath string) error {
+	for _, path := range c.paths {
+		c.logger.With("IQN", path.disk.IQN, "target", path.disk.Target()).Info("Rescanning path.")
+		if _, err := path.iscsiadm(
+			"-m", "node",
+			"-T", path.disk.IQN,
+			"-p", path.disk.Target(),
+			"-R"); err != nil {
+			return fmt.Errorf("iscsi: error rescanning target: %v", err)
+		}
+	}
+
+	c.logger.With("devicePath", devicePath).Info("Resizing multipath device.")
+	output, err := c.runner.Command(multipathdCommand, "resize", "map", filepath.Base(devicePath)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("resize of multipath device %s failed: %v. multipathd output: %s", devicePath, err, string(output))
+	}
+	return nil
+}
+
+// pathDeviceName returns the name of the SCSI disk of the first path, or an
+// empty string if it doesn't exist.
+func (c *multipathISCSIMounter) pathDeviceName() string {
+	device, err := filepath.EvalSymlinks(c.disk.DevicePath())
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(device)
+}
+
+// FindMultipathDevice returns the dm-multipath device holding the iSCSI disk
+// at the given /dev/disk/by-path link, or an empty string if the disk isn't
+// part of a multipath device.
+func FindMultipathDevice(diskByPath string) (string, error) {
+	device, err := filepath.EvalSymlinks(diskByPath)
+	if err != nil {
+		return "", err
+	}
+	return FindMultipathHolder(SysBlockPath, filepath.Base(device))
+}
+
+// FindMultipathHolder returns the /dev/mapper path of the dm-multipath device
+// holding the disk with the given name in the sysfs block directory, or an
+// empty string if the disk isn't part of a multipath device. Other
+// device-mapper holders, such as LUKS or LVM devices, are ignored.
+func FindMultipathHolder(sysBlock, deviceName string) (string, error) {
+	return findDeviceMapperHolder(sysBlock, deviceName, multipathUUIDPrefix)
+}
+
+// findDeviceMapperHolder returns the /dev/mapper path of the device-mapper
+// device holding the device whose UUID has the given prefix, or an empty
+// string if there is none.
+func findDeviceMapperHolder(sysBlock, deviceName, uuidPrefix string) (string, error) {
+	if deviceName == "" {
+		return "", nil
+	}
+	holders, err := ioutil.ReadDir(filepath.Join(sysBlock, deviceName, "holders"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, holder := range holders {
+		if !isDeviceMapperDevice(sysBlock, holder.Name(), uuidPrefix) {
+			continue
+		}
+		name, err := ioutil.ReadFile(filepath.Join(sysBlock, holder.Name(), "dm", "name"))
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(mapperPath, strings.TrimSpace(string(name))), nil
+	}
+	return "", nil
+}
+
+// isDeviceMapperDevice returns whether the device-mapper UUID of the device has
+// the given prefix, which tells the dm-multipath and dm-crypt devices apart.
+func isDeviceMapperDevice(sysBlock, dmName, uuidPrefix string) bool {
+	uuid, err := ioutil.ReadFile(filepath.Join(sysBlock, dmName, "dm", "uuid"))
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(uuid)), uuidPrefix)
+}
+
+// multipathSlaves returns the names of the disks of the dm-multipath device,
+// or nil if the device isn't a multipath device.
+func multipathSlaves(sysBlock, dmName string) ([]string, error) {
+	return deviceMapperSlaves(sysBlock, dmName, multipathUUIDPrefix)
+}
+
+// deviceMapperSlaves returns the names of the devices underneath the
+// device-mapper device, or nil if its UUID doesn't have the given prefix.
+func deviceMapperSlaves(sysBlock, dmName, uuidPrefix string) ([]string, error) {
+	if !isDeviceMapperDevice(sysBlock, dmName, uuidPrefix) {
+		return nil, nil
+	}
+	slaves, err := ioutil.ReadDir(filepath.Join(sysBlock, dmName, "slaves"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(slaves))
+	for _, slave := range slaves {
+		names = append(names, slave.Name())
+	}
+	return names, nil
+}
+
+// diskByPathsForMultipathDevice returns the /dev/disk/by-path links of the
+// paths of the dm-multipath device.
+func diskByPathsForMultipathDevice(device string) ([]string, error) {
+	target, err := filepath.EvalSymlinks(device)
+	if err != nil {
+		return nil, err
+	}
+	slaves, err := multipathSlaves(SysBlockPath, filepath.Base(target))
+	if err != nil {
+		return nil, err
+	}
+	if len(slaves) == 0 {
+		return nil, fmt.Errorf("%s is not a multipath device", device)
+	}
+
+	var diskByPaths []string
+	for _, slave := range slaves {
+		paths, err := diskByPathsForMountPoint(mount.MountPoint{Device: filepath.Join("/dev", slave)})
+		if err != nil {
+			return nil, err
+		}
+		diskByPaths = append(diskByPaths, paths...)
+	}
+	return diskByPaths, nil
+}
+
+// deviceMapperDeviceForDeviceFile returns the device-mapper device that is the
+// same device node as the given (bind mounted) device file.
+func deviceMapperDeviceForDeviceFile(deviceFile string) (string, error) {
+	deviceInfo, err := os.Stat(deviceFile)
+	if err != nil {
+		return "", err
+	}
+	devices, err := filepath.Glob(filepath.Join(SysBlockPath, "dm-*"))
+	if err != nil {
+		return "", err
+	}
+	for _, device := range devices {
+		targetInfo, err := os.Stat(filepath.Join("/dev", filepath.Base(device)))
+		if err != nil {
+			continue
+		}
+		if os.SameFile(deviceInfo, targetInfo) {
+			return filepath.Join("/dev", filepath.Base(device)), nil
+		}
+	}
+	return "", fmt.Errorf("device-mapper device not found for %s", deviceFile)
+}
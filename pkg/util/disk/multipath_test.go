@@ -0,0 +1,153 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+var multipathDisks = []*Disk{
+	{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.2", Port: 3260},
+	{IQN: "iqn.2015-12.com.oracleiaas:abc", IPv4: "169.254.2.3", Port: 3260},
+}
+
+func TestMultipathLogin(t *testing.T) {
+	runner := &fakeRunner{}
+	mounter := newMultipathWithRunner(zap.S(), &mockMounter{}, runner, multipathDisks)
+
+	if err := mounter.Login(); err != nil {
+		t.Fatalf("Login() => unexpected error: %v", err)
+	}
+	expected := []string{
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -l",
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.3:3260 -l",
+	}
+	if !reflect.DeepEqual(runner.commands, expected) {
+		t.Errorf("Login() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestMultipathLogout(t *testing.T) {
+	runner := &fakeRunner{}
+	mounter := newMultipathWithRunner(zap.S(), &mockMounter{}, runner, multipathDisks)
+
+	if err := mounter.Logout(); err != nil {
+		t.Fatalf("Logout() => unexpected error: %v", err)
+	}
+	expected := []string{
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -u",
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.3:3260 -u",
+	}
+	if !reflect.DeepEqual(runner.commands, expected) {
+		t.Errorf("Logout() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestMultipathRescan(t *testing.T) {
+	runner := &fakeRunner{}
+	mounter := newMultipathWithRunner(zap.S(), &mockMounter{}, runner, multipathDisks)
+
+	if err := mounter.Rescan("/dev/mapper/mpatha"); err != nil {
+		t.Fatalf("Rescan() => unexpected error: %v", err)
+	}
+	expected := []string{
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.2:3260 -R",
+		"iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abc -p 169.254.2.3:3260 -R",
+		"multipathd resize map mpatha",
+	}
+	if !reflect.DeepEqual(runner.commands, expected) {
+		t.Errorf("Rescan() ran\n%s\nExpected:\n%s", strings.Join(runner.commands, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// newFakeSysBlock creates a sysfs block directory with a multipath device
+// dm-0 (mpatha) over sdb and sdc, and a dm-crypt device dm-1 over sdd.
+func newFakeSysBlock(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "sys-block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("dm-0/dm/name", "mpatha\n")
+	write("dm-0/dm/uuid", "mpath-360f1b5e8f0a7492ea5a7e4b2f2b6e4a1\n")
+	write("dm-0/slaves/sdb", "")
+	write("dm-0/slaves/sdc", "")
+	write("sdb/holders/dm-0", "")
+	write("sdc/holders/dm-0", "")
+	write("dm-1/dm/name", "luks-abc\n")
+	write("dm-1/dm/uuid", "CRYPT-LUKS2-abc\n")
+	write("dm-1/slaves/sdd", "")
+	write("sdd/holders/dm-1", "")
+	write("sde/size", "")
+	return dir
+}
+
+func TestFindMultipathDevice(t *testing.T) {
+	sysBlock := newFakeSysBlock(t)
+	defer os.RemoveAll(sysBlock)
+
+	testCases := map[string]string{
+		"sdb": "/dev/mapper/mpatha",
+		"sdc": "/dev/mapper/mpatha",
+		"sdd": "",
+		"sde": "",
+		"sdf": "",
+	}
+	for deviceName, expected := range testCases {
+		device, err := FindMultipathHolder(sysBlock, deviceName)
+		if err != nil {
+			t.Fatalf("FindMultipathHolder(%s) => unexpected error: %v", deviceName, err)
+		}
+		if device != expected {
+			t.Errorf("FindMultipathHolder(%s) => %q, expected %q", deviceName, device, expected)
+		}
+	}
+}
+
+func TestMultipathSlaves(t *testing.T) {
+	sysBlock := newFakeSysBlock(t)
+	defer os.RemoveAll(sysBlock)
+
+	slaves, err := multipathSlaves(sysBlock, "dm-0")
+	if err != nil {
+		t.Fatalf("multipathSlaves() => unexpected error: %v", err)
+	}
+	if expected := []string{"sdb", "sdc"}; !reflect.DeepEqual(slaves, expected) {
+		t.Errorf("multipathSlaves() => %v, expected %v", slaves, expected)
+	}
+
+	slaves, err = multipathSlaves(sysBlock, "dm-1")
+	if err != nil {
+		t.Fatalf("multipathSlaves() => unexpected error: %v", err)
+	}
+	if len(slaves) != 0 {
+		t.Errorf("multipathSlaves() => %v for a dm-crypt device, expected none", slaves)
+	}
+}
@@ -92,6 +92,10 @@ func (c *pvMounter) Mount(source string, target string, fstype string, options [
 	return mnt(source, target, fstype, options, safeMounter)
 }
 
+func (c *pvMounter) BindMountBlockDevice(devicePath string, target string, options []string) error {
+	return bindMountBlockDevice(c.logger, c.mounter, devicePath, target, options)
+}
+
 func (c *pvMounter) UnmountPath(path string) error {
 	return mount.UnmountPath(c.logger, path, c.mounter)
 }
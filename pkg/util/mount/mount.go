@@ -75,6 +75,16 @@ type MountPoint struct {
 	Pass   int
 }
 
+// FilesystemStats is the space and inode usage of a mounted filesystem.
+type FilesystemStats struct {
+	TotalBytes     int64
+	AvailableBytes int64
+	UsedBytes      int64
+	TotalInodes    int64
+	FreeInodes     int64
+	UsedInodes     int64
+}
+
 // SafeFormatAndMount probes a device to see if it is formatted.
 // Namely it checks to see if a file system is present. If so it
 // mounts it otherwise the device is formatted first then mounted.
@@ -90,6 +100,7 @@ type SafeFormatAndMount struct {
 // disk is already formatted or it is being mounted as read-only, it
 // will be mounted without formatting.
 func (mounter *SafeFormatAndMount) FormatAndMount(source string, target string, fstype string, options []string) error {
+	options = append(options, fsTypeMountOptions(fstype)...)
 	// Don't attempt to format if mounting as readonly. Go straight to mounting.
 	for _, option := range options {
 		if option == "ro" {
@@ -109,6 +120,28 @@ func (mounter *SafeFormatAndMount) Mount(source string, target string, fstype st
 	return mounter.formatAndMount(source, target, fstype, options)
 }
 
+// fsTypeMountOptions returns the mount options the filesystem needs. XFS
+// refuses to mount a filesystem whose UUID is already mounted, which is the
+// case for a volume restored from a snapshot or cloned from a volume that is
+// mounted on the same node.
+func fsTypeMountOptions(fstype string) []string {
+	if fstype == "xfs" {
+		return []string{"nouuid"}
+	}
+	return nil
+}
+
+// mkfsArgs returns the arguments to format the device with the filesystem.
+func mkfsArgs(fstype string, source string) []string {
+	switch fstype {
+	case "ext3", "ext4":
+		return []string{"-F", source}
+	case "xfs", "btrfs":
+		return []string{"-f", source}
+	}
+	return []string{source}
+}
+
 func (mounter *SafeFormatAndMount) Resize(devicePath string, volumePath string) (bool, error) {
 	return mounter.resize(devicePath, volumePath)
 }
@@ -242,11 +242,13 @@ func (mounter *Mounter) IsLikelyNotMountPoint(file string) (bool, error) {
 	if err != nil {
 		return true, err
 	}
-	rootStat, err := os.Lstat(file + "/..")
+	// Stat the parent rather than file + "/.." so that bind mounted files,
+	// such as raw block devices, are handled as well as directories.
+	rootStat, err := os.Stat(filepath.Dir(strings.TrimSuffix(file, "/")))
 	if err != nil {
 		return true, err
 	}
-	// If the directory has a different device as parent, then it is a mountpoint.
+	// If the path has a different device as parent, then it is a mountpoint.
 	if stat.Sys().(*syscall.Stat_t).Dev != rootStat.Sys().(*syscall.Stat_t).Dev {
 		return false, nil
 	}
@@ -486,15 +488,11 @@ func (mounter *SafeFormatAndMount) formatAndMount(source string, target string,
 		}
 		if existingFormat == "" {
 			// Disk is unformatted so format it.
-			args = []string{source}
 			// Use 'ext4' as the default
 			if len(fstype) == 0 {
 				fstype = "ext4"
 			}
-
-			if fstype == "ext4" || fstype == "ext3" {
-				args = []string{"-F", source}
-			}
+			args = mkfsArgs(fstype, source)
 			mounter.Logger.With("argruments", args).Info("Disk appears to be unformatted, attempting to format.")
 			cmd := mounter.Runner.Command("mkfs."+fstype, args...)
 			_, err := cmd.CombinedOutput()
@@ -593,6 +591,8 @@ func (mounter *SafeFormatAndMount) resize(devicePath string, volumePath string)
 		return mounter.extResize(devicePath)
 	case "xfs":
 		return mounter.xfsResize(volumePath)
+	case "btrfs":
+		return mounter.btrfsResize(volumePath)
 	}
 	return false, fmt.Errorf("resize of format %s is not supported for device %s mounted at %s", format, devicePath, volumePath)
 }
@@ -615,7 +615,7 @@ func (mounter *SafeFormatAndMount) xfsResize(deviceMountPath string) (bool, erro
 	cmd := mounter.Runner.Command("xfs_growfs", args...)
 	output, err := cmd.CombinedOutput()
 	if err == nil {
-		mounter.Logger.With("deviceMountPath", deviceMountPath).Infof("Device %s resized successfully")
+		mounter.Logger.With("deviceMountPath", deviceMountPath).Infof("Device resized successfully")
 		return true, nil
 	}
 
@@ -623,6 +623,19 @@ func (mounter *SafeFormatAndMount) xfsResize(deviceMountPath string) (bool, erro
 	return false, resizeError
 }
 
+func (mounter *SafeFormatAndMount) btrfsResize(deviceMountPath string) (bool, error) {
+	args := []string{"filesystem", "resize", "max", deviceMountPath}
+	cmd := mounter.Runner.Command("btrfs", args...)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		mounter.Logger.With("deviceMountPath", deviceMountPath).Infof("Device resized successfully")
+		return true, nil
+	}
+
+	resizeError := fmt.Errorf("resize of device %s failed: %v. btrfs output: %s", deviceMountPath, err, string(output))
+	return false, resizeError
+}
+
 func (mounter *SafeFormatAndMount) rescan(devicePath string) error {
 
 	lsblkargs := []string{"-n", "-o", "NAME", devicePath}
@@ -675,3 +688,56 @@ func (mounter *SafeFormatAndMount) getBlockSizeBytes(devicePath string) (int64,
 	}
 	return gotSizeBytes, nil
 }
+
+// GetFilesystemStats returns the space and inode usage of the filesystem
+// mounted at path.
+func GetFilesystemStats(path string) (*FilesystemStats, error) {
+	statfs := &syscall.Statfs_t{}
+	if err := syscall.Statfs(path, statfs); err != nil {
+		return nil, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	return &FilesystemStats{
+		TotalBytes:     int64(statfs.Blocks) * int64(statfs.Bsize),
+		AvailableBytes: int64(statfs.Bavail) * int64(statfs.Bsize),
+		UsedBytes:      int64(statfs.Blocks-statfs.Bfree) * int64(statfs.Bsize),
+		TotalInodes:    int64(statfs.Files),
+		FreeInodes:     int64(statfs.Ffree),
+		UsedInodes:     int64(statfs.Files - statfs.Ffree),
+	}, nil
+}
+
+// GetBlockDeviceSizeBytes returns the size of the block device at path
+// without shelling out to blockdev, path may be a bind mount of the device.
+func GetBlockDeviceSizeBytes(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return -1, err
+	}
+	defer f.Close()
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1, &os.PathError{Op: "seek", Path: path, Err: err}
+	}
+	return size, nil
+}
+
+// IsCorruptedMnt returns true if err is returned by a stat on a mount point
+// whose backing device or remote server is gone, e.g. a stale NFS handle.
+func IsCorruptedMnt(err error) bool {
+	if err == nil {
+		return false
+	}
+	var underlyingError error
+	switch pe := err.(type) {
+	case *os.PathError:
+		underlyingError = pe.Err
+	case *os.LinkError:
+		underlyingError = pe.Err
+	case *os.SyscallError:
+		underlyingError = pe.Err
+	default:
+		underlyingError = err
+	}
+	return underlyingError == syscall.ENOTCONN || underlyingError == syscall.ESTALE ||
+		underlyingError == syscall.EIO || underlyingError == syscall.EACCES || underlyingError == syscall.EHOSTDOWN
+}
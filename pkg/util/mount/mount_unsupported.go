@@ -19,6 +19,8 @@ limitations under the License.
 package mount
 
 import (
+	"errors"
+
 	"go.uber.org/zap"
 )
 
@@ -75,6 +77,10 @@ func (mounter *SafeFormatAndMount) xfsResize(deviceMountPath string) (bool, erro
 	return true, nil
 }
 
+func (mounter *SafeFormatAndMount) btrfsResize(deviceMountPath string) (bool, error) {
+	return true, nil
+}
+
 func (mounter *SafeFormatAndMount) rescan(devicePath string) error {
 	return nil
 }
@@ -102,3 +108,15 @@ func IsInTransitEncryptionPackageInstalled(mounter Interface) (bool, error) {
 func (mounter *Mounter) UnmountWithEncrypt(target string) error {
 	return nil
 }
+
+func GetFilesystemStats(path string) (*FilesystemStats, error) {
+	return nil, errors.New("GetFilesystemStats is not supported on this platform")
+}
+
+func GetBlockDeviceSizeBytes(path string) (int64, error) {
+	return -1, errors.New("GetBlockDeviceSizeBytes is not supported on this platform")
+}
+
+func IsCorruptedMnt(err error) bool {
+	return false
+}
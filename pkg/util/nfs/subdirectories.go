@@ -0,0 +1,294 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	batchclient "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+const (
+	// ArchivedPrefix is prepended to the name of the subdirectories that are
+	// archived instead of removed.
+	ArchivedPrefix = "archived-"
+
+	// JobImageEnv names the environment variable overriding the image of the
+	// jobs that create and delete the subdirectories. The image needs a shell
+	// and the coreutils.
+	JobImageEnv = "SUBDIRECTORY_JOB_IMAGE"
+	// DefaultJobImage is used when JobImageEnv isn't set. Deployments should
+	// set JobImageEnv to an image pinned by digest.
+	DefaultJobImage = "oraclelinux:9-slim"
+
+	// defaultJobNamespace is used when the POD_NAMESPACE environment variable
+	// isn't set.
+	defaultJobNamespace = "kube-system"
+
+	jobNamePrefix = "oci-fss-subdirectory-"
+	jobLabel      = "app.kubernetes.io/managed-by"
+	jobLabelValue = "oci-fss-subdirectories"
+	exportMount   = "/export"
+
+	jobBackoffLimit    int32 = 2
+	jobDeadlineSeconds int64 = 300
+	jobTTLSeconds      int32 = 600
+	jobPollInterval          = 2 * time.Second
+
+	createSubdirectory = "create"
+	deleteSubdirectory = "delete"
+)
+
+// The scripts run by the jobs, with the subdirectory name and the other
+// arguments passed as positional parameters rather than in the script.
+const (
+	createSubdirectoryScript = `set -e
+dir="` + exportMount + `/$1"
+if [ -e "$dir" ] || [ -L "$dir" ]; then echo "subdirectory $1 already exists"; exit 0; fi
+mkdir "$dir"
+chmod "$2" "$dir"
+chown "$3:$4" "$dir"
+echo "created subdirectory $1"`
+	deleteSubdirectoryScript = `set -e
+dir="` + exportMount + `/$1"
+if [ ! -e "$dir" ] && [ ! -L "$dir" ]; then echo "subdirectory $1 not found"; exit 0; fi
+if [ "$2" = "true" ]; then mv -T "$dir" "` + exportMount + `/` + ArchivedPrefix + `$1"; echo "archived subdirectory $1"; exit 0; fi
+rm -rf "$dir"
+echo "removed subdirectory $1"`
+)
+
+// Subdirectories creates and deletes the subdirectories of a shared NFS export
+// that volumes are provisioned as.
+type Subdirectories interface {
+	// Create creates the subdirectory of the export with the given mode and
+	// ownership. An existing subdirectory is left as it is.
+	Create(ctx context.Context, server, exportPath, name string, mode os.FileMode, uid, gid int) error
+
+	// Delete removes the subdirectory of the export, or renames it with the
+	// ArchivedPrefix if archive is set. A missing subdirectory is not an error.
+	Delete(ctx context.Context, server, exportPath, name string, archive bool) error
+}
+
+// jobSubdirectories implements Subdirectories with a Kubernetes job for each
+// operation. The kubelet mounts the export into the job's pod as an nfs
+// volume, so neither the job nor the caller has to run privileged.
+type jobSubdirectories struct {
+	jobs         batchclient.JobInterface
+	namespace    string
+	image        string
+	logger       *zap.SugaredLogger
+	pollInterval time.Duration
+}
+
+// NewSubdirectories creates a new Subdirectories running its jobs in the
+// namespace of the pod, given by the POD_NAMESPACE environment variable, with
+// the image given by JobImageEnv.
+func NewSubdirectories(logger *zap.SugaredLogger, kubeClient kubernetes.Interface) Subdirectories {
+	namespace, ok := os.LookupEnv("POD_NAMESPACE")
+	if !ok {
+		namespace = defaultJobNamespace
+	}
+	image, ok := os.LookupEnv(JobImageEnv)
+	if !ok {
+		image = DefaultJobImage
+	}
+	if !strings.Contains(image, "@sha256:") {
+		logger.With("image", image).Warnf("The subdirectory job image isn't pinned by digest, set %s to <image>@sha256:<digest>.", JobImageEnv)
+	}
+	return newJobSubdirectories(logger, kubeClient.BatchV1().Jobs(namespace), namespace, image)
+}
+
+func newJobSubdirectories(logger *zap.SugaredLogger, jobs batchclient.JobInterface, namespace, image string) *jobSubdirectories {
+	return &jobSubdirectories{
+		jobs:         jobs,
+		namespace:    namespace,
+		image:        image,
+		logger:       logger,
+		pollInterval: jobPollInterval,
+	}
+}
+
+// ValidateName checks that the name is a single path element, so that a
+// subdirectory can't point outside of the export.
+func ValidateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid subdirectory name %q", name)
+	}
+	return nil
+}
+
+// ParseMode parses the octal permission mode of the subdirectories.
+func ParseMode(mode string) (os.FileMode, error) {
+	m, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || m > 0777 {
+		return 0, fmt.Errorf("invalid permission mode %q, it must be octal such as 0770", mode)
+	}
+	return os.FileMode(m), nil
+}
+
+// ParseID parses the uid or gid owning the subdirectories.
+func ParseID(id string) (int, error) {
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid uid or gid %q, it must be a non-negative integer", id)
+	}
+	return i, nil
+}
+
+func (s *jobSubdirectories) Create(ctx context.Context, server, exportPath, name string, mode os.FileMode, uid, gid int) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	logger := s.logger.With("server", server, "exportPath", exportPath, "subdirectory", name)
+	args := []string{name, fmt.Sprintf("%o", mode.Perm()), strconv.Itoa(uid), strconv.Itoa(gid)}
+	if err := s.runJob(ctx, logger, createSubdirectory, server, exportPath, createSubdirectoryScript, args); err != nil {
+		return fmt.Errorf("failed to create subdirectory %s: %v", name, err)
+	}
+	logger.With("mode", mode, "uid", uid, "gid", gid).Info("Created subdirectory.")
+	return nil
+}
+
+func (s *jobSubdirectories) Delete(ctx context.Context, server, exportPath, name string, archive bool) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	logger := s.logger.With("server", server, "exportPath", exportPath, "subdirectory", name, "archive", archive)
+	args := []string{name, strconv.FormatBool(archive)}
+	if err := s.runJob(ctx, logger, deleteSubdirectory, server, exportPath, deleteSubdirectoryScript, args); err != nil {
+		return fmt.Errorf("failed to delete subdirectory %s: %v", name, err)
+	}
+	logger.Info("Deleted subdirectory.")
+	return nil
+}
+
+// runJob runs the script in a job mounting the export and waits for the job
+// to finish. The job name is derived from the operation and its arguments, so
+// that a retried operation waits for the job that is already running.
+func (s *jobSubdirectories) runJob(ctx context.Context, logger *zap.SugaredLogger, operation, server, exportPath, script string, args []string) error {
+	job := s.newJob(operation, server, exportPath, script, args)
+	logger = logger.With("job", s.namespace+"/"+job.Name)
+
+	_, err := s.jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create job %s: %v", job.Name, err)
+	}
+	if err == nil {
+		logger.Info("Created subdirectory job.")
+	}
+
+	var failed bool
+	err = wait.PollImmediateUntil(s.pollInterval, func() (bool, error) {
+		j, err := s.jobs.Get(ctx, job.Name, metav1.GetOptions{})
+		if err != nil {
+			logger.With(zap.Error(err)).Warn("Failed to get subdirectory job, will retry.")
+			return false, nil
+		}
+		for _, c := range j.Status.Conditions {
+			if c.Status != v1.ConditionTrue {
+				continue
+			}
+			switch c.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				failed = true
+				return true, nil
+			}
+		}
+		return false, nil
+	}, ctx.Done())
+	if err != nil {
+		// The job is left running and picked up again by the next attempt.
+		return fmt.Errorf("job %s didn't finish: %v", job.Name, err)
+	}
+
+	// Failed jobs are removed too, so that the next attempt runs a new one.
+	propagation := metav1.DeletePropagationBackground
+	if err := s.jobs.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		logger.With(zap.Error(err)).Warn("Failed to delete subdirectory job, it is removed once its TTL expires.")
+	}
+	if failed {
+		return fmt.Errorf("job %s failed", job.Name)
+	}
+	return nil
+}
+
+func (s *jobSubdirectories) newJob(operation, server, exportPath, script string, args []string) *batchv1.Job {
+	hash := sha256.Sum256([]byte(strings.Join(append([]string{operation, server, exportPath}, args...), "\x00")))
+	name := fmt.Sprintf("%s%s-%x", jobNamePrefix, operation, hash[:8])
+
+	backoffLimit := jobBackoffLimit
+	deadline := jobDeadlineSeconds
+	ttl := jobTTLSeconds
+	runAsRoot := int64(0)
+	allowPrivilegeEscalation := false
+	automountToken := false
+	labels := map[string]string{jobLabel: jobLabelValue}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					RestartPolicy:                v1.RestartPolicyNever,
+					AutomountServiceAccountToken: &automountToken,
+					Volumes: []v1.Volume{{
+						Name: "export",
+						VolumeSource: v1.VolumeSource{
+							NFS: &v1.NFSVolumeSource{Server: server, Path: exportPath},
+						},
+					}},
+					Containers: []v1.Container{{
+						Name:         "subdirectory",
+						Image:        s.image,
+						Command:      append([]string{"/bin/sh", "-c", script, "sh"}, args...),
+						VolumeMounts: []v1.VolumeMount{{Name: "export", MountPath: exportMount}},
+						// Root without privileges, with the capabilities
+						// needed to manage files owned by other users.
+						SecurityContext: &v1.SecurityContext{
+							RunAsUser:                &runAsRoot,
+							AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+							Capabilities: &v1.Capabilities{
+								Drop: []v1.Capability{"ALL"},
+								Add:  []v1.Capability{"CHOWN", "DAC_OVERRIDE", "FOWNER"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
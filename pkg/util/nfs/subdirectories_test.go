@@ -0,0 +1,302 @@
+// Copyright 2022 Oracle and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	batchclient "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+// fakeJobs runs the script of the created jobs with sh against a local
+// directory standing in for the export.
+type fakeJobs struct {
+	batchclient.JobInterface
+	t       *testing.T
+	export  string
+	jobs    map[string]*batchv1.Job
+	created []*batchv1.Job
+	deleted []string
+}
+
+func (f *fakeJobs) Create(ctx context.Context, job *batchv1.Job, opts metav1.CreateOptions) (*batchv1.Job, error) {
+	if _, ok := f.jobs[job.Name]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Group: "batch", Resource: "jobs"}, job.Name)
+	}
+	f.created = append(f.created, job)
+
+	command := job.Spec.Template.Spec.Containers[0].Command
+	script := strings.ReplaceAll(command[2], exportMount, f.export)
+	condition := batchv1.JobComplete
+	if out, err := exec.Command(command[0], append([]string{"-c", script}, command[3:]...)...).CombinedOutput(); err != nil {
+		f.t.Logf("job %s failed: %v: %s", job.Name, err, out)
+		condition = batchv1.JobFailed
+	}
+	finished := job.DeepCopy()
+	finished.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: v1.ConditionTrue}}
+	f.jobs[job.Name] = finished
+	return job, nil
+}
+
+func (f *fakeJobs) Get(ctx context.Context, name string, opts metav1.GetOptions) (*batchv1.Job, error) {
+	job, ok := f.jobs[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, name)
+	}
+	return job, nil
+}
+
+func (f *fakeJobs) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	f.deleted = append(f.deleted, name)
+	delete(f.jobs, name)
+	return nil
+}
+
+func newFakeSubdirectories(t *testing.T) (*jobSubdirectories, *fakeJobs, func()) {
+	export, err := ioutil.TempDir("", "nfs-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := &fakeJobs{t: t, export: export, jobs: map[string]*batchv1.Job{}}
+	s := newJobSubdirectories(zap.S(), jobs, "kube-system", DefaultJobImage)
+	s.pollInterval = time.Millisecond
+	return s, jobs, func() { os.RemoveAll(export) }
+}
+
+func exportEntries(t *testing.T, export string) []string {
+	entries, err := ioutil.ReadDir(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCreate(t *testing.T) {
+	s, jobs, cleanup := newFakeSubdirectories(t)
+	defer cleanup()
+
+	if err := s.Create(context.Background(), "10.0.10.1", "/shared", "pvc-1", 0770, os.Getuid(), os.Getgid()); err != nil {
+		t.Fatalf("Create() => unexpected error: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(jobs.export, "pvc-1"))
+	if err != nil {
+		t.Fatalf("Create() => subdirectory not created: %v", err)
+	}
+	if info.Mode().Perm() != 0770 {
+		t.Errorf("Create() => subdirectory mode %v, expected %v", info.Mode().Perm(), os.FileMode(0770))
+	}
+
+	if len(jobs.created) != 1 {
+		t.Fatalf("Create() => created %d jobs, expected 1", len(jobs.created))
+	}
+	job := jobs.created[0]
+	nfs := job.Spec.Template.Spec.Volumes[0].NFS
+	if nfs == nil || nfs.Server != "10.0.10.1" || nfs.Path != "/shared" {
+		t.Errorf("Create() => job mounts %+v, expected 10.0.10.1:/shared", nfs)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if container.Image != DefaultJobImage {
+		t.Errorf("Create() => job image %s, expected %s", container.Image, DefaultJobImage)
+	}
+	if sc := container.SecurityContext; sc.Privileged != nil && *sc.Privileged || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+		t.Errorf("Create() => job container may be privileged: %+v", sc)
+	}
+	expectedArgs := []string{"pvc-1", "770", strconv.Itoa(os.Getuid()), strconv.Itoa(os.Getgid())}
+	if args := container.Command[4:]; strings.Join(args, " ") != strings.Join(expectedArgs, " ") {
+		t.Errorf("Create() => job arguments %v, expected %v", args, expectedArgs)
+	}
+	if len(jobs.deleted) != 1 || jobs.deleted[0] != job.Name {
+		t.Errorf("Create() => deleted jobs %v, expected %s", jobs.deleted, job.Name)
+	}
+
+	// Creating the subdirectory again is a no-op.
+	if err := os.Chmod(filepath.Join(jobs.export, "pvc-1"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Create(context.Background(), "10.0.10.1", "/shared", "pvc-1", 0770, os.Getuid(), os.Getgid()); err != nil {
+		t.Errorf("Create() => unexpected error for existing subdirectory: %v", err)
+	}
+	if info, _ := os.Stat(filepath.Join(jobs.export, "pvc-1")); info.Mode().Perm() != 0700 {
+		t.Errorf("Create() => existing subdirectory mode changed to %v", info.Mode().Perm())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	testCases := map[string]struct {
+		archive  bool
+		expected []string
+	}{
+		"subdirectory is removed":  {expected: []string{"other"}},
+		"subdirectory is archived": {archive: true, expected: []string{"archived-pvc-1", "other"}},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s, jobs, cleanup := newFakeSubdirectories(t)
+			defer cleanup()
+			for _, d := range []string{"pvc-1", "other"} {
+				if err := os.Mkdir(filepath.Join(jobs.export, d), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := ioutil.WriteFile(filepath.Join(jobs.export, "pvc-1", "data"), []byte("data"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Delete(context.Background(), "10.0.10.1", "/shared", "pvc-1", tt.archive); err != nil {
+				t.Fatalf("Delete() => unexpected error: %v", err)
+			}
+			if names := exportEntries(t, jobs.export); strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Delete() => export contains %v, expected %v", names, tt.expected)
+			}
+
+			// Deleting the subdirectory again is a no-op.
+			if err := s.Delete(context.Background(), "10.0.10.1", "/shared", "pvc-1", tt.archive); err != nil {
+				t.Errorf("Delete() => unexpected error for missing subdirectory: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteFailedJob(t *testing.T) {
+	s, jobs, cleanup := newFakeSubdirectories(t)
+	defer cleanup()
+	// Archiving fails because the archived subdirectory exists and isn't
+	// empty.
+	for _, d := range []string{"pvc-1", "archived-pvc-1"} {
+		if err := os.Mkdir(filepath.Join(jobs.export, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(jobs.export, "archived-pvc-1", "data"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(context.Background(), "10.0.10.1", "/shared", "pvc-1", true); err == nil {
+		t.Fatalf("Delete() => expected an error")
+	}
+	if len(jobs.jobs) != 0 {
+		t.Errorf("Delete() => failed job not deleted")
+	}
+	if names := exportEntries(t, jobs.export); strings.Join(names, ",") != "archived-pvc-1,pvc-1" {
+		t.Errorf("Delete() => export contains %v", names)
+	}
+}
+
+func TestRunJobWaitsForExistingJob(t *testing.T) {
+	s, jobs, cleanup := newFakeSubdirectories(t)
+	defer cleanup()
+
+	// A job left running by an earlier attempt is waited for rather than
+	// created again.
+	job := s.newJob(deleteSubdirectory, "10.0.10.1", "/shared", deleteSubdirectoryScript, []string{"pvc-1", "false"})
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+	jobs.jobs[job.Name] = job
+
+	if err := s.Delete(context.Background(), "10.0.10.1", "/shared", "pvc-1", false); err != nil {
+		t.Fatalf("Delete() => unexpected error: %v", err)
+	}
+	if len(jobs.created) != 0 {
+		t.Errorf("Delete() => created %d jobs, expected none", len(jobs.created))
+	}
+	if len(jobs.deleted) != 1 || jobs.deleted[0] != job.Name {
+		t.Errorf("Delete() => deleted jobs %v, expected %s", jobs.deleted, job.Name)
+	}
+}
+
+func TestRunJobTimeout(t *testing.T) {
+	s, jobs, cleanup := newFakeSubdirectories(t)
+	defer cleanup()
+
+	job := s.newJob(deleteSubdirectory, "10.0.10.1", "/shared", deleteSubdirectoryScript, []string{"pvc-1", "false"})
+	jobs.jobs[job.Name] = job
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Delete(ctx, "10.0.10.1", "/shared", "pvc-1", false); err == nil {
+		t.Fatalf("Delete() => expected an error")
+	}
+	if len(jobs.deleted) != 0 {
+		t.Errorf("Delete() => deleted the running job")
+	}
+}
+
+func TestJobNames(t *testing.T) {
+	s, _, cleanup := newFakeSubdirectories(t)
+	defer cleanup()
+
+	remove := s.newJob(deleteSubdirectory, "10.0.10.1", "/shared", deleteSubdirectoryScript, []string{"pvc-1", "false"})
+	if again := s.newJob(deleteSubdirectory, "10.0.10.1", "/shared", deleteSubdirectoryScript, []string{"pvc-1", "false"}); again.Name != remove.Name {
+		t.Errorf("newJob() => %s, expected the same name %s", again.Name, remove.Name)
+	}
+	if archive := s.newJob(deleteSubdirectory, "10.0.10.1", "/shared", deleteSubdirectoryScript, []string{"pvc-1", "true"}); archive.Name == remove.Name {
+		t.Errorf("newJob() => same name %s for different arguments", archive.Name)
+	}
+	if len(remove.Name) > 63 {
+		t.Errorf("newJob() => name %s is longer than a label value", remove.Name)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "a/b", "../pvc-1"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) => expected an error", name)
+		}
+	}
+	if err := ValidateName("pvc-1"); err != nil {
+		t.Errorf("ValidateName(pvc-1) => unexpected error: %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	testCases := map[string]struct {
+		expected os.FileMode
+		wantErr  bool
+	}{
+		"0770": {expected: 0770},
+		"755":  {expected: 0755},
+		"0999": {wantErr: true},
+		"1777": {wantErr: true},
+		"rwx":  {wantErr: true},
+	}
+	for mode, tt := range testCases {
+		m, err := ParseMode(mode)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("ParseMode(%s) => unexpected error: %v", mode, err)
+		}
+		if m != tt.expected {
+			t.Errorf("ParseMode(%s) => %v, expected %v", mode, m, tt.expected)
+		}
+	}
+}
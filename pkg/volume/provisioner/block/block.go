@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/v6/controller"
 
+	csi_util "github.com/oracle/oci-cloud-controller-manager/pkg/csi-util"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/metrics"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/util"
@@ -98,19 +99,11 @@ func NewBlockProvisioner(
 	}
 }
 
-func resolveFSType(options controller.ProvisionOptions) string {
+// resolveFSType returns the filesystem type requested by the storage class,
+// ext4 when none is requested.
+func resolveFSType(options controller.ProvisionOptions) (string, error) {
 	fsType, _ := options.StorageClass.Parameters[FSType]
-
-	defaultFsType := "ext4"
-	if fsType == "ext4" || fsType == "ext3" {
-		return fsType
-	} else if fsType != "" {
-		//TODO: Remove this code when we support other than ext4 || ext3.
-		return defaultFsType
-	} else {
-		//No fsType provided returning ext4
-		return defaultFsType
-	}
+	return csi_util.ValidateFsType(fsType)
 }
 
 func roundUpSize(volumeSizeBytes int64, allocationUnitBytes int64) int64 {
@@ -138,6 +131,11 @@ func (block *blockProvisioner) Provision(options controller.ProvisionOptions, ad
 		}
 	}
 
+	filesystemType, err := resolveFSType(options)
+	if err != nil {
+		return nil, err
+	}
+
 	var errorType string
 	var fvdMetricDimension string
 
@@ -237,8 +235,6 @@ func (block *blockProvisioner) Provision(options controller.ProvisionOptions, ad
 		return nil, errors.Wrap(err, "waiting for volume to become available")
 	}
 
-	filesystemType := resolveFSType(options)
-
 	pv := &v1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: *volume.Id,
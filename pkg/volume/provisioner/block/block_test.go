@@ -69,6 +69,10 @@ func (c *MockBlockStorageClient) GetVolumesByName(ctx context.Context, volumeNam
 	return nil, nil
 }
 
+func (c *MockBlockStorageClient) ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error) {
+	return nil, nil, nil
+}
+
 // CreateVolume mocks the BlockStorage CreateVolume implementation
 func (c *MockBlockStorageClient) CreateVolume(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error) {
 	return &core.Volume{Id: &VolumeBackupID}, nil
@@ -83,6 +87,26 @@ func (c *MockBlockStorageClient) DeleteVolume(ctx context.Context, id string) er
 	return nil
 }
 
+func (c *MockBlockStorageClient) CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error) {
+	return nil, nil, nil
+}
+
+func (c *MockBlockStorageClient) DeleteVolumeBackup(ctx context.Context, id string) error {
+	return nil
+}
+
 // MockFileStorageClient mocks FileStorage client implementation.
 type MockFileStorageClient struct{}
 
@@ -155,6 +179,10 @@ func (c *MockFileStorageClient) FindExport(ctx context.Context, compartmentID, f
 	}, nil
 }
 
+func (c *MockFileStorageClient) ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]filestorage.ExportSummary, error) {
+	return nil, nil
+}
+
 // DeleteExport mocks the FileStorage DeleteExport implementation
 func (c *MockFileStorageClient) DeleteExport(ctx context.Context, id string) error {
 	return nil
@@ -191,11 +219,11 @@ func (c *MockComputeClient) FindVolumeAttachment(ctx context.Context, compartmen
 	return nil, nil
 }
 
-func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error) {
+func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
-func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error) {
+func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
@@ -213,6 +241,18 @@ func (c *MockComputeClient) FindActiveVolumeAttachment(ctx context.Context, comp
 	return nil, nil
 }
 
+func (c *MockComputeClient) FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error) {
+	return nil, nil
+}
+
 // MockVirtualNetworkClient mocks VirtualNetwork client implementation
 type MockVirtualNetworkClient struct {
 }
@@ -293,6 +333,10 @@ func (p *MockProvisionerClient) Identity() client.IdentityInterface {
 	return &MockIdentityClient{}
 }
 
+func (p *MockProvisionerClient) Limits() client.LimitsInterface {
+	return nil
+}
+
 // FSS mocks client FileStorage implementation
 func (p *MockProvisionerClient) FSS() client.FileStorageInterface {
 	return &MockFileStorageClient{}
@@ -339,6 +383,18 @@ func (c *MockLoadBalancerClient) CreateCertificate(ctx context.Context, lbID str
 	return "", nil
 }
 
+func (c *MockLoadBalancerClient) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
 func (c *MockLoadBalancerClient) CreateBackendSet(ctx context.Context, lbID string, name string, details *client.GenericBackendSetDetails) (string, error) {
 	return "", nil
 }
@@ -394,7 +450,10 @@ func TestResolveFSTypeWhenNotConfigured(t *testing.T) {
 		StorageClass: &storageClass,
 	}
 	// test default fsType of 'ext4' is always returned.
-	fst := resolveFSType(provisionerOptions)
+	fst, err := resolveFSType(provisionerOptions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if fst != "ext4" {
 		t.Fatalf("Unexpected filesystem type: '%s'.", fst)
 	}
@@ -408,12 +467,28 @@ func TestResolveFSTypeWhenConfigured(t *testing.T) {
 	provisionerOptions := controller.ProvisionOptions{
 		StorageClass: &storageClass,
 	}
-	fst := resolveFSType(provisionerOptions)
+	fst, err := resolveFSType(provisionerOptions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if fst != "ext3" {
 		t.Fatalf("Unexpected filesystem type: '%s'.", fst)
 	}
 }
 
+func TestResolveFSTypeWhenUnsupported(t *testing.T) {
+	// test an unsupported fsType is rejected rather than replaced with 'ext4'.
+	storageClass := v12.StorageClass{
+		Parameters: map[string]string{FSType: "ntfs"},
+	}
+	provisionerOptions := controller.ProvisionOptions{
+		StorageClass: &storageClass,
+	}
+	if fst, err := resolveFSType(provisionerOptions); err == nil {
+		t.Fatalf("Expected an error for unsupported filesystem type, got '%s'.", fst)
+	}
+}
+
 func TestCreateVolumeFromBackup(t *testing.T) {
 	// test creating a volume from an existing backup
 
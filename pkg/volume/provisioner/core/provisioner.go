@@ -151,7 +151,7 @@ func NewOCIProvisioner(logger *zap.SugaredLogger, kubeClient kubernetes.Interfac
 			minVolumeSize,
 		)
 	case ProvisionerNameFss:
-		provisioner = fss.NewFilesystemProvisioner(logger, client, region, cfg.CompartmentID)
+		provisioner = fss.NewFilesystemProvisioner(logger, client, kubeClient, region, cfg.CompartmentID)
 	default:
 		return nil, errors.Errorf("invalid provisioner type %q", provisionerType)
 	}
@@ -18,9 +18,14 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"os"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/oracle/oci-cloud-controller-manager/pkg/oci/client"
+	"github.com/oracle/oci-cloud-controller-manager/pkg/util/nfs"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/volume/provisioner"
 	"github.com/oracle/oci-cloud-controller-manager/pkg/volume/provisioner/plugin"
 	fss "github.com/oracle/oci-go-sdk/v50/filestorage"
@@ -29,6 +34,7 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/v6/controller"
 )
 
@@ -41,6 +47,26 @@ const (
 
 	// MntTargetID is the name of the parameter which hold the target mount target ocid.
 	MntTargetID = "mntTargetId"
+
+	// FileSystemID is the name of the parameter which holds the ocid of an
+	// existing file system. The volumes are then provisioned as subdirectories
+	// of the file system rather than as file systems of their own.
+	FileSystemID = "fileSystemId"
+
+	// The parameters of the volumes that are subdirectories of a shared file
+	// system.
+	exportPathParameter      = "exportPath"
+	archiveOnDeleteParameter = "archiveOnDelete"
+	directoryModeParameter   = "directoryMode"
+	directoryUIDParameter    = "directoryUid"
+	directoryGIDParameter    = "directoryGid"
+
+	// ociSubdirectory and ociArchiveOnDelete annotate the PVs of the volumes
+	// that are subdirectories of a shared file system.
+	ociSubdirectory    = "volume.beta.kubernetes.io/oci-fss-subdirectory"
+	ociArchiveOnDelete = "volume.beta.kubernetes.io/oci-fss-archive-on-delete"
+
+	defaultDirectoryMode os.FileMode = 0777
 )
 
 const (
@@ -58,6 +84,10 @@ type filesystemProvisioner struct {
 	// is located.
 	compartmentID string
 
+	// subdirectories provisions the volumes that are subdirectories of a
+	// shared file system.
+	subdirectories nfs.Subdirectories
+
 	logger *zap.SugaredLogger
 }
 
@@ -69,13 +99,15 @@ var (
 )
 
 // NewFilesystemProvisioner creates a new file system provisioner that creates
-// filesystems using OCI File System Service.
-func NewFilesystemProvisioner(logger *zap.SugaredLogger, client client.Interface, region, compartmentID string) plugin.ProvisionerPlugin {
+// filesystems using OCI File System Service. The subdirectories of shared
+// file systems are created and deleted by jobs in the cluster.
+func NewFilesystemProvisioner(logger *zap.SugaredLogger, client client.Interface, kubeClient kubernetes.Interface, region, compartmentID string) plugin.ProvisionerPlugin {
 	return &filesystemProvisioner{
-		client:        client,
-		region:        region,
-		compartmentID: compartmentID,
-		logger:        logger,
+		client:         client,
+		region:         region,
+		compartmentID:  compartmentID,
+		subdirectories: nfs.NewSubdirectories(logger, kubeClient),
+		logger:         logger,
 	}
 }
 
@@ -199,6 +231,10 @@ func (fsp *filesystemProvisioner) Provision(options controller.ProvisionOptions,
 	}
 	logger = logger.With("privateIP", ip)
 
+	if fsID := options.StorageClass.Parameters[FileSystemID]; fsID != "" {
+		return fsp.provisionSubdirectory(ctx, logger.With("fileSystemID", fsID), options, fsID, *target.ExportSetId, ip)
+	}
+
 	logger.Info("Creating FileSystem")
 	fs, err := fsp.getOrCreateFileSystem(ctx, logger, *ad.Name, fsDisplayName)
 	if err != nil {
@@ -243,9 +279,142 @@ func (fsp *filesystemProvisioner) Provision(options controller.ProvisionOptions,
 	}, nil
 }
 
+// subdirectoryParameters holds the StorageClass parameters of the volumes
+// that are subdirectories of a shared file system.
+type subdirectoryParameters struct {
+	exportPath      string
+	archiveOnDelete bool
+	mode            os.FileMode
+	uid             int
+	gid             int
+}
+
+func getSubdirectoryParameters(parameters map[string]string) (subdirectoryParameters, error) {
+	p := subdirectoryParameters{mode: defaultDirectoryMode}
+	var err error
+	if v, ok := parameters[exportPathParameter]; ok {
+		if !strings.HasPrefix(v, "/") {
+			return p, errors.Errorf("%s must be an absolute path", exportPathParameter)
+		}
+		p.exportPath = v
+	}
+	if v, ok := parameters[archiveOnDeleteParameter]; ok {
+		if p.archiveOnDelete, err = strconv.ParseBool(v); err != nil {
+			return p, errors.Errorf("%s must be a boolean value", archiveOnDeleteParameter)
+		}
+	}
+	if v, ok := parameters[directoryModeParameter]; ok {
+		if p.mode, err = nfs.ParseMode(v); err != nil {
+			return p, errors.Wrap(err, directoryModeParameter)
+		}
+	}
+	if v, ok := parameters[directoryUIDParameter]; ok {
+		if p.uid, err = nfs.ParseID(v); err != nil {
+			return p, errors.Wrap(err, directoryUIDParameter)
+		}
+	}
+	if v, ok := parameters[directoryGIDParameter]; ok {
+		if p.gid, err = nfs.ParseID(v); err != nil {
+			return p, errors.Wrap(err, directoryGIDParameter)
+		}
+	}
+	return p, nil
+}
+
+// provisionSubdirectory provisions the volume as a subdirectory, named after
+// the PV, of the existing file system exported on the mount target.
+func (fsp *filesystemProvisioner) provisionSubdirectory(ctx context.Context, logger *zap.SugaredLogger, options controller.ProvisionOptions, fsID, exportSetID, ip string) (*v1.PersistentVolume, error) {
+	params, err := getSubdirectoryParameters(options.StorageClass.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := fsp.client.FSS().GetFileSystem(ctx, fsID)
+	if err != nil {
+		logger.With(zap.Error(err)).Error("Failed to retrieve file system")
+		return nil, err
+	}
+	if fs.LifecycleState != fss.FileSystemLifecycleStateActive {
+		return nil, errors.Errorf("file system %q is %s", fsID, fs.LifecycleState)
+	}
+
+	exportPath := params.exportPath
+	if exportPath == "" {
+		summary, err := fsp.client.FSS().FindExport(ctx, fsp.compartmentID, fsID, exportSetID)
+		if err != nil && !client.IsNotFound(err) {
+			return nil, err
+		}
+		if summary == nil {
+			return nil, errors.Errorf("file system %q isn't exported on the mount target", fsID)
+		}
+		export, err := fsp.client.FSS().AwaitExportActive(ctx, logger, *summary.Id)
+		if err != nil {
+			return nil, err
+		}
+		exportPath = *export.Path
+	}
+	logger = logger.With("exportPath", exportPath, "subdirectory", options.PVName)
+
+	if err := fsp.subdirectories.Create(ctx, ip, exportPath, options.PVName, params.mode, params.uid, params.gid); err != nil {
+		logger.With(zap.Error(err)).Error("Failed to create subdirectory")
+		return nil, err
+	}
+	logger.Info("Subdirectory provisioned")
+
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: options.PVName,
+			Annotations: map[string]string{
+				ociVolumeID:        fsID,
+				ociSubdirectory:    options.PVName,
+				ociArchiveOnDelete: strconv.FormatBool(params.archiveOnDelete),
+			},
+			Labels: map[string]string{plugin.LabelZoneRegion: fsp.region},
+		},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: *options.StorageClass.ReclaimPolicy,
+			AccessModes:                   options.PVC.Spec.AccessModes,
+			//NOTE: fs storage doesn't enforce quota, capacity is meaningless here.
+			Capacity: v1.ResourceList{
+				v1.ResourceName(v1.ResourceStorage): options.PVC.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)],
+			},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				NFS: &v1.NFSVolumeSource{
+					Server:   ip,
+					Path:     path.Join(exportPath, options.PVName),
+					ReadOnly: isReadOnly(options.PVC.Spec.AccessModes),
+				},
+			},
+			MountOptions: options.StorageClass.MountOptions,
+		},
+	}, nil
+}
+
+// deleteSubdirectory removes or archives the subdirectory of the volume. The
+// shared file system and its export are left alone.
+func (fsp *filesystemProvisioner) deleteSubdirectory(ctx context.Context, volume *v1.PersistentVolume, subdirectory string) error {
+	if volume.Spec.NFS == nil {
+		return errors.Errorf("PV %q has no NFS volume source", volume.Name)
+	}
+	archive, _ := strconv.ParseBool(volume.Annotations[ociArchiveOnDelete])
+	exportPath := path.Dir(volume.Spec.NFS.Path)
+	fsp.logger.With(
+		"fileSystemID", volume.Annotations[ociVolumeID],
+		"exportPath", exportPath,
+		"subdirectory", subdirectory,
+		"archive", archive,
+	).Info("Deleting subdirectory")
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return fsp.subdirectories.Delete(ctx, volume.Spec.NFS.Server, exportPath, subdirectory, archive)
+}
+
 // Delete terminates the OCI resources associated with the given PVC.
 func (fsp *filesystemProvisioner) Delete(volume *v1.PersistentVolume) error {
 	ctx := context.Background()
+	if subdirectory := volume.Annotations[ociSubdirectory]; subdirectory != "" {
+		return fsp.deleteSubdirectory(ctx, volume, subdirectory)
+	}
 	exportID := volume.Annotations[ociExportID]
 	if exportID == "" {
 		return errors.Errorf("%q annotation not found on PV", ociExportID)
@@ -16,6 +16,8 @@ package fss
 
 import (
 	"context"
+	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -69,6 +71,10 @@ func (c *MockBlockStorageClient) GetVolumesByName(ctx context.Context, volumeNam
 	return nil, nil
 }
 
+func (c *MockBlockStorageClient) ListVolumes(ctx context.Context, compartmentID string, limit int, page *string) ([]core.Volume, *string, error) {
+	return nil, nil, nil
+}
+
 // CreateVolume mocks the BlockStorage CreateVolume implementation
 func (c *MockBlockStorageClient) CreateVolume(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error) {
 	return &core.Volume{Id: &VolumeBackupID}, nil
@@ -83,6 +89,26 @@ func (c *MockBlockStorageClient) DeleteVolume(ctx context.Context, id string) er
 	return nil
 }
 
+func (c *MockBlockStorageClient) CreateVolumeBackup(ctx context.Context, details core.CreateVolumeBackupDetails) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackup(ctx context.Context, id string) (*core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) GetVolumeBackupsByName(ctx context.Context, snapshotName, compartmentID string) ([]core.VolumeBackup, error) {
+	return nil, nil
+}
+
+func (c *MockBlockStorageClient) ListVolumeBackups(ctx context.Context, compartmentID, volumeID string, limit int, page *string) ([]core.VolumeBackup, *string, error) {
+	return nil, nil, nil
+}
+
+func (c *MockBlockStorageClient) DeleteVolumeBackup(ctx context.Context, id string) error {
+	return nil
+}
+
 // MockFileStorageClient mocks FileStorage client implementation.
 type MockFileStorageClient struct{}
 
@@ -155,6 +181,10 @@ func (c *MockFileStorageClient) FindExport(ctx context.Context, compartmentID, f
 	}, nil
 }
 
+func (c *MockFileStorageClient) ListExportsForFileSystem(ctx context.Context, compartmentID, fsID string) ([]filestorage.ExportSummary, error) {
+	return nil, nil
+}
+
 // DeleteExport mocks the FileStorage DeleteExport implementation
 func (c *MockFileStorageClient) DeleteExport(ctx context.Context, id string) error {
 	return nil
@@ -191,11 +221,11 @@ func (c *MockComputeClient) FindVolumeAttachment(ctx context.Context, compartmen
 	return nil, nil
 }
 
-func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled bool) (core.VolumeAttachment, error) {
+func (MockComputeClient) AttachParavirtualizedVolume(ctx context.Context, instanceID, volumeID string, isPvEncryptionInTransitEnabled, isShareable, isReadOnly bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
-func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string) (core.VolumeAttachment, error) {
+func (c *MockComputeClient) AttachVolume(ctx context.Context, instanceID, volumeID string, isShareable, isReadOnly, useChap bool) (core.VolumeAttachment, error) {
 	return nil, nil
 }
 
@@ -213,6 +243,18 @@ func (c *MockComputeClient) FindActiveVolumeAttachment(ctx context.Context, comp
 	return nil, nil
 }
 
+func (c *MockComputeClient) FindActiveVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) FindVolumeAttachmentForInstance(ctx context.Context, compartmentID, volumeID, instanceID string) (core.VolumeAttachment, error) {
+	return nil, nil
+}
+
+func (c *MockComputeClient) ListVolumeAttachments(ctx context.Context, compartmentID, volumeID string) ([]core.VolumeAttachment, error) {
+	return nil, nil
+}
+
 // MockVirtualNetworkClient mocks VirtualNetwork client implementation
 type MockVirtualNetworkClient struct {
 }
@@ -293,6 +335,10 @@ func (p *MockProvisionerClient) Identity() client.IdentityInterface {
 	return &MockIdentityClient{}
 }
 
+func (p *MockProvisionerClient) Limits() client.LimitsInterface {
+	return nil
+}
+
 // FSS mocks client FileStorage implementation
 func (p *MockProvisionerClient) FSS() client.FileStorageInterface {
 	return &MockFileStorageClient{}
@@ -339,6 +385,18 @@ func (c *MockLoadBalancerClient) CreateCertificate(ctx context.Context, lbID str
 	return "", nil
 }
 
+func (c *MockLoadBalancerClient) DeleteCertificate(ctx context.Context, lbID, name string) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) CreateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
+func (c *MockLoadBalancerClient) UpdateSSLCipherSuite(ctx context.Context, lbID string, suite *client.GenericSslCipherSuite) (string, error) {
+	return "", nil
+}
+
 func (c *MockLoadBalancerClient) CreateBackendSet(ctx context.Context, lbID string, name string, details *client.GenericBackendSetDetails) (string, error) {
 	return "", nil
 }
@@ -412,3 +470,85 @@ func TestCreateVolumeWithFSS(t *testing.T) {
 		t.Fatalf("Failed to provision volume from fss storage: %v", err)
 	}
 }
+
+// fakeSubdirectories records the subdirectories created and deleted.
+type fakeSubdirectories struct {
+	created []string
+	deleted map[string]bool
+}
+
+func (s *fakeSubdirectories) Create(ctx context.Context, server, exportPath, name string, mode os.FileMode, uid, gid int) error {
+	s.created = append(s.created, server+":"+exportPath+"/"+name)
+	return nil
+}
+
+func (s *fakeSubdirectories) Delete(ctx context.Context, server, exportPath, name string, archive bool) error {
+	s.deleted[server+":"+exportPath+"/"+name] = archive
+	return nil
+}
+
+func TestCreateSubdirectoryVolumeWithFSS(t *testing.T) {
+	subdirectories := &fakeSubdirectories{deleted: map[string]bool{}}
+	fsp := filesystemProvisioner{
+		client:         NewClientProvisioner(nil, nil),
+		logger:         zaptest.NewLogger(t).Sugar(),
+		region:         "phx",
+		subdirectories: subdirectories,
+	}
+
+	persistentVolumeReclaimPolicy := v1.PersistentVolumeReclaimDelete
+	storageClass := v12.StorageClass{
+		Parameters: map[string]string{
+			MntTargetID:              "dummyMountTargetID",
+			FileSystemID:             fileSystemID,
+			archiveOnDeleteParameter: "true",
+			directoryModeParameter:   "0770",
+		},
+		ReclaimPolicy: &persistentVolumeReclaimPolicy,
+	}
+	pv, err := fsp.Provision(
+		controller.ProvisionOptions{
+			StorageClass: &storageClass,
+			PVName:       "pvc-my-uid",
+			PVC: &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "my-uid",
+				},
+			},
+		},
+		&identity.AvailabilityDomain{
+			Name:          common.String("dummyAdName"),
+			CompartmentId: common.String("dummyCompartmentId"),
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to provision subdirectory volume from fss storage: %v", err)
+	}
+	if expected := []string{privateIP + ":/" + fileSystemID + "/pvc-my-uid"}; !reflect.DeepEqual(subdirectories.created, expected) {
+		t.Errorf("Provision() created subdirectories %v, expected %v", subdirectories.created, expected)
+	}
+	if pv.Spec.NFS.Path != "/"+fileSystemID+"/pvc-my-uid" {
+		t.Errorf("Provision() => NFS path %q, expected the subdirectory", pv.Spec.NFS.Path)
+	}
+
+	if err := fsp.Delete(pv); err != nil {
+		t.Fatalf("Failed to delete subdirectory volume: %v", err)
+	}
+	if expected := map[string]bool{privateIP + ":/" + fileSystemID + "/pvc-my-uid": true}; !reflect.DeepEqual(subdirectories.deleted, expected) {
+		t.Errorf("Delete() deleted subdirectories %v, expected %v", subdirectories.deleted, expected)
+	}
+}
+
+func TestGetSubdirectoryParameters(t *testing.T) {
+	for _, parameters := range []map[string]string{
+		{exportPathParameter: "relative"},
+		{archiveOnDeleteParameter: "maybe"},
+		{directoryModeParameter: "0999"},
+		{directoryUIDParameter: "-1"},
+		{directoryGIDParameter: "users"},
+	} {
+		if _, err := getSubdirectoryParameters(parameters); err == nil {
+			t.Errorf("getSubdirectoryParameters(%v) => expected an error", parameters)
+		}
+	}
+}